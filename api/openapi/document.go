@@ -0,0 +1,87 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/invopop/yaml"
+)
+
+// buildDateExtension is the info extension with the build date of the served api.
+const buildDateExtension = "x-build-date"
+
+// Option is an option for Document.
+type Option func(d *Document)
+
+// WithVersion sets the version of the document, the version of the specification is kept if it is empty.
+func WithVersion(version string) Option {
+	return func(d *Document) {
+		if version != "" {
+			d.doc.Info.Version = version
+		}
+	}
+}
+
+// WithBuildDate adds the build date to the info of the document if it is not empty.
+func WithBuildDate(buildDate string) Option {
+	return func(d *Document) {
+		if buildDate != "" {
+			d.doc.Info.Extensions[buildDateExtension] = buildDate
+		}
+	}
+}
+
+// Document is the specification served to the clients with the build info of the binary.
+type Document struct {
+	doc *openapi3.T
+}
+
+// NewDocument loads the embedded specification.
+func NewDocument(opts ...Option) (Document, error) {
+	doc, err := openapi3.NewLoader().LoadFromData(Spec)
+	if err != nil {
+		return Document{}, fmt.Errorf("can not load openapi spec: %w", err)
+	}
+
+	if doc.Info.Extensions == nil {
+		doc.Info.Extensions = make(map[string]interface{})
+	}
+
+	d := Document{
+		doc: doc,
+	}
+	for _, opt := range opts {
+		opt(&d)
+	}
+
+	return d, nil
+}
+
+// JSON returns the document in json with serverURL as the only server.
+func (d Document) JSON(serverURL string) ([]byte, error) {
+	doc := *d.doc
+	doc.Servers = openapi3.Servers{{URL: serverURL}}
+
+	data, err := json.Marshal(&doc)
+	if err != nil {
+		return nil, fmt.Errorf("can not marshal openapi document: %w", err)
+	}
+
+	return data, nil
+}
+
+// YAML returns the document in yaml with serverURL as the only server.
+func (d Document) YAML(serverURL string) ([]byte, error) {
+	data, err := d.JSON(serverURL)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err = yaml.JSONToYAML(data)
+	if err != nil {
+		return nil, fmt.Errorf("can not convert openapi document to yaml: %w", err)
+	}
+
+	return data, nil
+}
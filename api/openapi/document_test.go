@@ -0,0 +1,78 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/invopop/yaml"
+	"github.com/maypok86/conduit/api/openapi"
+	"github.com/stretchr/testify/require"
+)
+
+type document struct {
+	Info struct {
+		Version   string `json:"version"`
+		BuildDate string `json:"x-build-date"`
+	} `json:"info"`
+	Servers []struct {
+		URL string `json:"url"`
+	} `json:"servers"`
+	Paths map[string]interface{} `json:"paths"`
+}
+
+func TestDocument(t *testing.T) {
+	t.Parallel()
+
+	const serverURL = "https://conduit.example.com/api"
+
+	tests := []struct {
+		name          string
+		opts          []openapi.Option
+		wantVersion   string
+		wantBuildDate string
+	}{
+		{
+			name: "build info",
+			opts: []openapi.Option{
+				openapi.WithVersion("4f2a9c1"),
+				openapi.WithBuildDate("2022-09-01T10:00:00"),
+			},
+			wantVersion:   "4f2a9c1",
+			wantBuildDate: "2022-09-01T10:00:00",
+		},
+		{
+			name:        "no build info",
+			opts:        []openapi.Option{openapi.WithVersion(""), openapi.WithBuildDate("")},
+			wantVersion: "1.0.0",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			d, err := openapi.NewDocument(tt.opts...)
+			require.NoError(t, err)
+
+			jsonData, err := d.JSON(serverURL)
+			require.NoError(t, err)
+
+			yamlData, err := d.YAML(serverURL)
+			require.NoError(t, err)
+
+			yamlJSONData, err := yaml.YAMLToJSON(yamlData)
+			require.NoError(t, err)
+			require.JSONEq(t, string(jsonData), string(yamlJSONData))
+
+			var got document
+			require.NoError(t, json.Unmarshal(jsonData, &got))
+			require.Equal(t, tt.wantVersion, got.Info.Version)
+			require.Equal(t, tt.wantBuildDate, got.Info.BuildDate)
+			require.Len(t, got.Servers, 1)
+			require.Equal(t, serverURL, got.Servers[0].URL)
+			require.Contains(t, got.Paths, "/users/login")
+		})
+	}
+}
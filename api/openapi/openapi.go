@@ -0,0 +1,9 @@
+// Package openapi embeds the OpenAPI specification of the conduit api into the binary.
+package openapi
+
+import _ "embed"
+
+// Spec is the OpenAPI specification in yaml.
+//
+//go:embed conduit.yml
+var Spec []byte
@@ -2,12 +2,15 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"os"
 
 	"github.com/maypok86/conduit/internal/app/api"
 	"github.com/maypok86/conduit/internal/config"
+	"github.com/maypok86/conduit/internal/seed"
 	"github.com/maypok86/conduit/pkg/logger"
 	"go.uber.org/zap"
 )
@@ -30,7 +33,11 @@ func run(ctx context.Context) error {
 	l := logger.New(os.Stdout, logger.WithLevel(cfg.Logger.Level))
 	l.Info("conduit", zap.String("version", version), zap.String("build_date", buildDate))
 
-	app, err := api.New(ctx, l)
+	if len(os.Args) > 1 {
+		return runCommand(ctx, l, os.Args[1:])
+	}
+
+	app, err := api.New(ctx, l, api.BuildInfo{Version: version, BuildDate: buildDate})
 	if err != nil {
 		return fmt.Errorf("failed to create app: %w", err)
 	}
@@ -41,3 +48,44 @@ func run(ctx context.Context) error {
 
 	return nil
 }
+
+var errUsage = errors.New("usage: conduit [migrate up|down|redo|status] [seed -users n -max-follows n -seed n]")
+
+func runCommand(ctx context.Context, l *zap.Logger, args []string) error {
+	switch args[0] {
+	case "migrate":
+		const migrateArgs = 2
+		if len(args) != migrateArgs {
+			return errUsage
+		}
+
+		if err := api.Migrate(ctx, l, args[1], os.Stdout); err != nil {
+			return fmt.Errorf("failed to run migrations: %w", err)
+		}
+	case "seed":
+		flags := flag.NewFlagSet("seed", flag.ContinueOnError)
+		users := flags.Int("users", 1000, "number of generated users")
+		maxFollows := flags.Int("max-follows", 100, "max number of users a single user follows")
+		randomSeed := flags.Int64("seed", 1, "seed of the generated data, the same seed generates the same data")
+		password := flags.String("password", "password", "password of every generated user")
+
+		if err := flags.Parse(args[1:]); err != nil {
+			return errUsage
+		}
+
+		if err := api.Seed(
+			ctx,
+			l,
+			seed.WithUsers(*users),
+			seed.WithMaxFollows(*maxFollows),
+			seed.WithSeed(*randomSeed),
+			seed.WithPassword(*password),
+		); err != nil {
+			return fmt.Errorf("failed to seed demo data: %w", err)
+		}
+	default:
+		return errUsage
+	}
+
+	return nil
+}
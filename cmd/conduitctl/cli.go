@@ -0,0 +1,299 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/maypok86/conduit/pkg/client"
+)
+
+type cli struct {
+	client      *client.Client
+	session     session
+	sessionPath string
+	printer     printer
+	stdin       io.Reader
+	stderr      io.Writer
+}
+
+type cliDeps struct {
+	baseURL     string
+	sessionPath string
+	printer     printer
+	stdin       io.Reader
+	stderr      io.Writer
+}
+
+func newCLI(deps cliDeps) (*cli, error) {
+	s, err := loadSession(deps.sessionPath)
+	if err != nil {
+		return nil, err
+	}
+
+	baseURL := resolveBaseURL(deps.baseURL, s)
+
+	// The token of the session is valid only for the api it was issued by.
+	var opts []client.Option
+	if s.BaseURL == baseURL {
+		opts = append(opts, client.WithToken(s.Token))
+	}
+
+	c, err := client.New(baseURL, append(opts, client.WithUserAgent("conduitctl"))...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client: %w", err)
+	}
+
+	return &cli{
+		client:      c,
+		session:     session{BaseURL: baseURL, Username: s.Username, Token: s.Token},
+		sessionPath: deps.sessionPath,
+		printer:     deps.printer,
+		stdin:       deps.stdin,
+		stderr:      deps.stderr,
+	}, nil
+}
+
+func resolveBaseURL(flagValue string, s session) string {
+	if flagValue != "" {
+		return flagValue
+	}
+
+	if env := os.Getenv(baseURLEnv); env != "" {
+		return env
+	}
+
+	if s.BaseURL != "" {
+		return s.BaseURL
+	}
+
+	return defaultBaseURL
+}
+
+func (c *cli) run(ctx context.Context, command string, args []string) error {
+	switch command {
+	case "register":
+		return c.register(ctx, args)
+	case "login":
+		return c.login(ctx, args)
+	case "logout":
+		return removeSession(c.sessionPath)
+	case "whoami":
+		return c.whoami(ctx)
+	case "profile":
+		return c.profile(ctx, args)
+	case "update-user":
+		return c.updateUser(ctx, args)
+	default:
+		return fmt.Errorf("unknown command %q: %w", command, errUsage)
+	}
+}
+
+func (c *cli) register(ctx context.Context, args []string) error {
+	flags := c.newFlagSet("register")
+	email := flags.String("email", "", "email of the user")
+	username := flags.String("username", "", "username of the user")
+	password := flags.String("password", "", "password of the user")
+
+	if err := parse(flags, args); err != nil {
+		return err
+	}
+
+	if *email == "" || *username == "" {
+		return errUsage
+	}
+
+	pass, err := c.password(*password)
+	if err != nil {
+		return err
+	}
+
+	user, err := c.client.Register(ctx, client.NewUser{
+		Email:    *email,
+		Username: *username,
+		Password: pass,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to register: %w", err)
+	}
+
+	return c.loggedIn(user)
+}
+
+func (c *cli) login(ctx context.Context, args []string) error {
+	flags := c.newFlagSet("login")
+	email := flags.String("email", "", "email of the user")
+	password := flags.String("password", "", "password of the user")
+
+	if err := parse(flags, args); err != nil {
+		return err
+	}
+
+	if *email == "" {
+		return errUsage
+	}
+
+	pass, err := c.password(*password)
+	if err != nil {
+		return err
+	}
+
+	user, err := c.client.Login(ctx, *email, pass)
+	if err != nil {
+		return fmt.Errorf("failed to log in: %w", err)
+	}
+
+	return c.loggedIn(user)
+}
+
+func (c *cli) loggedIn(user client.User) error {
+	c.session.Username = user.Username
+	c.session.Token = user.Token
+
+	if err := c.session.save(c.sessionPath); err != nil {
+		return err
+	}
+
+	return c.printer.user(user)
+}
+
+func (c *cli) whoami(ctx context.Context) error {
+	user, err := c.client.CurrentUser(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get current user: %w", err)
+	}
+
+	return c.printer.user(user)
+}
+
+func (c *cli) profile(ctx context.Context, args []string) error {
+	const profileArgs = 2
+	if len(args) != profileArgs {
+		return errUsage
+	}
+
+	action, username := args[0], args[1]
+
+	var (
+		profile client.Profile
+		err     error
+	)
+
+	switch action {
+	case "get":
+		profile, err = c.client.Profile(ctx, username)
+	case "follow":
+		profile, err = c.client.Follow(ctx, username)
+	case "unfollow":
+		profile, err = c.client.Unfollow(ctx, username)
+	default:
+		return fmt.Errorf("unknown profile command %q: %w", action, errUsage)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to %s profile: %w", action, err)
+	}
+
+	return c.printer.profile(profile)
+}
+
+func (c *cli) updateUser(ctx context.Context, args []string) error {
+	flags := c.newFlagSet("update-user")
+	email := flags.String("email", "", "new email")
+	username := flags.String("username", "", "new username")
+	bio := flags.String("bio", "", "new bio")
+	image := flags.String("image", "", "new image url")
+	private := flags.String("private", "", "make the profile private, true or false")
+
+	if err := parse(flags, args); err != nil {
+		return err
+	}
+
+	// Only the passed flags are updated, so an empty bio can be set with -bio "".
+	var update client.UpdateUser
+	flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "email":
+			update.Email = email
+		case "username":
+			update.Username = username
+		case "bio":
+			update.Bio = bio
+		case "image":
+			update.Image = image
+		}
+	})
+
+	if *private != "" {
+		value, err := strconv.ParseBool(*private)
+		if err != nil {
+			return fmt.Errorf("invalid -private %q: %w", *private, errUsage)
+		}
+
+		update.Private = &value
+	}
+
+	user, err := c.client.UpdateUser(ctx, update)
+	if err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+
+	c.session.Username = user.Username
+	if err := c.session.save(c.sessionPath); err != nil {
+		return err
+	}
+
+	if update.Email != nil {
+		fmt.Fprintln(c.stderr, "the email is changed, log in again with the new one")
+	}
+
+	return c.printer.user(user)
+}
+
+func (c *cli) newFlagSet(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(c.stderr)
+
+	return flags
+}
+
+// parse parses the flags of a command without positional arguments.
+func parse(flags *flag.FlagSet, args []string) error {
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 0 {
+		return errUsage
+	}
+
+	return nil
+}
+
+// password returns the password from the flag, the environment or the first line of stdin.
+func (c *cli) password(flagValue string) (string, error) {
+	if flagValue != "" {
+		return flagValue, nil
+	}
+
+	if env := os.Getenv(passwordEnv); env != "" {
+		return env, nil
+	}
+
+	line, err := bufio.NewReader(c.stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("can not read password from stdin: %w", err)
+	}
+
+	password := strings.TrimRight(line, "\r\n")
+	if password == "" {
+		return "", fmt.Errorf("empty password: %w", errUsage)
+	}
+
+	return password, nil
+}
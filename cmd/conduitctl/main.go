@@ -0,0 +1,89 @@
+// Command conduitctl is a command-line client of the conduit api.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+)
+
+const (
+	defaultBaseURL = "http://localhost:8080/api"
+	// baseURLEnv is the environment variable with the base url, the -url flag takes precedence.
+	baseURLEnv = "CONDUITCTL_URL"
+	// passwordEnv is the environment variable with the password of register and login, so scripts
+	// do not pass it in the arguments.
+	passwordEnv = "CONDUITCTL_PASSWORD"
+)
+
+const usage = `usage: conduitctl [-url url] [-output table|json] [-session path] command [arguments]
+
+commands:
+  register -email email -username username [-password password]
+  login -email email [-password password]
+  logout
+  whoami
+  profile get|follow|unfollow username
+  update-user [-email email] [-username username] [-bio bio] [-image url] [-private true|false]
+
+The password is read from -password, ` + passwordEnv + ` or the first line of stdin. The base url is read
+from -url, ` + baseURLEnv + ` or the session and defaults to ` + defaultBaseURL + `.
+`
+
+var errUsage = errors.New("invalid arguments, run conduitctl -h for usage")
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("conduitctl: ")
+
+	if err := run(context.Background(), os.Args[1:], os.Stdin, os.Stdout, os.Stderr); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+
+		log.Fatal(err)
+	}
+}
+
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("conduitctl", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprint(stderr, usage)
+	}
+
+	baseURL := flags.String("url", "", "base url of the api")
+	output := flags.String("output", outputTable, "output format, table or json")
+	sessionPath := flags.String("session", defaultSessionPath(), "path of the stored login session")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return errUsage
+	}
+
+	p, err := newPrinter(stdout, *output)
+	if err != nil {
+		return err
+	}
+
+	cli, err := newCLI(cliDeps{
+		baseURL:     *baseURL,
+		sessionPath: *sessionPath,
+		printer:     p,
+		stdin:       stdin,
+		stderr:      stderr,
+	})
+	if err != nil {
+		return err
+	}
+
+	return cli.run(ctx, flags.Arg(0), flags.Args()[1:])
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/maypok86/conduit/internal/testserver"
+	"github.com/maypok86/conduit/pkg/client"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	testserver.Main(m)
+}
+
+// conduitctl runs the command with stdin and returns what it printed to stdout.
+func conduitctl(t *testing.T, stdin string, args ...string) (string, error) {
+	t.Helper()
+
+	var stdout, stderr bytes.Buffer
+
+	err := run(context.Background(), args, strings.NewReader(stdin), &stdout, &stderr)
+
+	return stdout.String(), err
+}
+
+// register registers username on the api at baseURL and stores the session at sessionPath.
+func register(t *testing.T, baseURL, sessionPath, username string) {
+	t.Helper()
+
+	_, err := conduitctl(t, "", "-url", baseURL, "-session", sessionPath,
+		"register", "-email", username+"@mail.com", "-username", username, "-password", "password")
+	require.NoError(t, err)
+}
+
+func TestRun_Session(t *testing.T) {
+	t.Parallel()
+
+	baseURL := testserver.URL(testserver.New(t))
+	sessionPath := filepath.Join(t.TempDir(), "conduitctl", "session.json")
+
+	_, err := conduitctl(t, "password\n", "-url", baseURL, "-session", sessionPath,
+		"register", "-email", "alice@mail.com", "-username", "alice")
+	require.NoError(t, err)
+
+	s, err := loadSession(sessionPath)
+	require.NoError(t, err)
+	require.Equal(t, baseURL, s.BaseURL)
+	require.Equal(t, "alice", s.Username)
+	require.NotEmpty(t, s.Token)
+
+	info, err := os.Stat(sessionPath)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	// The base url and the token are taken from the session.
+	out, err := conduitctl(t, "", "-session", sessionPath, "-output", "json", "whoami")
+	require.NoError(t, err)
+	require.Contains(t, out, `"username": "alice"`)
+
+	_, err = conduitctl(t, "", "-session", sessionPath, "logout")
+	require.NoError(t, err)
+
+	_, err = os.Stat(sessionPath)
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	_, err = conduitctl(t, "", "-url", baseURL, "-session", sessionPath, "whoami")
+	require.ErrorIs(t, err, client.ErrUnauthorized)
+
+	_, err = conduitctl(t, "", "-url", baseURL, "-session", sessionPath,
+		"login", "-email", "alice@mail.com", "-password", "password")
+	require.NoError(t, err)
+
+	_, err = conduitctl(t, "", "-session", sessionPath, "whoami")
+	require.NoError(t, err)
+}
+
+func TestRun_SessionPerBaseURL(t *testing.T) {
+	t.Parallel()
+
+	firstURL := testserver.URL(testserver.New(t))
+	secondURL := testserver.URL(testserver.New(t))
+	sessionPath := filepath.Join(t.TempDir(), "session.json")
+
+	register(t, firstURL, sessionPath, "alice")
+
+	// The token is issued by the first api, so it is not sent to the second one.
+	_, err := conduitctl(t, "", "-url", secondURL, "-session", sessionPath, "whoami")
+	require.ErrorIs(t, err, client.ErrUnauthorized)
+
+	register(t, secondURL, sessionPath, "bob")
+
+	s, err := loadSession(sessionPath)
+	require.NoError(t, err)
+	require.Equal(t, secondURL, s.BaseURL)
+	require.Equal(t, "bob", s.Username)
+
+	_, err = conduitctl(t, "", "-url", firstURL, "-session", sessionPath, "whoami")
+	require.ErrorIs(t, err, client.ErrUnauthorized)
+
+	out, err := conduitctl(t, "", "-session", sessionPath, "-output", "json", "whoami")
+	require.NoError(t, err)
+	require.Contains(t, out, `"username": "bob"`)
+}
+
+func TestRun_Output(t *testing.T) {
+	t.Parallel()
+
+	baseURL := testserver.URL(testserver.New(t))
+	sessionPath := filepath.Join(t.TempDir(), "session.json")
+
+	register(t, baseURL, sessionPath, "alice")
+	register(t, baseURL, sessionPath, "bob")
+
+	t.Run("json", func(t *testing.T) {
+		t.Parallel()
+
+		out, err := conduitctl(t, "", "-session", sessionPath, "-output", "json", "profile", "get", "alice")
+		require.NoError(t, err)
+
+		var got map[string]client.Profile
+		require.NoError(t, json.Unmarshal([]byte(out), &got))
+		require.Equal(t, map[string]client.Profile{"profile": {Username: "alice"}}, got)
+	})
+
+	t.Run("table", func(t *testing.T) {
+		t.Parallel()
+
+		out, err := conduitctl(t, "", "-session", sessionPath, "profile", "get", "alice")
+		require.NoError(t, err)
+
+		lines := strings.Split(strings.TrimSpace(out), "\n")
+		require.Len(t, lines, 2)
+		require.Equal(t, []string{
+			"USERNAME", "BIO", "IMAGE", "PRIVATE", "FOLLOWING", "REQUESTED", "FOLLOWERS", "FOLLOWS",
+		}, strings.Fields(lines[0]))
+		require.Equal(t, []string{"alice", "-", "-", "false", "false", "false", "0", "0"}, strings.Fields(lines[1]))
+	})
+
+	t.Run("token is not printed", func(t *testing.T) {
+		t.Parallel()
+
+		s, err := loadSession(sessionPath)
+		require.NoError(t, err)
+
+		out, err := conduitctl(t, "", "-session", sessionPath, "-output", "json", "whoami")
+		require.NoError(t, err)
+		require.NotContains(t, out, s.Token)
+		require.NotContains(t, out, "token")
+	})
+
+	t.Run("unknown output", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := conduitctl(t, "", "-session", sessionPath, "-output", "yaml", "whoami")
+		require.Error(t, err)
+	})
+}
+
+func TestRun_Usage(t *testing.T) {
+	t.Parallel()
+
+	sessionPath := filepath.Join(t.TempDir(), "session.json")
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{
+			name: "no command",
+		},
+		{
+			name: "unknown command",
+			args: []string{"articles"},
+		},
+		{
+			name: "register without username",
+			args: []string{"register", "-email", "alice@mail.com", "-password", "password"},
+		},
+		{
+			name: "unknown profile command",
+			args: []string{"profile", "block", "alice"},
+		},
+		{
+			name: "invalid private",
+			args: []string{"update-user", "-private", "maybe"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := conduitctl(t, "", append([]string{"-session", sessionPath}, tt.args...)...)
+			require.ErrorIs(t, err, errUsage)
+		})
+	}
+}
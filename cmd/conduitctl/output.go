@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/maypok86/conduit/pkg/client"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+// printer prints the results of the commands as a table for humans or as json for scripts.
+type printer struct {
+	w      io.Writer
+	format string
+}
+
+func newPrinter(w io.Writer, format string) (printer, error) {
+	if format != outputTable && format != outputJSON {
+		return printer{}, fmt.Errorf("unknown output %q, use %s or %s", format, outputTable, outputJSON)
+	}
+
+	return printer{
+		w:      w,
+		format: format,
+	}, nil
+}
+
+// userOutput is the user without the token, the token is kept in the session and is not printed
+// so it does not leak into the logs of the scripts.
+type userOutput struct {
+	Email    string `json:"email"`
+	Username string `json:"username"`
+	Bio      string `json:"bio"`
+	Image    string `json:"image"`
+	Private  bool   `json:"private"`
+}
+
+func (p printer) user(user client.User) error {
+	if p.format == outputJSON {
+		return p.json(map[string]userOutput{"user": {
+			Email:    user.Email,
+			Username: user.Username,
+			Bio:      user.Bio,
+			Image:    user.Image,
+			Private:  user.Private,
+		}})
+	}
+
+	return p.table(
+		[]string{"USERNAME", "EMAIL", "BIO", "IMAGE", "PRIVATE"},
+		[]string{user.Username, user.Email, user.Bio, user.Image, strconv.FormatBool(user.Private)},
+	)
+}
+
+func (p printer) profile(profile client.Profile) error {
+	if p.format == outputJSON {
+		return p.json(map[string]client.Profile{"profile": profile})
+	}
+
+	return p.table(
+		[]string{"USERNAME", "BIO", "IMAGE", "PRIVATE", "FOLLOWING", "REQUESTED", "FOLLOWERS", "FOLLOWS"},
+		[]string{
+			profile.Username,
+			profile.Bio,
+			profile.Image,
+			strconv.FormatBool(profile.Private),
+			strconv.FormatBool(profile.Following),
+			strconv.FormatBool(profile.Requested),
+			strconv.Itoa(profile.FollowersCount),
+			strconv.Itoa(profile.FollowingCount),
+		},
+	)
+}
+
+func (p printer) json(v interface{}) error {
+	encoder := json.NewEncoder(p.w)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(v); err != nil {
+		return fmt.Errorf("can not encode output: %w", err)
+	}
+
+	return nil
+}
+
+func (p printer) table(header []string, rows ...[]string) error {
+	w := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+	for _, row := range rows {
+		for i, value := range row {
+			if value == "" {
+				row[i] = "-"
+			}
+		}
+
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("can not write output: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// session is the login stored between the runs, the password is never stored.
+type session struct {
+	BaseURL  string `json:"baseUrl"`
+	Username string `json:"username"`
+	Token    string `json:"token"`
+}
+
+// defaultSessionPath returns the path of the session in the user config directory.
+func defaultSessionPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ".conduitctl.json"
+	}
+
+	return filepath.Join(dir, "conduitctl", "session.json")
+}
+
+// loadSession loads the session at path, a missing session is empty.
+func loadSession(path string) (session, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return session{}, nil
+	}
+
+	if err != nil {
+		return session{}, fmt.Errorf("can not read session: %w", err)
+	}
+
+	var s session
+	if err := json.Unmarshal(data, &s); err != nil {
+		return session{}, fmt.Errorf("can not decode session %s: %w", path, err)
+	}
+
+	return s, nil
+}
+
+// save stores the session at path, readable by the owner only as it holds the token.
+func (s session) save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("can not create session directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("can not encode session: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("can not write session: %w", err)
+	}
+
+	return nil
+}
+
+// removeSession removes the session at path, a missing session is not an error.
+func removeSession(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("can not remove session: %w", err)
+	}
+
+	return nil
+}
@@ -3,20 +3,36 @@ package api
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
+	"github.com/maypok86/conduit/api/openapi"
 	"github.com/maypok86/conduit/internal/config"
 	httphandler "github.com/maypok86/conduit/internal/controller/http/handler"
+	"github.com/maypok86/conduit/internal/controller/http/middleware"
 	"github.com/maypok86/conduit/internal/domain"
+	"github.com/maypok86/conduit/internal/domain/export"
+	"github.com/maypok86/conduit/internal/domain/profile"
+	"github.com/maypok86/conduit/internal/domain/user"
+	"github.com/maypok86/conduit/internal/metrics"
+	"github.com/maypok86/conduit/internal/repository/memory"
 	"github.com/maypok86/conduit/internal/repository/psql"
 	"github.com/maypok86/conduit/pkg/hash"
+	"github.com/maypok86/conduit/pkg/health"
 	"github.com/maypok86/conduit/pkg/httpserver"
+	"github.com/maypok86/conduit/pkg/lifecycle"
+	"github.com/maypok86/conduit/pkg/logger"
 	"github.com/maypok86/conduit/pkg/postgres"
 	"github.com/maypok86/conduit/pkg/token"
+	"github.com/maypok86/conduit/pkg/tracing"
+	"github.com/maypok86/conduit/pkg/worker"
+	"go.opentelemetry.io/otel"
 	"go.uber.org/zap"
 )
 
@@ -25,46 +41,121 @@ type App struct {
 	logger     *zap.Logger
 	db         *postgres.Postgres
 	httpServer httpserver.Server
+	// metricsServer is nil when the metrics are disabled.
+	metricsServer  *httpserver.Server
+	tracerProvider *tracing.Provider
+	health         *health.Registry
+	exportWorker   *worker.Pool
+	userService    user.Service
+}
+
+// BuildInfo describes the build of the binary.
+type BuildInfo struct {
+	Version   string
+	BuildDate string
 }
 
 // New creates a new App.
-func New(ctx context.Context, logger *zap.Logger) (App, error) {
+func New(ctx context.Context, logger *zap.Logger, buildInfo BuildInfo) (App, error) {
 	cfg := config.Get()
 
-	postgresInstance, err := postgres.New(
+	tracerProvider, err := tracing.New(
 		ctx,
-		postgres.NewConnectionConfig(
-			cfg.Postgres.Host,
-			cfg.Postgres.Port,
-			cfg.Postgres.DBName,
-			cfg.Postgres.User,
-			cfg.Postgres.Password,
-			cfg.Postgres.SSLMode,
-		),
+		tracing.WithExporter(cfg.Tracing.Exporter),
+		tracing.WithServiceVersion(buildInfo.Version),
+		tracing.WithOTLPEndpoint(cfg.Tracing.OTLPEndpoint),
+		tracing.WithOTLPInsecure(cfg.Tracing.OTLPInsecure),
+		tracing.WithSampleRatio(cfg.Tracing.SampleRatio),
 	)
 	if err != nil {
-		return App{}, fmt.Errorf("can not connect to postgres: %w", err)
+		return App{}, fmt.Errorf("failed to create tracer provider: %w", err)
+	}
+
+	var appMetrics *metrics.Metrics
+	if cfg.Metrics.Enabled {
+		appMetrics = metrics.New()
+	}
+
+	repositories, transactor, db, err := newStorage(ctx, cfg, logger, appMetrics)
+	if err != nil {
+		return App{}, err
 	}
 
-	passwordHasher := hash.NewArgon2Hasher()
+	var passwordHasher user.PasswordHasher = hash.NewArgon2Hasher()
+	if appMetrics != nil {
+		passwordHasher = appMetrics.InstrumentPasswordHasher(passwordHasher)
+	}
 
 	tokenMaker, err := token.NewJWTMaker(cfg.Token.SecretKey)
 	if err != nil {
 		return App{}, fmt.Errorf("failed to create token maker: %w", err)
 	}
 
-	repositories := psql.NewRepositories(postgresInstance)
-	services := domain.NewServices(repositories, passwordHasher)
+	exportWorker := worker.New(
+		worker.WithSize(cfg.Export.Workers),
+		worker.WithQueueSize(cfg.Export.QueueSize),
+	)
+
+	services := domain.NewServices(domain.Deps{
+		Repositories:     repositories,
+		PasswordHasher:   passwordHasher,
+		Transactor:       transactor,
+		ExportDispatcher: exportWorker,
+		UserOptions: []user.Option{
+			user.WithDeletionGracePeriod(cfg.Account.DeletionGracePeriod),
+			user.WithDeletionMode(user.DeletionMode(cfg.Account.DeletionMode)),
+			user.WithUsernameQuarantine(cfg.Account.UsernameQuarantine),
+			user.WithUsernameCooldown(cfg.Account.UsernameCooldown),
+			user.WithRenameLimit(cfg.Account.RenameLimit, cfg.Account.RenameWindow),
+		},
+		ExportOptions: []export.Option{
+			export.WithSyncLimit(cfg.Export.SyncLimit),
+			export.WithTTL(cfg.Export.TTL),
+			export.WithPendingTimeout(cfg.Export.PendingTimeout),
+		},
+	})
+
+	openAPI, err := newOpenAPI(cfg)
+	if err != nil {
+		return App{}, err
+	}
+
+	document, err := openapi.NewDocument(
+		openapi.WithVersion(buildInfo.Version),
+		openapi.WithBuildDate(buildInfo.BuildDate),
+	)
+	if err != nil {
+		return App{}, fmt.Errorf("failed to create openapi document: %w", err)
+	}
+
+	healthRegistry := health.NewRegistry(health.WithTimeout(cfg.Health.CheckTimeout))
+	if db != nil {
+		healthRegistry.Register("postgres", health.CheckerFunc(db.Ping))
+	}
 
-	router := httphandler.NewRouter(httphandler.Deps{
+	deps := httphandler.Deps{
 		TokenMaker: tokenMaker,
 		Logger:     logger,
 		Services:   services,
-	})
+		OpenAPI:    openAPI,
+		Document:   document,
+		Readiness:  healthRegistry,
+	}
+
+	var metricsServer *httpserver.Server
+	if appMetrics != nil {
+		deps.Metrics = appMetrics
+		metricsServer = newMetricsServer(cfg, appMetrics)
+	}
+
+	router := httphandler.NewRouter(deps)
 
 	return App{
-		logger: logger,
-		db:     postgresInstance,
+		logger:         logger,
+		db:             db,
+		metricsServer:  metricsServer,
+		tracerProvider: tracerProvider,
+		health:         healthRegistry,
 		httpServer: httpserver.New(
 			router,
 			httpserver.WithHost(cfg.HTTP.Host),
@@ -73,14 +164,146 @@ func New(ctx context.Context, logger *zap.Logger) (App, error) {
 			httpserver.WithReadTimeout(cfg.HTTP.ReadTimeout),
 			httpserver.WithWriteTimeout(cfg.HTTP.WriteTimeout),
 		),
+		exportWorker: exportWorker,
+		userService:  services.User,
 	}, nil
 }
 
-// Run runs the application.
+// newMetricsServer creates the server of the metrics, it listens apart from the api so the metrics
+// are not public.
+func newMetricsServer(cfg *config.Config, appMetrics *metrics.Metrics) *httpserver.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", appMetrics.Handler())
+
+	server := httpserver.New(
+		mux,
+		httpserver.WithHost(cfg.Metrics.Host),
+		httpserver.WithPort(cfg.Metrics.Port),
+	)
+
+	return &server
+}
+
+// newOpenAPI creates the OpenAPI validation middleware if it is enabled. Responses are not validated in prod,
+// buffering them costs too much there.
+func newOpenAPI(cfg *config.Config) (*middleware.OpenAPI, error) {
+	if !cfg.IsOpenAPIValidationEnabled() {
+		return nil, nil //nolint:nilnil
+	}
+
+	openAPI, err := middleware.NewOpenAPI(
+		openapi.Spec,
+		middleware.WithStrictValidation(cfg.IsOpenAPIValidationStrict()),
+		middleware.WithResponseValidation(!cfg.IsProd()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create openapi middleware: %w", err)
+	}
+
+	return &openAPI, nil
+}
+
+// newStorage creates the repositories of the configured storage. The returned postgres instance is nil
+// for the memory storage. The queries and the pool are recorded by appMetrics unless it is nil.
+func newStorage(
+	ctx context.Context,
+	cfg *config.Config,
+	logger *zap.Logger,
+	appMetrics *metrics.Metrics,
+) (domain.Repositories, profile.Transactor, *postgres.Postgres, error) {
+	if cfg.IsMemoryStorage() {
+		logger.Warn("repositories keep the data in memory, it is lost when the application exits")
+
+		store := memory.NewStore()
+		return memory.NewRepositories(store), store, nil, nil
+	}
+
+	if cfg.Migrate.OnStart {
+		if err := Migrate(ctx, logger, MigrateUp, io.Discard); err != nil {
+			return domain.Repositories{}, nil, nil, err
+		}
+	}
+
+	replicaConfigs := make([]postgres.ConnectionConfig, 0, len(cfg.Postgres.ReplicaURLs))
+	for _, replicaURL := range cfg.Postgres.ReplicaURLs {
+		replicaConfigs = append(replicaConfigs, postgres.NewURLConnectionConfig(replicaURL))
+	}
+
+	opts := []postgres.Option{
+		postgres.WithMaxPoolSize(cfg.Postgres.MaxConns),
+		postgres.WithMinPoolSize(cfg.Postgres.MinConns),
+		postgres.WithMaxConnLifetime(cfg.Postgres.MaxConnLifetime),
+		postgres.WithMaxConnIdleTime(cfg.Postgres.MaxConnIdleTime),
+		postgres.WithHealthCheckPeriod(cfg.Postgres.HealthCheckPeriod),
+		postgres.WithStatementTimeout(cfg.Postgres.StatementTimeout),
+		postgres.WithIdleInTransactionSessionTimeout(cfg.Postgres.IdleInTxTimeout),
+		postgres.WithApplicationName(cfg.Postgres.ApplicationName),
+		postgres.WithConnAttempts(cfg.Postgres.ConnAttempts),
+		postgres.WithConnTimeout(cfg.Postgres.ConnTimeout),
+		postgres.WithSlowQueryThreshold(cfg.Postgres.SlowQueryThreshold),
+		postgres.WithReplicas(replicaConfigs...),
+		postgres.WithReplicaCheckPeriod(cfg.Postgres.ReplicaCheckPeriod),
+		postgres.WithReadYourWritesWindow(cfg.Postgres.ReadYourWritesWindow),
+		postgres.WithLogger(logger),
+	}
+	if appMetrics != nil {
+		opts = append(opts, postgres.WithQueryHooks(appMetrics.QueryHook()))
+	}
+
+	if cfg.IsTracingEnabled() {
+		opts = append(opts, postgres.WithQueryHooks(postgres.NewTracingHook(otel.GetTracerProvider())))
+	}
+
+	postgresInstance, err := postgres.New(ctx, newConnectionConfig(cfg), opts...)
+	if err != nil {
+		return domain.Repositories{}, nil, nil, fmt.Errorf("can not connect to postgres: %w", err)
+	}
+
+	if appMetrics != nil {
+		appMetrics.RegisterPool(postgresInstance)
+	}
+
+	return psql.NewRepositories(postgresInstance), postgresInstance, postgresInstance, nil
+}
+
+func newConnectionConfig(cfg *config.Config) postgres.ConnectionConfig {
+	if cfg.Postgres.URL != "" {
+		return postgres.NewURLConnectionConfig(cfg.Postgres.URL)
+	}
+
+	return postgres.NewConnectionConfig(
+		cfg.Postgres.Host,
+		cfg.Postgres.Port,
+		cfg.Postgres.DBName,
+		cfg.Postgres.User,
+		cfg.Postgres.Password,
+		cfg.Postgres.SSLMode,
+	)
+}
+
+// Run runs the application until SIGINT or SIGTERM and then shuts it down gracefully, a second signal
+// exits at once.
 func (a App) Run(ctx context.Context) error {
-	eChan := make(chan error)
+	// The channel is buffered for every server, so a server failing after the shutdown does not block.
+	const servers = 2
+	eChan := make(chan error, servers)
 	interrupt := make(chan os.Signal, 1)
 
+	workerCtx := logger.ContextWithLogger(ctx, a.logger)
+	a.exportWorker.Start(workerCtx)
+
+	// The purge has its own context, so stopping it does not cancel the exports the pool is flushing.
+	purgeCtx, cancelPurge := context.WithCancel(workerCtx)
+	defer cancelPurge()
+
+	purgeDone := make(chan struct{})
+
+	go func() {
+		defer close(purgeDone)
+
+		a.purgeDeletedUsers(purgeCtx, config.Get().Account.PurgeInterval)
+	}()
+
 	a.logger.Info("Http server is starting")
 
 	go func() {
@@ -89,17 +312,127 @@ func (a App) Run(ctx context.Context) error {
 		}
 	}()
 
+	if a.metricsServer != nil {
+		go func() {
+			if err := a.metricsServer.Start(); err != nil {
+				eChan <- fmt.Errorf("failed to listen and serve metrics: %w", err)
+			}
+		}()
+	}
+
 	signal.Notify(interrupt, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(interrupt)
+
+	var runErr error
 	select {
 	case err := <-eChan:
-		return fmt.Errorf("conduit started failed: %w", err)
-	case <-interrupt:
+		runErr = fmt.Errorf("conduit started failed: %w", err)
+	case sig := <-interrupt:
+		a.logger.Info("Shutting down", zap.String("signal", sig.String()))
+
+		go a.forceExit(interrupt)
 	}
 
-	const httpShutdownTimeout = 5 * time.Second
-	if err := a.httpServer.Stop(ctx, httpShutdownTimeout); err != nil {
-		return fmt.Errorf("failed to stop http server: %w", err)
+	if err := a.newLifecycle(cancelPurge, purgeDone).Shutdown(ctx); err != nil && runErr == nil {
+		runErr = fmt.Errorf("failed to shutdown: %w", err)
 	}
 
-	return nil
+	return runErr
+}
+
+// forceExit exits on the second signal, when the graceful shutdown takes too long for the operator.
+func (a App) forceExit(interrupt <-chan os.Signal) {
+	sig := <-interrupt
+	a.logger.Warn("Forced exit, the shutdown is not finished", zap.String("signal", sig.String()))
+	_ = a.logger.Sync()
+
+	os.Exit(1)
+}
+
+// newLifecycle returns the shutdown of the application: the readiness fails first, so the load balancers
+// stop sending traffic, then the servers drain the in-flight requests, the background work is flushed
+// and the resources it used are closed last. cancelPurge stops the purge of the deleted users, purgeDone
+// is closed once it returns.
+func (a App) newLifecycle(cancelPurge context.CancelFunc, purgeDone <-chan struct{}) *lifecycle.Manager {
+	cfg := config.Get()
+	manager := lifecycle.New(lifecycle.WithTimeout(cfg.Shutdown.Timeout), lifecycle.WithLogger(a.logger))
+
+	manager.Add("readiness", func(ctx context.Context) error {
+		a.health.Drain()
+		a.logger.Info("Readiness is failing, draining traffic", zap.Duration("delay", cfg.Health.DrainDelay))
+
+		timer := time.NewTimer(cfg.Health.DrainDelay)
+		defer timer.Stop()
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("drain delay is interrupted: %w", ctx.Err())
+		case <-timer.C:
+			return nil
+		}
+	})
+	manager.Add("http server", a.httpServer.Stop)
+
+	if a.metricsServer != nil {
+		manager.Add("metrics server", a.metricsServer.Stop)
+	}
+
+	manager.Add("purge", func(ctx context.Context) error {
+		cancelPurge()
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("purge is not finished: %w", ctx.Err())
+		case <-purgeDone:
+			return nil
+		}
+	})
+	// The pool finishes the running and the queued exports, it cancels them only when ctx is done.
+	manager.Add("export worker", func(ctx context.Context) error {
+		if err := a.exportWorker.Stop(ctx); err != nil {
+			return fmt.Errorf("can not stop export worker: %w", err)
+		}
+
+		return nil
+	})
+	manager.Add("tracer provider", a.tracerProvider.Shutdown)
+
+	if a.db != nil {
+		manager.Add("postgres", func(context.Context) error {
+			a.db.Close()
+
+			return nil
+		})
+	}
+
+	manager.Add("logger", func(context.Context) error {
+		// Syncing a terminal or a pipe is not supported, the writes are not buffered there anyway.
+		if err := a.logger.Sync(); err != nil && !errors.Is(err, syscall.EINVAL) && !errors.Is(err, syscall.ENOTTY) {
+			return fmt.Errorf("can not sync logger: %w", err)
+		}
+
+		return nil
+	})
+
+	return manager
+}
+
+func (a App) purgeDeletedUsers(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := a.userService.PurgeDeleted(ctx)
+		if err != nil {
+			a.logger.Error("failed to purge deleted users", zap.Error(err))
+		} else if purged > 0 {
+			a.logger.Info("deleted users are purged", zap.Int64("count", purged))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
 }
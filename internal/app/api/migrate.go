@@ -0,0 +1,95 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"text/tabwriter"
+	"time"
+
+	"github.com/maypok86/conduit/internal/config"
+	"github.com/maypok86/conduit/migrations"
+	"github.com/maypok86/conduit/pkg/migrator"
+	"go.uber.org/zap"
+)
+
+// Migration commands.
+const (
+	MigrateUp     = "up"
+	MigrateDown   = "down"
+	MigrateRedo   = "redo"
+	MigrateStatus = "status"
+)
+
+// ErrUnknownMigrateCommand is returned when the migration command is not supported.
+var ErrUnknownMigrateCommand = errors.New("unknown migrate command, use up, down, redo or status")
+
+// Migrate runs the migration command against the configured database. Status is written to out.
+func Migrate(ctx context.Context, logger *zap.Logger, command string, out io.Writer) error {
+	switch command {
+	case MigrateUp, MigrateDown, MigrateRedo, MigrateStatus:
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownMigrateCommand, command)
+	}
+
+	m, err := migrator.New(
+		newConnectionConfig(config.Get()).DSN(),
+		migrations.FS,
+		migrator.WithLogger(logger),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create migrator: %w", err)
+	}
+
+	defer func() {
+		if err := m.Close(); err != nil {
+			logger.Error("failed to close migrator", zap.Error(err))
+		}
+	}()
+
+	switch command {
+	case MigrateUp:
+		err = m.Up(ctx)
+	case MigrateDown:
+		err = m.Down(ctx)
+	case MigrateRedo:
+		err = m.Redo(ctx)
+	case MigrateStatus:
+		err = printMigrationStatus(ctx, m, out)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to migrate %s: %w", command, err)
+	}
+
+	return nil
+}
+
+func printMigrationStatus(ctx context.Context, m *migrator.Migrator, out io.Writer) error {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get migration status: %w", err)
+	}
+
+	const padding = 2
+
+	w := tabwriter.NewWriter(out, 0, 0, padding, ' ', 0)
+	fmt.Fprintln(w, "Applied At\tMigration")
+
+	for _, status := range statuses {
+		appliedAt := "Pending"
+		if status.Applied {
+			appliedAt = status.AppliedAt.Format(time.RFC3339)
+		}
+
+		fmt.Fprintf(w, "%s\t%s\n", appliedAt, path.Base(status.Source))
+	}
+
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to write migration status: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/maypok86/conduit/internal/config"
+	"github.com/maypok86/conduit/internal/seed"
+	"github.com/maypok86/conduit/pkg/hash"
+	"github.com/maypok86/conduit/pkg/logger"
+	"github.com/maypok86/conduit/pkg/postgres"
+	"go.uber.org/zap"
+)
+
+// Seed loads demo data into the configured database.
+func Seed(ctx context.Context, l *zap.Logger, opts ...seed.Option) error {
+	cfg := config.Get()
+
+	db, err := postgres.New(
+		ctx,
+		newConnectionConfig(cfg),
+		postgres.WithConnAttempts(cfg.Postgres.ConnAttempts),
+		postgres.WithConnTimeout(cfg.Postgres.ConnTimeout),
+		postgres.WithLogger(l),
+	)
+	if err != nil {
+		return fmt.Errorf("can not connect to postgres: %w", err)
+	}
+	defer db.Close()
+
+	result, err := seed.New(db, hash.NewArgon2Hasher(), opts...).Run(logger.ContextWithLogger(ctx, l))
+	if err != nil {
+		return fmt.Errorf("failed to seed: %w", err)
+	}
+
+	l.Info("demo data is loaded", zap.Int64("users", result.Users), zap.Int64("follows", result.Follows))
+
+	return nil
+}
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"net"
+	"net/url"
 	"sync"
 	"time"
 
@@ -20,34 +22,93 @@ const (
 	dev  EnvType = "dev"
 )
 
+const (
+	postgresStorage = "postgres"
+	memoryStorage   = "memory"
+)
+
+const (
+	openAPIValidationOff  = "off"
+	openAPIValidationLog  = "log"
+	openAPIValidationFail = "fail"
+)
+
+const (
+	tracingExporterNone   = "none"
+	tracingExporterStdout = "stdout"
+	tracingExporterOTLP   = "otlp"
+)
+
 type (
 	// Config is the configuration for the application.
 	Config struct {
 		Environment EnvType `envconfig:"ENVIRONMENT" required:"true"`
 		HTTP        HTTP
+		Storage     Storage
 		Postgres    Postgres
+		Migrate     Migrate
 		Logger      Logger
 		Token       Token
 		CORS        CORS
+		OpenAPI     OpenAPI
+		Account     Account
+		Export      Export
+		Metrics     Metrics
+		Tracing     Tracing
+		Health      Health
+		Shutdown    Shutdown
 	}
 
-	// HTTP is the configuration for the HTTP server.
+	// HTTP is the configuration for the HTTP server. The client ip is taken from the X-Forwarded-For and
+	// X-Real-IP headers only when the request comes from one of TrustedProxies, the ips or CIDRs. PublicURL
+	// is the http or https URL the clients reach the server at, the OpenAPI document uses a relative
+	// server URL without it.
 	HTTP struct {
 		Host           string        `envconfig:"HTTP_HOST"             required:"true"`
 		Port           string        `envconfig:"HTTP_PORT"             required:"true"`
 		MaxHeaderBytes int           `envconfig:"HTTP_MAX_HEADER_BYTES"                 default:"1"`
 		ReadTimeout    time.Duration `envconfig:"HTTP_READ_TIMEOUT"                     default:"10s"`
 		WriteTimeout   time.Duration `envconfig:"HTTP_WRITE_TIMEOUT"                    default:"10s"`
+		TrustedProxies []string      `envconfig:"HTTP_TRUSTED_PROXIES"`
+		PublicURL      string        `envconfig:"HTTP_PUBLIC_URL"`
+	}
+
+	// Storage is the configuration for the storage of the repositories. The memory storage does not need
+	// the postgres configuration and loses the data when the application exits.
+	Storage struct {
+		Driver string `envconfig:"STORAGE_DRIVER" default:"postgres"`
 	}
 
 	// Postgres is the configuration for the Postgres database.
+	// URL takes precedence over the discrete connection fields. Reads of profiles are served by
+	// the replicas from ReplicaURLs when there are any.
 	Postgres struct {
-		Host     string `envconfig:"POSTGRES_HOST"     required:"true"`
-		Port     string `envconfig:"POSTGRES_PORT"     required:"true"`
-		DBName   string `envconfig:"POSTGRES_DBNAME"   required:"true"`
-		User     string `envconfig:"POSTGRES_USER"     required:"true"`
-		Password string `envconfig:"POSTGRES_PASSWORD" required:"true" json:"-"`
-		SSLMode  string `envconfig:"POSTGRES_SSLMODE"                           default:"disable"`
+		URL                  string        `envconfig:"DATABASE_URL"                     json:"-"`
+		Host                 string        `envconfig:"POSTGRES_HOST"`
+		Port                 string        `envconfig:"POSTGRES_PORT"`
+		DBName               string        `envconfig:"POSTGRES_DBNAME"`
+		User                 string        `envconfig:"POSTGRES_USER"`
+		Password             string        `envconfig:"POSTGRES_PASSWORD"                json:"-"`
+		SSLMode              string        `envconfig:"POSTGRES_SSLMODE"                          default:"disable"`
+		MaxConns             int           `envconfig:"POSTGRES_MAX_CONNS"                        default:"10"`
+		MinConns             int           `envconfig:"POSTGRES_MIN_CONNS"                        default:"0"`
+		MaxConnLifetime      time.Duration `envconfig:"POSTGRES_MAX_CONN_LIFETIME"                default:"1h"`
+		MaxConnIdleTime      time.Duration `envconfig:"POSTGRES_MAX_CONN_IDLE_TIME"               default:"30m"`
+		HealthCheckPeriod    time.Duration `envconfig:"POSTGRES_HEALTH_CHECK_PERIOD"              default:"1m"`
+		StatementTimeout     time.Duration `envconfig:"POSTGRES_STATEMENT_TIMEOUT"                default:"0s"`
+		IdleInTxTimeout      time.Duration `envconfig:"POSTGRES_IDLE_IN_TX_TIMEOUT"               default:"0s"`
+		ApplicationName      string        `envconfig:"POSTGRES_APPLICATION_NAME"                 default:"conduit"`
+		ConnAttempts         int           `envconfig:"POSTGRES_CONN_ATTEMPTS"                    default:"10"`
+		ConnTimeout          time.Duration `envconfig:"POSTGRES_CONN_TIMEOUT"                     default:"5s"`
+		SlowQueryThreshold   time.Duration `envconfig:"POSTGRES_SLOW_QUERY_THRESHOLD"             default:"200ms"`
+		ReplicaURLs          []string      `envconfig:"POSTGRES_REPLICA_URLS"            json:"-"`
+		ReplicaCheckPeriod   time.Duration `envconfig:"POSTGRES_REPLICA_CHECK_PERIOD"             default:"5s"`
+		ReadYourWritesWindow time.Duration `envconfig:"POSTGRES_READ_YOUR_WRITES_WINDOW"          default:"5s"`
+	}
+
+	// Migrate is the configuration for the database migrations.
+	Migrate struct {
+		OnStart bool `envconfig:"MIGRATE_ON_START" default:"false"`
 	}
 
 	// Logger is the configuration for the logger.
@@ -65,8 +126,84 @@ type (
 	CORS struct {
 		AllowOrigins []string `envconfig:"CORS_ALLOW_ORIGINS" required:"true"`
 	}
+
+	// OpenAPI is the configuration for the validation of the api traffic against the OpenAPI specification.
+	// Validation is off, log or fail. Responses are validated only in the dev and test environments.
+	OpenAPI struct {
+		Validation string `envconfig:"OPENAPI_VALIDATION" default:"off"`
+	}
+
+	// Account is the configuration for the account deletion and renaming.
+	Account struct {
+		DeletionGracePeriod time.Duration `envconfig:"ACCOUNT_DELETION_GRACE_PERIOD" default:"720h"`
+		DeletionMode        string        `envconfig:"ACCOUNT_DELETION_MODE"         default:"anonymize"`
+		PurgeInterval       time.Duration `envconfig:"ACCOUNT_PURGE_INTERVAL"        default:"1h"`
+		UsernameQuarantine  time.Duration `envconfig:"ACCOUNT_USERNAME_QUARANTINE"   default:"2160h"`
+		UsernameCooldown    time.Duration `envconfig:"ACCOUNT_USERNAME_COOLDOWN"     default:"720h"`
+		RenameLimit         int           `envconfig:"ACCOUNT_RENAME_LIMIT"          default:"3"`
+		RenameWindow        time.Duration `envconfig:"ACCOUNT_RENAME_WINDOW"         default:"720h"`
+	}
+
+	// Export is the configuration for the user data export.
+	Export struct {
+		SyncLimit      int           `envconfig:"EXPORT_SYNC_LIMIT"      default:"1000"`
+		TTL            time.Duration `envconfig:"EXPORT_TTL"             default:"24h"`
+		PendingTimeout time.Duration `envconfig:"EXPORT_PENDING_TIMEOUT" default:"1h"`
+		Workers        int           `envconfig:"EXPORT_WORKERS"         default:"2"`
+		QueueSize      int           `envconfig:"EXPORT_QUEUE_SIZE"      default:"100"`
+	}
+
+	// Metrics is the configuration for the Prometheus metrics. The metrics are served by a separate listener,
+	// so they are not exposed on the public api port.
+	Metrics struct {
+		Enabled bool   `envconfig:"METRICS_ENABLED" default:"true"`
+		Host    string `envconfig:"METRICS_HOST"    default:"localhost"`
+		Port    string `envconfig:"METRICS_PORT"    default:"9090"`
+	}
+
+	// Tracing is the configuration for the OpenTelemetry tracing. Exporter is none, stdout or otlp,
+	// the trace context of the incoming requests is propagated to the logs with any exporter.
+	Tracing struct {
+		Exporter     string  `envconfig:"TRACING_EXPORTER"      default:"none"`
+		OTLPEndpoint string  `envconfig:"TRACING_OTLP_ENDPOINT" default:"localhost:4318"`
+		OTLPInsecure bool    `envconfig:"TRACING_OTLP_INSECURE" default:"false"`
+		SampleRatio  float64 `envconfig:"TRACING_SAMPLE_RATIO"  default:"1"`
+	}
+
+	// Health is the configuration for the readiness checks. The readiness fails for DrainDelay before
+	// the server stops, so the load balancers have time to stop sending traffic.
+	Health struct {
+		CheckTimeout time.Duration `envconfig:"HEALTH_CHECK_TIMEOUT" default:"1s"`
+		DrainDelay   time.Duration `envconfig:"HEALTH_DRAIN_DELAY"   default:"0s"`
+	}
+
+	// Shutdown is the configuration for the graceful shutdown. Timeout bounds the whole shutdown,
+	// the drain delay of the readiness included.
+	Shutdown struct {
+		Timeout time.Duration `envconfig:"SHUTDOWN_TIMEOUT" default:"15s"`
+	}
 )
 
+// IsMemoryStorage check that repositories keep the data in memory.
+func (c *Config) IsMemoryStorage() bool {
+	return c.Storage.Driver == memoryStorage
+}
+
+// IsOpenAPIValidationEnabled check that the api traffic is validated against the OpenAPI specification.
+func (c *Config) IsOpenAPIValidationEnabled() bool {
+	return c.OpenAPI.Validation != openAPIValidationOff
+}
+
+// IsOpenAPIValidationStrict check that mismatches with the OpenAPI specification fail the requests.
+func (c *Config) IsOpenAPIValidationStrict() bool {
+	return c.OpenAPI.Validation == openAPIValidationFail
+}
+
+// IsTracingEnabled check that the spans are exported.
+func (c *Config) IsTracingEnabled() bool {
+	return c.Tracing.Exporter != tracingExporterNone
+}
+
 // IsDev check that environment is dev.
 func (c *Config) IsDev() bool {
 	return c.Environment == dev
@@ -99,6 +236,56 @@ func Get() *Config {
 		default:
 			log.Fatal("config environment should be test, prod or dev")
 		}
+
+		for _, proxy := range instance.HTTP.TrustedProxies {
+			if !isIPOrCIDR(proxy) {
+				log.Fatalf("config http trusted proxy %q should be an ip or a CIDR", proxy)
+			}
+		}
+
+		if instance.HTTP.PublicURL != "" && !isHTTPURL(instance.HTTP.PublicURL) {
+			log.Fatal("config http public url should be an absolute http or https url")
+		}
+
+		switch instance.Storage.Driver {
+		case postgresStorage, memoryStorage:
+		default:
+			log.Fatal("config storage driver should be postgres or memory")
+		}
+
+		if !instance.IsMemoryStorage() && instance.Postgres.URL == "" && (instance.Postgres.Host == "" ||
+			instance.Postgres.Port == "" ||
+			instance.Postgres.DBName == "" ||
+			instance.Postgres.User == "") {
+			log.Fatal("config postgres should have DATABASE_URL or host, port, dbname and user")
+		}
+
+		switch instance.OpenAPI.Validation {
+		case openAPIValidationOff, openAPIValidationLog, openAPIValidationFail:
+		default:
+			log.Fatal("config openapi validation should be off, log or fail")
+		}
+
+		switch instance.Tracing.Exporter {
+		case tracingExporterNone, tracingExporterStdout, tracingExporterOTLP:
+		default:
+			log.Fatal("config tracing exporter should be none, stdout or otlp")
+		}
+
+		if instance.Tracing.SampleRatio < 0 || instance.Tracing.SampleRatio > 1 {
+			log.Fatal("config tracing sample ratio should be between 0 and 1")
+		}
+
+		if instance.Shutdown.Timeout <= instance.Health.DrainDelay {
+			log.Fatal("config shutdown timeout should be greater than health drain delay")
+		}
+
+		switch instance.Account.DeletionMode {
+		case "anonymize", "cascade":
+		default:
+			log.Fatal("config account deletion mode should be anonymize or cascade")
+		}
+
 		if instance.IsDev() {
 			configBytes, err := json.MarshalIndent(instance, "", " ")
 			if err != nil {
@@ -110,3 +297,19 @@ func Get() *Config {
 
 	return &instance
 }
+
+func isIPOrCIDR(value string) bool {
+	if net.ParseIP(value) != nil {
+		return true
+	}
+
+	_, _, err := net.ParseCIDR(value)
+
+	return err == nil
+}
+
+func isHTTPURL(value string) bool {
+	u, err := url.Parse(value)
+
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
@@ -63,13 +63,26 @@ func TestGet(t *testing.T) {
 			ReadTimeout:    10 * time.Second,
 			WriteTimeout:   10 * time.Second,
 		},
+		Storage: config.Storage{
+			Driver: "postgres",
+		},
 		Postgres: config.Postgres{
-			Host:     "postgres",
-			Port:     "5431",
-			DBName:   "test_conduit",
-			User:     "test_conduit",
-			Password: "test",
-			SSLMode:  "disable",
+			Host:                 "postgres",
+			Port:                 "5431",
+			DBName:               "test_conduit",
+			User:                 "test_conduit",
+			Password:             "test",
+			SSLMode:              "disable",
+			MaxConns:             10,
+			MaxConnLifetime:      time.Hour,
+			MaxConnIdleTime:      30 * time.Minute,
+			HealthCheckPeriod:    time.Minute,
+			ApplicationName:      "conduit",
+			ConnAttempts:         10,
+			ConnTimeout:          5 * time.Second,
+			SlowQueryThreshold:   200 * time.Millisecond,
+			ReplicaCheckPeriod:   5 * time.Second,
+			ReadYourWritesWindow: 5 * time.Second,
 		},
 		Logger: config.Logger{
 			Level: "info",
@@ -81,6 +94,41 @@ func TestGet(t *testing.T) {
 		CORS: config.CORS{
 			AllowOrigins: []string{"http://localhost:3000"},
 		},
+		OpenAPI: config.OpenAPI{
+			Validation: "off",
+		},
+		Account: config.Account{
+			DeletionGracePeriod: 720 * time.Hour,
+			DeletionMode:        "anonymize",
+			PurgeInterval:       time.Hour,
+			UsernameQuarantine:  2160 * time.Hour,
+			UsernameCooldown:    720 * time.Hour,
+			RenameLimit:         3,
+			RenameWindow:        720 * time.Hour,
+		},
+		Export: config.Export{
+			SyncLimit:      1000,
+			TTL:            24 * time.Hour,
+			PendingTimeout: time.Hour,
+			Workers:        2,
+			QueueSize:      100,
+		},
+		Metrics: config.Metrics{
+			Enabled: true,
+			Host:    "localhost",
+			Port:    "9090",
+		},
+		Tracing: config.Tracing{
+			Exporter:     "none",
+			OTLPEndpoint: "localhost:4318",
+			SampleRatio:  1,
+		},
+		Health: config.Health{
+			CheckTimeout: time.Second,
+		},
+		Shutdown: config.Shutdown{
+			Timeout: 15 * time.Second,
+		},
 	}
 
 	setEnv(t, env)
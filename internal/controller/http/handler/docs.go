@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/maypok86/conduit/internal/controller/http/httperr"
+	swaggerfiles "github.com/swaggo/files"
+)
+
+// Document is the OpenAPI document of the api.
+type Document interface {
+	JSON(serverURL string) ([]byte, error)
+	YAML(serverURL string) ([]byte, error)
+}
+
+// docsPage is the api explorer, it loads the document and the swagger ui assets from the api itself,
+// so it works offline.
+const docsPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+	<meta charset="UTF-8">
+	<title>Conduit API</title>
+	<link rel="stylesheet" type="text/css" href="/api/docs/swagger-ui.css">
+	<link rel="icon" type="image/png" href="/api/docs/favicon-32x32.png" sizes="32x32">
+	<link rel="icon" type="image/png" href="/api/docs/favicon-16x16.png" sizes="16x16">
+</head>
+<body>
+	<div id="swagger-ui"></div>
+	<script src="/api/docs/swagger-ui-bundle.js" charset="UTF-8"></script>
+	<script src="/api/docs/swagger-ui-standalone-preset.js" charset="UTF-8"></script>
+	<script>
+		window.onload = function () {
+			window.ui = SwaggerUIBundle({
+				url: "/api/openapi.json",
+				dom_id: "#swagger-ui",
+				deepLinking: true,
+				presets: [SwaggerUIBundle.presets.apis, SwaggerUIStandalonePreset],
+				layout: "StandaloneLayout"
+			});
+		};
+	</script>
+</body>
+</html>
+`
+
+type docsAsset struct {
+	contentType string
+	data        []byte
+}
+
+// docsAssets are the swagger ui assets the explorer loads.
+var docsAssets = map[string]docsAsset{
+	"swagger-ui.css": {
+		contentType: "text/css; charset=utf-8",
+		data:        swaggerfiles.FileSwaggerUICSS,
+	},
+	"swagger-ui-bundle.js": {
+		contentType: "application/javascript",
+		data:        swaggerfiles.FileSwaggerUIBundleJs,
+	},
+	"swagger-ui-standalone-preset.js": {
+		contentType: "application/javascript",
+		data:        swaggerfiles.FileSwaggerUIStandalonePresetJs,
+	},
+	"favicon-32x32.png": {
+		contentType: "image/png",
+		data:        swaggerfiles.FileFavicon32x32Png,
+	},
+	"favicon-16x16.png": {
+		contentType: "image/png",
+		data:        swaggerfiles.FileFavicon16x16Png,
+	},
+}
+
+type docsHandler struct {
+	document  Document
+	serverURL string
+}
+
+type docsDeps struct {
+	router   *gin.RouterGroup
+	document Document
+	// serverURL is the url of the api in the document.
+	serverURL string
+	// explorer enables the api explorer at /docs.
+	explorer bool
+}
+
+func newDocsHandler(deps docsDeps) {
+	handler := docsHandler{
+		document:  deps.document,
+		serverURL: deps.serverURL,
+	}
+
+	deps.router.GET("/openapi.json", handler.getJSON)
+	deps.router.GET("/openapi.yaml", handler.getYAML)
+
+	if deps.explorer {
+		deps.router.GET("/docs", handler.getExplorer)
+		deps.router.GET("/docs/:asset", handler.getAsset)
+	}
+}
+
+func (h docsHandler) getJSON(c *gin.Context) {
+	data, err := h.document.JSON(h.serverURL)
+	if err != nil {
+		httperr.InternalError(c, "openapi-document", err)
+
+		return
+	}
+
+	c.Data(http.StatusOK, "application/json; charset=utf-8", data)
+}
+
+func (h docsHandler) getYAML(c *gin.Context) {
+	data, err := h.document.YAML(h.serverURL)
+	if err != nil {
+		httperr.InternalError(c, "openapi-document", err)
+
+		return
+	}
+
+	c.Data(http.StatusOK, "application/yaml; charset=utf-8", data)
+}
+
+func (h docsHandler) getExplorer(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(docsPage))
+}
+
+func (h docsHandler) getAsset(c *gin.Context) {
+	asset, ok := docsAssets[c.Param("asset")]
+	if !ok {
+		c.AbortWithStatus(http.StatusNotFound)
+
+		return
+	}
+
+	c.Data(http.StatusOK, asset.contentType, asset.data)
+}
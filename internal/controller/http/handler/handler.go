@@ -2,49 +2,107 @@
 package handler
 
 import (
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/maypok86/conduit/internal/config"
 	"github.com/maypok86/conduit/internal/controller/http/middleware"
 	"github.com/maypok86/conduit/internal/domain"
 	"github.com/maypok86/conduit/pkg/token"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 	"go.uber.org/zap"
 )
 
+// serviceName is the name of the api in the traces.
+const serviceName = "conduit"
+
 //go:generate mockgen -source=handler.go -destination=mocks/handler_test.go -package=handler_test
 
 // TokenMaker is a token maker.
 type TokenMaker interface {
-	CreateToken(email string, duration time.Duration) (string, error)
+	CreateToken(userID uuid.UUID, email string, duration time.Duration) (string, error)
 	VerifyToken(accessToken string) (*token.Payload, error)
 }
 
+// Metrics records the metrics of the api.
+type Metrics interface {
+	middleware.RequestObserver
+	middleware.TokenFailureObserver
+}
+
 // Deps is a http handler dependencies.
 type Deps struct {
 	TokenMaker TokenMaker
 	Logger     *zap.Logger
 	Services   domain.Services
+	// OpenAPI validates the api traffic against the specification, nil disables the validation.
+	OpenAPI *middleware.OpenAPI
+	// Document is served at /api/openapi.json and /api/openapi.yaml, nil disables serving it.
+	Document Document
+	// Metrics records the requests and the rejected tokens, nil disables the metrics.
+	Metrics Metrics
+	// Readiness is served at /readyz together with the liveness at /healthz, nil disables both.
+	Readiness Readiness
 }
 
 // NewRouter returns a new http router.
 func NewRouter(deps Deps) *gin.Engine {
 	router := gin.New()
+	cfg := config.Get()
 
-	if config.Get().IsProd() {
+	if cfg.IsProd() {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
+	// The proxies are validated by the config, on an error no proxy is trusted rather than every one.
+	if err := router.SetTrustedProxies(cfg.HTTP.TrustedProxies); err != nil {
+		deps.Logger.Error("failed to set trusted proxies", zap.Error(err))
+		_ = router.SetTrustedProxies(nil)
+	}
+
+	var authOptions []middleware.AuthOption
+	if deps.Metrics != nil {
+		// The metrics middleware goes first to record the requests the recovery middleware answers.
+		router.Use(middleware.Metrics(deps.Metrics))
+		authOptions = append(authOptions, middleware.WithTokenFailureObserver(deps.Metrics))
+	}
+
+	// The tracing middleware goes before the access log, which adds the trace id to the request logger.
+	router.Use(otelgin.Middleware(serviceName))
 	middleware.ApplyMiddlewares(router, deps.Logger)
 
+	if deps.Readiness != nil {
+		newHealthHandler(healthDeps{
+			router:    router,
+			readiness: deps.Readiness,
+		})
+	}
+
+	if deps.Document != nil {
+		// The document does not describe itself, so it is served without the OpenAPI validation.
+		newDocsHandler(docsDeps{
+			router:    router.Group("/api"),
+			document:  deps.Document,
+			serverURL: apiURL(cfg.HTTP.PublicURL),
+			explorer:  !cfg.IsProd(),
+		})
+	}
+
 	api := router.Group("/api")
+	if deps.OpenAPI != nil {
+		api.Use(deps.OpenAPI.Handle)
+	}
+
 	{
-		authMiddleware := middleware.NewAuth(deps.TokenMaker)
+		authMiddleware := middleware.NewAuth(deps.TokenMaker, authOptions...)
 
 		newUserHandler(userDeps{
 			router:         api,
 			authMiddleware: authMiddleware,
 			userService:    deps.Services.User,
+			exportService:  deps.Services.Export,
 			tokenMaker:     deps.TokenMaker,
 		})
 
@@ -57,3 +115,9 @@ func NewRouter(deps Deps) *gin.Engine {
 
 	return router
 }
+
+// apiURL returns the url of the api under publicURL. Without publicURL it is relative, the clients resolve it
+// against the url they loaded the document from, the request headers are not trusted for it.
+func apiURL(publicURL string) string {
+	return strings.TrimSuffix(publicURL, "/") + "/api"
+}
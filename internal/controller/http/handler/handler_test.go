@@ -0,0 +1,89 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/maypok86/conduit/internal/testserver"
+	"github.com/stretchr/testify/require"
+)
+
+// The handlers are tested through the router of testserver, it validates the requests and the responses
+// against the specification strictly, so a handler drifting from the contract fails its test.
+
+const password = "password"
+
+func TestMain(m *testing.M) {
+	testserver.Main(m)
+}
+
+// response is a response of the api, the mismatches with the specification turn into 422 and 500.
+type response struct {
+	status int
+	header http.Header
+	body   []byte
+}
+
+// decode decodes the json body of the response into v.
+func (r response) decode(t *testing.T, v interface{}) {
+	t.Helper()
+
+	require.NoError(t, json.Unmarshal(r.body, v), string(r.body))
+}
+
+// do sends the request to the api, body is json and token authenticates the request unless empty.
+func do(t *testing.T, server *httptest.Server, method, path, token, body string) response {
+	t.Helper()
+
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+
+	request, err := http.NewRequest(method, testserver.URL(server)+path, reader)
+	require.NoError(t, err)
+
+	if body != "" {
+		request.Header.Set("Content-Type", "application/json")
+	}
+
+	if token != "" {
+		request.Header.Set("Authorization", "Token "+token)
+	}
+
+	resp, err := server.Client().Do(request)
+	require.NoError(t, err)
+
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	return response{
+		status: resp.StatusCode,
+		header: resp.Header,
+		body:   data,
+	}
+}
+
+// register registers username and returns their token.
+func register(t *testing.T, server *httptest.Server, username string) string {
+	t.Helper()
+
+	resp := do(t, server, http.MethodPost, "/users", "",
+		`{"user":{"email":"`+username+`@mail.com","username":"`+username+`","password":"`+password+`"}}`)
+	require.Equal(t, http.StatusCreated, resp.status, string(resp.body))
+
+	var body struct {
+		User struct {
+			Token string `json:"token"`
+		} `json:"user"`
+	}
+	resp.decode(t, &body)
+
+	return body.User.Token
+}
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/maypok86/conduit/pkg/health"
+)
+
+// Readiness reports whether the api can serve traffic.
+type Readiness interface {
+	Ready(ctx context.Context) health.Report
+}
+
+type healthHandler struct {
+	readiness Readiness
+}
+
+type healthDeps struct {
+	router    *gin.Engine
+	readiness Readiness
+}
+
+func newHealthHandler(deps healthDeps) {
+	handler := healthHandler{
+		readiness: deps.readiness,
+	}
+
+	deps.router.GET("/healthz", handler.getHealth)
+	deps.router.GET("/readyz", handler.getReadiness)
+}
+
+// getHealth reports that the process is up, it does not check the dependencies so a failing database
+// does not get the process restarted.
+func (h healthHandler) getHealth(c *gin.Context) {
+	c.JSON(http.StatusOK, health.Report{Status: health.StatusOK})
+}
+
+func (h healthHandler) getReadiness(c *gin.Context) {
+	report := h.readiness.Ready(c.Request.Context())
+	if !report.IsOK() {
+		c.JSON(http.StatusServiceUnavailable, report)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
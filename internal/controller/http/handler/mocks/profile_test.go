@@ -35,6 +35,36 @@ func (m *MockProfileService) EXPECT() *MockProfileServiceMockRecorder {
 	return m.recorder
 }
 
+// ApproveFollowRequest mocks base method.
+func (m *MockProfileService) ApproveFollowRequest(ctx context.Context, email, username string) (profile.Profile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApproveFollowRequest", ctx, email, username)
+	ret0, _ := ret[0].(profile.Profile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApproveFollowRequest indicates an expected call of ApproveFollowRequest.
+func (mr *MockProfileServiceMockRecorder) ApproveFollowRequest(ctx, email, username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApproveFollowRequest", reflect.TypeOf((*MockProfileService)(nil).ApproveFollowRequest), ctx, email, username)
+}
+
+// Block mocks base method.
+func (m *MockProfileService) Block(ctx context.Context, email, username string) (profile.Profile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Block", ctx, email, username)
+	ret0, _ := ret[0].(profile.Profile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Block indicates an expected call of Block.
+func (mr *MockProfileServiceMockRecorder) Block(ctx, email, username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Block", reflect.TypeOf((*MockProfileService)(nil).Block), ctx, email, username)
+}
+
 // Follow mocks base method.
 func (m *MockProfileService) Follow(ctx context.Context, email, username string) (profile.Profile, error) {
 	m.ctrl.T.Helper()
@@ -80,6 +110,96 @@ func (mr *MockProfileServiceMockRecorder) GetWithFollow(ctx, email, username int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWithFollow", reflect.TypeOf((*MockProfileService)(nil).GetWithFollow), ctx, email, username)
 }
 
+// ListFollowRequests mocks base method.
+func (m *MockProfileService) ListFollowRequests(ctx context.Context, dto profile.ListRequestsDTO) (profile.FollowsPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFollowRequests", ctx, dto)
+	ret0, _ := ret[0].(profile.FollowsPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFollowRequests indicates an expected call of ListFollowRequests.
+func (mr *MockProfileServiceMockRecorder) ListFollowRequests(ctx, dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFollowRequests", reflect.TypeOf((*MockProfileService)(nil).ListFollowRequests), ctx, dto)
+}
+
+// ListFollowers mocks base method.
+func (m *MockProfileService) ListFollowers(ctx context.Context, dto profile.ListDTO) (profile.FollowsPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFollowers", ctx, dto)
+	ret0, _ := ret[0].(profile.FollowsPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFollowers indicates an expected call of ListFollowers.
+func (mr *MockProfileServiceMockRecorder) ListFollowers(ctx, dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFollowers", reflect.TypeOf((*MockProfileService)(nil).ListFollowers), ctx, dto)
+}
+
+// ListFollowing mocks base method.
+func (m *MockProfileService) ListFollowing(ctx context.Context, dto profile.ListDTO) (profile.FollowsPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFollowing", ctx, dto)
+	ret0, _ := ret[0].(profile.FollowsPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFollowing indicates an expected call of ListFollowing.
+func (mr *MockProfileServiceMockRecorder) ListFollowing(ctx, dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFollowing", reflect.TypeOf((*MockProfileService)(nil).ListFollowing), ctx, dto)
+}
+
+// Mute mocks base method.
+func (m *MockProfileService) Mute(ctx context.Context, email, username string) (profile.Profile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Mute", ctx, email, username)
+	ret0, _ := ret[0].(profile.Profile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Mute indicates an expected call of Mute.
+func (mr *MockProfileServiceMockRecorder) Mute(ctx, email, username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Mute", reflect.TypeOf((*MockProfileService)(nil).Mute), ctx, email, username)
+}
+
+// RejectFollowRequest mocks base method.
+func (m *MockProfileService) RejectFollowRequest(ctx context.Context, email, username string) (profile.Profile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RejectFollowRequest", ctx, email, username)
+	ret0, _ := ret[0].(profile.Profile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RejectFollowRequest indicates an expected call of RejectFollowRequest.
+func (mr *MockProfileServiceMockRecorder) RejectFollowRequest(ctx, email, username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RejectFollowRequest", reflect.TypeOf((*MockProfileService)(nil).RejectFollowRequest), ctx, email, username)
+}
+
+// Unblock mocks base method.
+func (m *MockProfileService) Unblock(ctx context.Context, email, username string) (profile.Profile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unblock", ctx, email, username)
+	ret0, _ := ret[0].(profile.Profile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Unblock indicates an expected call of Unblock.
+func (mr *MockProfileServiceMockRecorder) Unblock(ctx, email, username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unblock", reflect.TypeOf((*MockProfileService)(nil).Unblock), ctx, email, username)
+}
+
 // Unfollow mocks base method.
 func (m *MockProfileService) Unfollow(ctx context.Context, email, username string) (profile.Profile, error) {
 	m.ctrl.T.Helper()
@@ -94,3 +214,18 @@ func (mr *MockProfileServiceMockRecorder) Unfollow(ctx, email, username interfac
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unfollow", reflect.TypeOf((*MockProfileService)(nil).Unfollow), ctx, email, username)
 }
+
+// Unmute mocks base method.
+func (m *MockProfileService) Unmute(ctx context.Context, email, username string) (profile.Profile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unmute", ctx, email, username)
+	ret0, _ := ret[0].(profile.Profile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Unmute indicates an expected call of Unmute.
+func (mr *MockProfileServiceMockRecorder) Unmute(ctx, email, username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unmute", reflect.TypeOf((*MockProfileService)(nil).Unmute), ctx, email, username)
+}
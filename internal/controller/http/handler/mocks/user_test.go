@@ -9,6 +9,7 @@ import (
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
+	export "github.com/maypok86/conduit/internal/domain/export"
 	user "github.com/maypok86/conduit/internal/domain/user"
 )
 
@@ -50,6 +51,21 @@ func (mr *MockUserServiceMockRecorder) Create(ctx, dto interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockUserService)(nil).Create), ctx, dto)
 }
 
+// DeleteByEmail mocks base method.
+func (m *MockUserService) DeleteByEmail(ctx context.Context, email string) (user.Deletion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByEmail", ctx, email)
+	ret0, _ := ret[0].(user.Deletion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteByEmail indicates an expected call of DeleteByEmail.
+func (mr *MockUserServiceMockRecorder) DeleteByEmail(ctx, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByEmail", reflect.TypeOf((*MockUserService)(nil).DeleteByEmail), ctx, email)
+}
+
 // GetByEmail mocks base method.
 func (m *MockUserService) GetByEmail(ctx context.Context, email string) (user.User, error) {
 	m.ctrl.T.Helper()
@@ -94,3 +110,41 @@ func (mr *MockUserServiceMockRecorder) UpdateByEmail(ctx, email, dto interface{}
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateByEmail", reflect.TypeOf((*MockUserService)(nil).UpdateByEmail), ctx, email, dto)
 }
+
+// MockExportService is a mock of ExportService interface.
+type MockExportService struct {
+	ctrl     *gomock.Controller
+	recorder *MockExportServiceMockRecorder
+}
+
+// MockExportServiceMockRecorder is the mock recorder for MockExportService.
+type MockExportServiceMockRecorder struct {
+	mock *MockExportService
+}
+
+// NewMockExportService creates a new mock instance.
+func NewMockExportService(ctrl *gomock.Controller) *MockExportService {
+	mock := &MockExportService{ctrl: ctrl}
+	mock.recorder = &MockExportServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExportService) EXPECT() *MockExportServiceMockRecorder {
+	return m.recorder
+}
+
+// Export mocks base method.
+func (m *MockExportService) Export(ctx context.Context, email string) (export.Export, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", ctx, email)
+	ret0, _ := ret[0].(export.Export)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockExportServiceMockRecorder) Export(ctx, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockExportService)(nil).Export), ctx, email)
+}
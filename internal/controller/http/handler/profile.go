@@ -19,6 +19,15 @@ type ProfileService interface {
 	GetWithFollow(ctx context.Context, email, username string) (profile.Profile, error)
 	Follow(ctx context.Context, email, username string) (profile.Profile, error)
 	Unfollow(ctx context.Context, email, username string) (profile.Profile, error)
+	ListFollowers(ctx context.Context, dto profile.ListDTO) (profile.FollowsPage, error)
+	ListFollowing(ctx context.Context, dto profile.ListDTO) (profile.FollowsPage, error)
+	Block(ctx context.Context, email, username string) (profile.Profile, error)
+	Unblock(ctx context.Context, email, username string) (profile.Profile, error)
+	Mute(ctx context.Context, email, username string) (profile.Profile, error)
+	Unmute(ctx context.Context, email, username string) (profile.Profile, error)
+	ListFollowRequests(ctx context.Context, dto profile.ListRequestsDTO) (profile.FollowsPage, error)
+	ApproveFollowRequest(ctx context.Context, email, username string) (profile.Profile, error)
+	RejectFollowRequest(ctx context.Context, email, username string) (profile.Profile, error)
 }
 
 type profileHandler struct {
@@ -39,11 +48,24 @@ func newProfileHandler(deps profileDeps) {
 	}
 
 	deps.router.GET("/profiles/:username", deps.authMiddleware.OptionalHandle, handler.getProfile)
+	deps.router.GET("/profiles/:username/followers", deps.authMiddleware.OptionalHandle, handler.listFollowers)
+	deps.router.GET("/profiles/:username/following", deps.authMiddleware.OptionalHandle, handler.listFollowing)
 
 	profilesGroup := deps.router.Group("/profiles", deps.authMiddleware.Handle)
 	{
 		profilesGroup.POST("/:username/follow", handler.follow)
 		profilesGroup.DELETE("/:username/follow", handler.unfollow)
+		profilesGroup.POST("/:username/block", handler.block)
+		profilesGroup.DELETE("/:username/block", handler.unblock)
+		profilesGroup.POST("/:username/mute", handler.mute)
+		profilesGroup.DELETE("/:username/mute", handler.unmute)
+	}
+
+	requestsGroup := deps.router.Group("/user/follow-requests", deps.authMiddleware.Handle)
+	{
+		requestsGroup.GET("", handler.listFollowRequests)
+		requestsGroup.POST("/:username/approve", handler.approveFollowRequest)
+		requestsGroup.POST("/:username/reject", handler.rejectFollowRequest)
 	}
 }
 
@@ -52,10 +74,17 @@ type getProfileRequest struct {
 }
 
 type getProfileResponse struct {
-	Username  string `json:"username"`
-	Bio       string `json:"bio"`
-	Image     string `json:"image"`
-	Following bool   `json:"following"`
+	Username       string `json:"username"`
+	Bio            string `json:"bio"`
+	Image          string `json:"image"`
+	Private        bool   `json:"private"`
+	Following      bool   `json:"following"`
+	Requested      bool   `json:"requested"`
+	Blocking       bool   `json:"blocking"`
+	Muting         bool   `json:"muting"`
+	FollowersCount int    `json:"followersCount"`
+	FollowingCount int    `json:"followingCount"`
+	RenamedFrom    string `json:"renamedFrom,omitempty"`
 }
 
 func (h profileHandler) getProfile(c *gin.Context) {
@@ -77,10 +106,17 @@ func (h profileHandler) getProfile(c *gin.Context) {
 
 		c.JSON(http.StatusOK, gin.H{
 			"profile": getProfileResponse{
-				Username:  profileEntity.Username,
-				Bio:       profileEntity.GetBio(),
-				Image:     profileEntity.GetImage(),
-				Following: profileEntity.Following,
+				Username:       profileEntity.Username,
+				Bio:            profileEntity.GetBio(),
+				Image:          profileEntity.GetImage(),
+				Private:        profileEntity.Private,
+				Following:      profileEntity.Following,
+				Requested:      profileEntity.Requested,
+				Blocking:       profileEntity.Blocking,
+				Muting:         profileEntity.Muting,
+				FollowersCount: profileEntity.FollowersCount,
+				FollowingCount: profileEntity.FollowingCount,
+				RenamedFrom:    profileEntity.RenamedFrom,
 			},
 		})
 	} else {
@@ -92,10 +128,17 @@ func (h profileHandler) getProfile(c *gin.Context) {
 
 		c.JSON(http.StatusOK, gin.H{
 			"profile": getProfileResponse{
-				Username:  profileEntity.Username,
-				Bio:       profileEntity.GetBio(),
-				Image:     profileEntity.GetImage(),
-				Following: profileEntity.Following,
+				Username:       profileEntity.Username,
+				Bio:            profileEntity.GetBio(),
+				Image:          profileEntity.GetImage(),
+				Private:        profileEntity.Private,
+				Following:      profileEntity.Following,
+				Requested:      profileEntity.Requested,
+				Blocking:       profileEntity.Blocking,
+				Muting:         profileEntity.Muting,
+				FollowersCount: profileEntity.FollowersCount,
+				FollowingCount: profileEntity.FollowingCount,
+				RenamedFrom:    profileEntity.RenamedFrom,
 			},
 		})
 	}
@@ -109,7 +152,11 @@ type followResponse struct {
 	Username  string `json:"username"`
 	Bio       string `json:"bio"`
 	Image     string `json:"image"`
+	Private   bool   `json:"private"`
 	Following bool   `json:"following"`
+	Requested bool   `json:"requested"`
+	Blocking  bool   `json:"blocking"`
+	Muting    bool   `json:"muting"`
 }
 
 func (h profileHandler) follow(c *gin.Context) {
@@ -132,7 +179,11 @@ func (h profileHandler) follow(c *gin.Context) {
 			Username:  profileEntity.Username,
 			Bio:       profileEntity.GetBio(),
 			Image:     profileEntity.GetImage(),
+			Private:   profileEntity.Private,
 			Following: profileEntity.Following,
+			Requested: profileEntity.Requested,
+			Blocking:  profileEntity.Blocking,
+			Muting:    profileEntity.Muting,
 		},
 	})
 }
@@ -145,7 +196,11 @@ type unfollowResponse struct {
 	Username  string `json:"username"`
 	Bio       string `json:"bio"`
 	Image     string `json:"image"`
+	Private   bool   `json:"private"`
 	Following bool   `json:"following"`
+	Requested bool   `json:"requested"`
+	Blocking  bool   `json:"blocking"`
+	Muting    bool   `json:"muting"`
 }
 
 func (h profileHandler) unfollow(c *gin.Context) {
@@ -168,7 +223,197 @@ func (h profileHandler) unfollow(c *gin.Context) {
 			Username:  profileEntity.Username,
 			Bio:       profileEntity.GetBio(),
 			Image:     profileEntity.GetImage(),
+			Private:   profileEntity.Private,
+			Following: profileEntity.Following,
+			Requested: profileEntity.Requested,
+			Blocking:  profileEntity.Blocking,
+			Muting:    profileEntity.Muting,
+		},
+	})
+}
+
+type listFollowsURI struct {
+	Username string `uri:"username" binding:"required"`
+}
+
+type listFollowsQuery struct {
+	Cursor string `form:"cursor"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+type listFollowsProfile struct {
+	Username  string `json:"username"`
+	Bio       string `json:"bio"`
+	Image     string `json:"image"`
+	Private   bool   `json:"private"`
+	Following bool   `json:"following"`
+	Requested bool   `json:"requested"`
+	Blocking  bool   `json:"blocking"`
+	Muting    bool   `json:"muting"`
+}
+
+type listFollowsResponse struct {
+	Profiles   []listFollowsProfile `json:"profiles"`
+	NextCursor string               `json:"nextCursor,omitempty"`
+}
+
+func (h profileHandler) listFollowers(c *gin.Context) {
+	h.listFollows(c, h.profileService.ListFollowers)
+}
+
+func (h profileHandler) listFollowing(c *gin.Context) {
+	h.listFollows(c, h.profileService.ListFollowing)
+}
+
+func (h profileHandler) listFollows(
+	c *gin.Context,
+	list func(context.Context, profile.ListDTO) (profile.FollowsPage, error),
+) {
+	var uri listFollowsURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		httperr.BadRequest(c, "invalid-request", err)
+		return
+	}
+
+	var query listFollowsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		httperr.BadRequest(c, "invalid-request", err)
+		return
+	}
+
+	dto := profile.ListDTO{
+		Username: uri.Username,
+		Cursor:   query.Cursor,
+		Limit:    query.Limit,
+	}
+	if payload := h.authMiddleware.GetPayload(c); payload != nil {
+		dto.ViewerEmail = payload.Email
+	}
+
+	page, err := list(logger.FromRequestToContext(c), dto)
+	if err != nil {
+		httperr.RespondWithSlugError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newListFollowsResponse(page))
+}
+
+func newListFollowsResponse(page profile.FollowsPage) listFollowsResponse {
+	profiles := make([]listFollowsProfile, 0, len(page.Profiles))
+	for _, profileEntity := range page.Profiles {
+		profiles = append(profiles, listFollowsProfile{
+			Username:  profileEntity.Username,
+			Bio:       profileEntity.GetBio(),
+			Image:     profileEntity.GetImage(),
+			Private:   profileEntity.Private,
+			Following: profileEntity.Following,
+			Requested: profileEntity.Requested,
+			Blocking:  profileEntity.Blocking,
+			Muting:    profileEntity.Muting,
+		})
+	}
+
+	return listFollowsResponse{
+		Profiles:   profiles,
+		NextCursor: page.NextCursor,
+	}
+}
+
+type relationshipRequest struct {
+	Username string `uri:"username" binding:"required"`
+}
+
+type relationshipResponse struct {
+	Username  string `json:"username"`
+	Bio       string `json:"bio"`
+	Image     string `json:"image"`
+	Private   bool   `json:"private"`
+	Following bool   `json:"following"`
+	Requested bool   `json:"requested"`
+	Blocking  bool   `json:"blocking"`
+	Muting    bool   `json:"muting"`
+}
+
+func (h profileHandler) block(c *gin.Context) {
+	h.changeRelationship(c, h.profileService.Block)
+}
+
+func (h profileHandler) unblock(c *gin.Context) {
+	h.changeRelationship(c, h.profileService.Unblock)
+}
+
+func (h profileHandler) mute(c *gin.Context) {
+	h.changeRelationship(c, h.profileService.Mute)
+}
+
+func (h profileHandler) unmute(c *gin.Context) {
+	h.changeRelationship(c, h.profileService.Unmute)
+}
+
+func (h profileHandler) changeRelationship(
+	c *gin.Context,
+	change func(ctx context.Context, email, username string) (profile.Profile, error),
+) {
+	var request relationshipRequest
+	if err := c.ShouldBindUri(&request); err != nil {
+		httperr.BadRequest(c, "invalid-request", err)
+		return
+	}
+
+	payload := h.authMiddleware.GetPayload(c)
+
+	profileEntity, err := change(logger.FromRequestToContext(c), payload.Email, request.Username)
+	if err != nil {
+		httperr.RespondWithSlugError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"profile": relationshipResponse{
+			Username:  profileEntity.Username,
+			Bio:       profileEntity.GetBio(),
+			Image:     profileEntity.GetImage(),
+			Private:   profileEntity.Private,
 			Following: profileEntity.Following,
+			Requested: profileEntity.Requested,
+			Blocking:  profileEntity.Blocking,
+			Muting:    profileEntity.Muting,
 		},
 	})
 }
+
+type listFollowRequestsQuery struct {
+	Cursor string `form:"cursor"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+func (h profileHandler) listFollowRequests(c *gin.Context) {
+	var query listFollowRequestsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		httperr.BadRequest(c, "invalid-request", err)
+		return
+	}
+
+	payload := h.authMiddleware.GetPayload(c)
+
+	page, err := h.profileService.ListFollowRequests(logger.FromRequestToContext(c), profile.ListRequestsDTO{
+		Email:  payload.Email,
+		Cursor: query.Cursor,
+		Limit:  query.Limit,
+	})
+	if err != nil {
+		httperr.RespondWithSlugError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newListFollowsResponse(page))
+}
+
+func (h profileHandler) approveFollowRequest(c *gin.Context) {
+	h.changeRelationship(c, h.profileService.ApproveFollowRequest)
+}
+
+func (h profileHandler) rejectFollowRequest(c *gin.Context) {
+	h.changeRelationship(c, h.profileService.RejectFollowRequest)
+}
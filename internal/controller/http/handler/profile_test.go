@@ -0,0 +1,210 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/maypok86/conduit/internal/testserver"
+	"github.com/stretchr/testify/require"
+)
+
+type profileBody struct {
+	Profile struct {
+		Username       string `json:"username"`
+		Private        bool   `json:"private"`
+		Following      bool   `json:"following"`
+		Requested      bool   `json:"requested"`
+		Blocking       bool   `json:"blocking"`
+		Muting         bool   `json:"muting"`
+		FollowersCount int    `json:"followersCount"`
+		FollowingCount int    `json:"followingCount"`
+	} `json:"profile"`
+}
+
+type followsBody struct {
+	Profiles []struct {
+		Username string `json:"username"`
+	} `json:"profiles"`
+	NextCursor string `json:"nextCursor"`
+}
+
+func (b followsBody) usernames() []string {
+	usernames := make([]string, 0, len(b.Profiles))
+	for _, p := range b.Profiles {
+		usernames = append(usernames, p.Username)
+	}
+
+	return usernames
+}
+
+// profile sends the request and decodes the profile it responds with.
+func profile(t *testing.T, server *httptest.Server, method, path, token string) profileBody {
+	t.Helper()
+
+	resp := do(t, server, method, path, token, "")
+	require.Equal(t, http.StatusOK, resp.status, string(resp.body))
+
+	var body profileBody
+	resp.decode(t, &body)
+
+	return body
+}
+
+// follows sends the request and decodes the page of profiles it responds with.
+func follows(t *testing.T, server *httptest.Server, path, token string) followsBody {
+	t.Helper()
+
+	resp := do(t, server, http.MethodGet, path, token, "")
+	require.Equal(t, http.StatusOK, resp.status, string(resp.body))
+
+	var body followsBody
+	resp.decode(t, &body)
+
+	return body
+}
+
+func TestProfileHandler_Get(t *testing.T) {
+	t.Parallel()
+
+	server := testserver.New(t)
+	token := register(t, server, "alice")
+	register(t, server, "bob")
+
+	got := profile(t, server, http.MethodGet, "/profiles/bob", "")
+	require.Equal(t, "bob", got.Profile.Username)
+	require.False(t, got.Profile.Following)
+
+	profile(t, server, http.MethodPost, "/profiles/bob/follow", token)
+
+	got = profile(t, server, http.MethodGet, "/profiles/bob", token)
+	require.True(t, got.Profile.Following)
+	require.Equal(t, 1, got.Profile.FollowersCount)
+
+	resp := do(t, server, http.MethodGet, "/profiles/carol", "", "")
+	require.Equal(t, http.StatusUnprocessableEntity, resp.status, string(resp.body))
+}
+
+func TestProfileHandler_Follow(t *testing.T) {
+	t.Parallel()
+
+	server := testserver.New(t)
+	token := register(t, server, "alice")
+	register(t, server, "bob")
+
+	resp := do(t, server, http.MethodPost, "/profiles/bob/follow", "", "")
+	require.Equal(t, http.StatusUnauthorized, resp.status)
+
+	got := profile(t, server, http.MethodPost, "/profiles/bob/follow", token)
+	require.True(t, got.Profile.Following)
+
+	got = profile(t, server, http.MethodDelete, "/profiles/bob/follow", token)
+	require.False(t, got.Profile.Following)
+
+	resp = do(t, server, http.MethodDelete, "/profiles/bob/follow", token, "")
+	require.Equal(t, http.StatusBadRequest, resp.status, string(resp.body))
+
+	resp = do(t, server, http.MethodPost, "/profiles/alice/follow", token, "")
+	require.Equal(t, http.StatusBadRequest, resp.status, string(resp.body))
+}
+
+func TestProfileHandler_BlockAndMute(t *testing.T) {
+	t.Parallel()
+
+	server := testserver.New(t)
+	aliceToken := register(t, server, "alice")
+	bobToken := register(t, server, "bob")
+
+	got := profile(t, server, http.MethodPost, "/profiles/bob/block", aliceToken)
+	require.True(t, got.Profile.Blocking)
+
+	resp := do(t, server, http.MethodPost, "/profiles/alice/follow", bobToken, "")
+	require.Equal(t, http.StatusBadRequest, resp.status, string(resp.body))
+
+	resp = do(t, server, http.MethodGet, "/profiles/alice/followers", bobToken, "")
+	require.Equal(t, http.StatusUnprocessableEntity, resp.status, string(resp.body))
+
+	got = profile(t, server, http.MethodDelete, "/profiles/bob/block", aliceToken)
+	require.False(t, got.Profile.Blocking)
+
+	resp = do(t, server, http.MethodDelete, "/profiles/bob/block", aliceToken, "")
+	require.Equal(t, http.StatusBadRequest, resp.status, string(resp.body))
+
+	got = profile(t, server, http.MethodPost, "/profiles/bob/mute", aliceToken)
+	require.True(t, got.Profile.Muting)
+
+	got = profile(t, server, http.MethodDelete, "/profiles/bob/mute", aliceToken)
+	require.False(t, got.Profile.Muting)
+
+	resp = do(t, server, http.MethodDelete, "/profiles/bob/mute", aliceToken, "")
+	require.Equal(t, http.StatusBadRequest, resp.status, string(resp.body))
+}
+
+func TestProfileHandler_ListFollows(t *testing.T) {
+	t.Parallel()
+
+	server := testserver.New(t)
+	register(t, server, "alice")
+
+	for _, username := range []string{"bob", "carol", "dave"} {
+		profile(t, server, http.MethodPost, "/profiles/alice/follow", register(t, server, username))
+	}
+
+	page := follows(t, server, "/profiles/alice/followers?limit=2", "")
+	require.Equal(t, []string{"dave", "carol"}, page.usernames())
+	require.NotEmpty(t, page.NextCursor)
+
+	page = follows(t, server, "/profiles/alice/followers?limit=2&cursor="+page.NextCursor, "")
+	require.Equal(t, []string{"bob"}, page.usernames())
+	require.Empty(t, page.NextCursor)
+
+	page = follows(t, server, "/profiles/bob/following", "")
+	require.Equal(t, []string{"alice"}, page.usernames())
+
+	resp := do(t, server, http.MethodGet, "/profiles/alice/followers?cursor=cursor", "", "")
+	require.Equal(t, http.StatusBadRequest, resp.status, string(resp.body))
+
+	resp = do(t, server, http.MethodGet, "/profiles/alice/followers?limit=1000", "", "")
+	require.Equal(t, http.StatusUnprocessableEntity, resp.status, string(resp.body))
+}
+
+func TestProfileHandler_FollowRequests(t *testing.T) {
+	t.Parallel()
+
+	server := testserver.New(t)
+	aliceToken := register(t, server, "alice")
+	bobToken := register(t, server, "bob")
+	carolToken := register(t, server, "carol")
+
+	resp := do(t, server, http.MethodPut, "/user", aliceToken, `{"user":{"private":true}}`)
+	require.Equal(t, http.StatusOK, resp.status, string(resp.body))
+
+	got := profile(t, server, http.MethodPost, "/profiles/alice/follow", bobToken)
+	require.False(t, got.Profile.Following)
+	require.True(t, got.Profile.Requested)
+
+	profile(t, server, http.MethodPost, "/profiles/alice/follow", carolToken)
+
+	page := follows(t, server, "/user/follow-requests", aliceToken)
+	require.ElementsMatch(t, []string{"bob", "carol"}, page.usernames())
+
+	resp = do(t, server, http.MethodGet, "/user/follow-requests", "", "")
+	require.Equal(t, http.StatusUnauthorized, resp.status)
+
+	profile(t, server, http.MethodPost, "/user/follow-requests/bob/approve", aliceToken)
+	profile(t, server, http.MethodPost, "/user/follow-requests/carol/reject", aliceToken)
+
+	resp = do(t, server, http.MethodPost, "/user/follow-requests/carol/approve", aliceToken, "")
+	require.Equal(t, http.StatusBadRequest, resp.status, string(resp.body))
+
+	// The follows of a private profile are visible only to the owner and the approved followers.
+	for _, token := range []string{"", carolToken} {
+		resp = do(t, server, http.MethodGet, "/profiles/alice/followers", token, "")
+		require.Equal(t, http.StatusBadRequest, resp.status, string(resp.body))
+	}
+
+	for _, token := range []string{aliceToken, bobToken} {
+		page = follows(t, server, "/profiles/alice/followers", token)
+		require.Equal(t, []string{"bob"}, page.usernames())
+	}
+}
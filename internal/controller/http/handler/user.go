@@ -3,12 +3,15 @@ package handler
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/maypok86/conduit/internal/config"
 	"github.com/maypok86/conduit/internal/controller/http/httperr"
 	"github.com/maypok86/conduit/internal/controller/http/middleware"
+	"github.com/maypok86/conduit/internal/domain/export"
 	"github.com/maypok86/conduit/internal/domain/user"
 	"github.com/maypok86/conduit/pkg/logger"
 	"go.uber.org/zap"
@@ -25,11 +28,18 @@ type UserService interface {
 	Login(ctx context.Context, email, password string) (user.User, error)
 	GetByEmail(ctx context.Context, email string) (user.User, error)
 	UpdateByEmail(ctx context.Context, email string, dto user.UpdateDTO) (user.User, error)
+	DeleteByEmail(ctx context.Context, email string) (user.Deletion, error)
+}
+
+// ExportService is a user data export service interface.
+type ExportService interface {
+	Export(ctx context.Context, email string) (export.Export, error)
 }
 
 type userHandler struct {
 	authMiddleware middleware.Auth
 	userService    UserService
+	exportService  ExportService
 	tokenMaker     TokenMaker
 }
 
@@ -37,12 +47,14 @@ type userDeps struct {
 	router         *gin.RouterGroup
 	authMiddleware middleware.Auth
 	userService    UserService
+	exportService  ExportService
 	tokenMaker     TokenMaker
 }
 
 func newUserHandler(deps userDeps) {
 	handler := userHandler{
 		userService:    deps.userService,
+		exportService:  deps.exportService,
 		tokenMaker:     deps.tokenMaker,
 		authMiddleware: deps.authMiddleware,
 	}
@@ -57,6 +69,8 @@ func newUserHandler(deps userDeps) {
 	{
 		userGroup.GET("/", handler.getCurrentUser)
 		userGroup.PUT("/", handler.updateCurrentUser)
+		userGroup.DELETE("/", handler.deleteCurrentUser)
+		userGroup.GET("/export", handler.exportCurrentUser)
 	}
 }
 
@@ -95,7 +109,7 @@ func (h userHandler) createUser(c *gin.Context) {
 		return
 	}
 
-	token, err := h.tokenMaker.CreateToken(userEntity.Email, config.Get().Token.Expired)
+	token, err := h.tokenMaker.CreateToken(userEntity.ID, userEntity.Email, config.Get().Token.Expired)
 	if err != nil {
 		httperr.RespondWithSlugError(c, err)
 		return
@@ -140,7 +154,7 @@ func (h userHandler) loginUser(c *gin.Context) {
 		return
 	}
 
-	accessToken, err := h.tokenMaker.CreateToken(userEntity.Email, config.Get().Token.Expired)
+	accessToken, err := h.tokenMaker.CreateToken(userEntity.ID, userEntity.Email, config.Get().Token.Expired)
 	if err != nil {
 		httperr.RespondWithSlugError(c, err)
 		return
@@ -162,6 +176,7 @@ type getCurrentUserResponse struct {
 	Username string `json:"username"`
 	Bio      string `json:"bio"`
 	Image    string `json:"image"`
+	Private  bool   `json:"private"`
 	Token    string `json:"token"`
 }
 
@@ -183,6 +198,7 @@ func (h userHandler) getCurrentUser(c *gin.Context) {
 			Username: userEntity.Username,
 			Bio:      userEntity.GetBio(),
 			Image:    userEntity.GetImage(),
+			Private:  userEntity.Private,
 			Token:    h.authMiddleware.GetToken(c),
 		},
 	})
@@ -195,6 +211,7 @@ type updateCurrentUserRequest struct {
 		Token    *string `json:"token"    binding:"omitempty"`
 		Bio      *string `json:"bio"      binding:"omitempty,max=1024"`
 		Image    *string `json:"image"    binding:"omitempty,url"`
+		Private  *bool   `json:"private"`
 	} `json:"user" binding:"required"`
 }
 
@@ -203,6 +220,7 @@ type updateCurrentUserResponse struct {
 	Username string `json:"username"`
 	Bio      string `json:"bio"`
 	Image    string `json:"image"`
+	Private  bool   `json:"private"`
 	Token    string `json:"token"`
 }
 
@@ -223,6 +241,10 @@ func (ucur updateCurrentUserRequest) validate() error {
 		return nil
 	}
 
+	if ucur.User.Private != nil {
+		return nil
+	}
+
 	return ErrAtLeastOneFieldRequired
 }
 
@@ -250,6 +272,7 @@ func (h userHandler) updateCurrentUser(c *gin.Context) {
 		Email:    request.User.Email,
 		Bio:      request.User.Bio,
 		Image:    request.User.Image,
+		Private:  request.User.Private,
 	})
 	if err != nil {
 		httperr.RespondWithSlugError(c, err)
@@ -267,7 +290,65 @@ func (h userHandler) updateCurrentUser(c *gin.Context) {
 			Username: userEntity.Username,
 			Bio:      userEntity.GetBio(),
 			Image:    userEntity.GetImage(),
+			Private:  userEntity.Private,
 			Token:    token,
 		},
 	})
 }
+
+type deleteCurrentUserResponse struct {
+	DeletedAt time.Time `json:"deletedAt"`
+	PurgeAt   time.Time `json:"purgeAt"`
+}
+
+func (h userHandler) deleteCurrentUser(c *gin.Context) {
+	payload := h.authMiddleware.GetPayload(c)
+	if payload == nil {
+		return
+	}
+
+	deletion, err := h.userService.DeleteByEmail(logger.FromRequestToContext(c), payload.Email)
+	if err != nil {
+		httperr.RespondWithSlugError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"deletion": deleteCurrentUserResponse{
+			DeletedAt: deletion.DeletedAt,
+			PurgeAt:   deletion.PurgeAt,
+		},
+	})
+}
+
+type exportCurrentUserResponse struct {
+	Status      export.Status `json:"status"`
+	RequestedAt time.Time     `json:"requestedAt"`
+}
+
+func (h userHandler) exportCurrentUser(c *gin.Context) {
+	payload := h.authMiddleware.GetPayload(c)
+	if payload == nil {
+		return
+	}
+
+	exportEntity, err := h.exportService.Export(logger.FromRequestToContext(c), payload.Email)
+	if err != nil {
+		httperr.RespondWithSlugError(c, err)
+		return
+	}
+
+	if exportEntity.Status != export.StatusReady {
+		c.JSON(http.StatusAccepted, gin.H{
+			"export": exportCurrentUserResponse{
+				Status:      exportEntity.Status,
+				RequestedAt: exportEntity.RequestedAt,
+			},
+		})
+
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", exportEntity.Filename))
+	c.Data(http.StatusOK, "application/zip", exportEntity.Archive)
+}
@@ -0,0 +1,180 @@
+package handler_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/maypok86/conduit/internal/testserver"
+	"github.com/stretchr/testify/require"
+)
+
+type userBody struct {
+	User struct {
+		Email    string  `json:"email"`
+		Username string  `json:"username"`
+		Bio      string  `json:"bio"`
+		Image    string  `json:"image"`
+		Private  bool    `json:"private"`
+		Token    *string `json:"token"`
+	} `json:"user"`
+}
+
+func TestUserHandler_Register(t *testing.T) {
+	t.Parallel()
+
+	server := testserver.New(t)
+	register(t, server, "taken")
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{
+			name:       "valid",
+			body:       `{"user":{"email":"alice@mail.com","username":"alice","password":"password"}}`,
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:       "username with underscore",
+			body:       `{"user":{"email":"celeb_bob@mail.com","username":"celeb_bob","password":"password"}}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid username",
+			body:       `{"user":{"email":"carol@mail.com","username":"carol/profile","password":"password"}}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "short password",
+			body:       `{"user":{"email":"dave@mail.com","username":"dave","password":"pass"}}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "missing user",
+			body:       `{}`,
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "taken username",
+			body:       `{"user":{"email":"eve@mail.com","username":"TAKEN","password":"password"}}`,
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			resp := do(t, server, http.MethodPost, "/users", "", tt.body)
+			require.Equal(t, tt.wantStatus, resp.status, string(resp.body))
+		})
+	}
+}
+
+func TestUserHandler_Login(t *testing.T) {
+	t.Parallel()
+
+	server := testserver.New(t)
+	register(t, server, "alice")
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{
+			name:       "valid",
+			body:       `{"user":{"email":"ALICE@mail.com","password":"password"}}`,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "wrong password",
+			body:       `{"user":{"email":"alice@mail.com","password":"wrong password"}}`,
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "unknown email",
+			body:       `{"user":{"email":"bob@mail.com","password":"password"}}`,
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "missing password",
+			body:       `{"user":{"email":"alice@mail.com"}}`,
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			resp := do(t, server, http.MethodPost, "/users/login", "", tt.body)
+			require.Equal(t, tt.wantStatus, resp.status, string(resp.body))
+
+			if tt.wantStatus == http.StatusOK {
+				var body userBody
+				resp.decode(t, &body)
+				require.Equal(t, "alice", body.User.Username)
+				require.NotNil(t, body.User.Token)
+			}
+		})
+	}
+}
+
+func TestUserHandler_CurrentUser(t *testing.T) {
+	t.Parallel()
+
+	server := testserver.New(t)
+	token := register(t, server, "alice")
+
+	resp := do(t, server, http.MethodGet, "/user", "", "")
+	require.Equal(t, http.StatusUnauthorized, resp.status)
+
+	resp = do(t, server, http.MethodGet, "/user", token, "")
+	require.Equal(t, http.StatusOK, resp.status, string(resp.body))
+
+	var body userBody
+	resp.decode(t, &body)
+	require.Equal(t, "alice", body.User.Username)
+	require.Equal(t, "alice@mail.com", body.User.Email)
+
+	resp = do(t, server, http.MethodPut, "/user", token,
+		`{"user":{"bio":"I work at statefarm","image":"https://example.com/alice.png","private":true}}`)
+	require.Equal(t, http.StatusOK, resp.status, string(resp.body))
+
+	body = userBody{}
+	resp.decode(t, &body)
+	require.Equal(t, "I work at statefarm", body.User.Bio)
+	require.Equal(t, "https://example.com/alice.png", body.User.Image)
+	require.True(t, body.User.Private)
+
+	resp = do(t, server, http.MethodPut, "/user", token, `{"user":{"image":"not a url"}}`)
+	require.Equal(t, http.StatusBadRequest, resp.status, string(resp.body))
+
+	resp = do(t, server, http.MethodDelete, "/user", token, "")
+	require.Equal(t, http.StatusAccepted, resp.status, string(resp.body))
+
+	resp = do(t, server, http.MethodGet, "/user", token, "")
+	require.NotEqual(t, http.StatusOK, resp.status)
+}
+
+func TestUserHandler_Export(t *testing.T) {
+	t.Parallel()
+
+	server := testserver.New(t)
+	token := register(t, server, "alice")
+
+	resp := do(t, server, http.MethodGet, "/user/export", "", "")
+	require.Equal(t, http.StatusUnauthorized, resp.status)
+
+	resp = do(t, server, http.MethodGet, "/user/export", token, "")
+	require.Equal(t, http.StatusOK, resp.status, string(resp.body))
+	require.Equal(t, "application/zip", resp.header.Get("Content-Type"))
+	require.Contains(t, resp.header.Get("Content-Disposition"), "attachment")
+	require.NotEmpty(t, resp.body)
+}
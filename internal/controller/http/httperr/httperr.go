@@ -8,6 +8,8 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/maypok86/conduit/pkg/logger"
 	"github.com/maypok86/conduit/pkg/slugerr"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
@@ -31,6 +33,23 @@ func BadRequest(c *gin.Context, slug string, err error) {
 	httpRespondWithError(c, err, slug, "Bad request", http.StatusBadRequest)
 }
 
+// TooManyRequests is a helper function to respond with too many requests error.
+func TooManyRequests(c *gin.Context, slug string, err error) {
+	httpRespondWithError(c, err, slug, "Too many requests", http.StatusTooManyRequests)
+}
+
+// Conflict is a helper function to respond with conflict error. Unlike other errors, the error message
+// is sent to the client, for example "username has already been taken".
+func Conflict(c *gin.Context, slug string, err error) {
+	logger.FromRequest(c).Warn("Conflict", zap.Error(err), zap.String("error-slug", slug))
+	recordError(c, err, slug)
+	c.AbortWithStatusJSON(http.StatusUnprocessableEntity, ErrorResponse{
+		Errors: Errors{
+			Body: []string{err.Error()},
+		},
+	})
+}
+
 // RespondWithSlugError is a helper function to respond with slug error.
 func RespondWithSlugError(c *gin.Context, err error) {
 	var slugError slugerr.SlugError
@@ -45,6 +64,10 @@ func RespondWithSlugError(c *gin.Context, err error) {
 		Unauthorised(c, slugError.Slug(), slugError)
 	case slugerr.ErrorTypeIncorrectInput:
 		BadRequest(c, slugError.Slug(), slugError)
+	case slugerr.ErrorTypeConflict:
+		Conflict(c, slugError.Slug(), slugError)
+	case slugerr.ErrorTypeTooManyRequests:
+		TooManyRequests(c, slugError.Slug(), slugError)
 	default:
 		InternalError(c, slugError.Slug(), slugError)
 	}
@@ -62,9 +85,15 @@ type ErrorResponse struct {
 
 func httpRespondWithError(c *gin.Context, err error, slug string, logMessage string, status int) {
 	logger.FromRequest(c).Warn(logMessage, zap.Error(err), zap.String("error-slug", slug))
+	recordError(c, err, slug)
 	c.AbortWithStatusJSON(status, ErrorResponse{
 		Errors: Errors{
 			Body: []string{slug},
 		},
 	})
 }
+
+// recordError adds the error to the span of the request, so the trace shows why the request failed.
+func recordError(c *gin.Context, err error, slug string) {
+	trace.SpanFromContext(c.Request.Context()).RecordError(err, trace.WithAttributes(attribute.String("error.slug", slug)))
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/maypok86/conduit/pkg/logger"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// AccessLog is a middleware that sets the request logger and logs one line per request with its outcome.
+// The client ip is taken from the forwarding headers only behind the trusted proxies of the router.
+func AccessLog(l *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		startedAt := time.Now()
+
+		fields := []zap.Field{
+			zap.String("request_id", RequestIDFromContext(c.Request.Context())),
+			zap.String("endpoint", c.Request.URL.Path),
+			zap.String("method", c.Request.Method),
+			zap.String("client_ip", c.ClientIP()),
+		}
+
+		// The span context is set by the tracing middleware from the traceparent header or a new trace.
+		if spanContext := trace.SpanContextFromContext(c.Request.Context()); spanContext.IsValid() {
+			fields = append(
+				fields,
+				zap.String("trace_id", spanContext.TraceID().String()),
+				zap.String("span_id", spanContext.SpanID().String()),
+			)
+		}
+
+		requestLogger := l.With(fields...)
+		logger.RequestWithLogger(c, requestLogger)
+		c.Request = c.Request.WithContext(logger.ContextWithLogger(c.Request.Context(), requestLogger))
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+
+		// The size is negative when nothing is written.
+		bytes := c.Writer.Size()
+		if bytes < 0 {
+			bytes = 0
+		}
+
+		status := c.Writer.Status()
+		outcome := []zap.Field{
+			zap.Int("status", status),
+			zap.Duration("latency", time.Since(startedAt)),
+			zap.Int("bytes", bytes),
+			zap.String("user_id", c.GetString(authenticatedUserKey)),
+			zap.String("route", route),
+			zap.String("user_agent", c.Request.UserAgent()),
+		}
+
+		if status >= http.StatusInternalServerError {
+			requestLogger.Error("request", outcome...)
+
+			return
+		}
+
+		requestLogger.Info("request", outcome...)
+	}
+}
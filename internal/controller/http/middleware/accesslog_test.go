@@ -0,0 +1,137 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/maypok86/conduit/internal/controller/http/middleware"
+	"github.com/maypok86/conduit/pkg/token"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+)
+
+func TestAccessLog(t *testing.T) {
+	t.Parallel()
+
+	tokenMaker, err := token.NewJWTMaker("access-log-middleware-test-secret-key")
+	require.NoError(t, err)
+
+	userID := uuid.New()
+	accessToken, err := tokenMaker.CreateToken(userID, "jake@jake.jake", time.Minute)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		path          string
+		authorization string
+		remoteAddr    string
+		forwardedFor  string
+		wantLevel     zapcore.Level
+		wantFields    map[string]interface{}
+	}{
+		{
+			name:          "authenticated",
+			path:          "/api/profiles/jake",
+			authorization: "Token " + accessToken,
+			remoteAddr:    "192.0.2.1:1234",
+			wantLevel:     zapcore.InfoLevel,
+			wantFields: map[string]interface{}{
+				"status":     int64(http.StatusOK),
+				"bytes":      int64(len(`{"username":"jake"}`)),
+				"user_id":    userID.String(),
+				"route":      "/api/profiles/:username",
+				"client_ip":  "192.0.2.1",
+				"user_agent": "conduit-test",
+				"request_id": "access-log-request",
+			},
+		},
+		{
+			name:         "trusted proxy",
+			path:         "/missing",
+			remoteAddr:   "10.0.0.1:1234",
+			forwardedFor: "203.0.113.7",
+			wantLevel:    zapcore.InfoLevel,
+			wantFields: map[string]interface{}{
+				"status":    int64(http.StatusNotFound),
+				"user_id":   "",
+				"route":     "unmatched",
+				"client_ip": "203.0.113.7",
+			},
+		},
+		{
+			name:         "untrusted proxy",
+			path:         "/missing",
+			remoteAddr:   "192.0.2.1:1234",
+			forwardedFor: "203.0.113.7",
+			wantLevel:    zapcore.InfoLevel,
+			wantFields: map[string]interface{}{
+				"client_ip": "192.0.2.1",
+			},
+		},
+		{
+			name:       "panic",
+			path:       "/panic",
+			remoteAddr: "192.0.2.1:1234",
+			wantLevel:  zapcore.ErrorLevel,
+			wantFields: map[string]interface{}{
+				"status": int64(http.StatusInternalServerError),
+				"bytes":  int64(0),
+				"route":  "/panic",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			core, logs := zapobserver.New(zapcore.InfoLevel)
+
+			router := gin.New()
+			require.NoError(t, router.SetTrustedProxies([]string{"10.0.0.0/8"}))
+			router.Use(middleware.RequestID(), middleware.AccessLog(zap.New(core)), gin.Recovery())
+
+			auth := middleware.NewAuth(tokenMaker)
+			router.GET("/api/profiles/:username", auth.OptionalHandle, func(c *gin.Context) {
+				c.JSON(http.StatusOK, gin.H{"username": c.Param("username")})
+			})
+			router.GET("/panic", func(c *gin.Context) {
+				panic("handler failed")
+			})
+
+			request := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			request.RemoteAddr = tt.remoteAddr
+			request.Header.Set("User-Agent", "conduit-test")
+			request.Header.Set(middleware.RequestIDHeader, "access-log-request")
+
+			if tt.authorization != "" {
+				request.Header.Set("Authorization", tt.authorization)
+			}
+
+			if tt.forwardedFor != "" {
+				request.Header.Set("X-Forwarded-For", tt.forwardedFor)
+			}
+
+			router.ServeHTTP(httptest.NewRecorder(), request)
+
+			entries := logs.FilterMessage("request").All()
+			require.Len(t, entries, 1)
+			require.Equal(t, tt.wantLevel, entries[0].Level)
+
+			fields := entries[0].ContextMap()
+			require.Contains(t, fields, "latency")
+
+			for key, want := range tt.wantFields {
+				require.Equal(t, want, fields[key], key)
+			}
+		})
+	}
+}
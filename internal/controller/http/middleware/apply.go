@@ -8,7 +8,10 @@ import (
 
 // ApplyMiddlewares applies middlewares to the given router.
 func ApplyMiddlewares(router *gin.Engine, l *zap.Logger) {
+	router.Use(RequestID())
+	// The recovery middleware goes after the access log, so the panics are logged with the 500 status.
+	router.Use(AccessLog(l))
 	router.Use(gin.Recovery())
 	router.Use(corsMiddleware())
-	router.Use(logMiddleware(l))
+	router.Use(sessionMiddleware())
 }
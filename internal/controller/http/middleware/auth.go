@@ -7,10 +7,17 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/maypok86/conduit/internal/controller/http/httperr"
 	"github.com/maypok86/conduit/pkg/token"
 )
 
+const emptyTokenSlug = "empty-token"
+
+// authenticatedUserKey is the key of the id of the authenticated user in the request, the access log
+// reads it after the request is handled.
+const authenticatedUserKey = "authenticated_user"
+
 var (
 	errAuthHeaderNotProvided   = errors.New("authorization header is not provided")
 	errInvalidAuthHeaderFormat = errors.New("invalid authorization header format")
@@ -20,10 +27,25 @@ var (
 
 // TokenMaker is a token maker.
 type TokenMaker interface {
-	CreateToken(email string, duration time.Duration) (string, error)
+	CreateToken(userID uuid.UUID, email string, duration time.Duration) (string, error)
 	VerifyToken(accessToken string) (*token.Payload, error)
 }
 
+// TokenFailureObserver records the rejected tokens.
+type TokenFailureObserver interface {
+	ObserveTokenFailure(slug string)
+}
+
+// AuthOption is an option for the Auth middleware.
+type AuthOption func(a *Auth)
+
+// WithTokenFailureObserver sets the observer of the rejected tokens.
+func WithTokenFailureObserver(observer TokenFailureObserver) AuthOption {
+	return func(a *Auth) {
+		a.failureObserver = observer
+	}
+}
+
 // Auth is a middleware that parses the authorization header and sets the payload to the context.
 type Auth struct {
 	authorizationHeaderKey  string
@@ -31,17 +53,23 @@ type Auth struct {
 	authorizationPayloadKey string
 	authorizationTokenKey   string
 	tokenMaker              TokenMaker
+	failureObserver         TokenFailureObserver
 }
 
 // NewAuth returns a new Auth middleware.
-func NewAuth(tokenMaker TokenMaker) Auth {
-	return Auth{
+func NewAuth(tokenMaker TokenMaker, opts ...AuthOption) Auth {
+	a := Auth{
 		tokenMaker:              tokenMaker,
 		authorizationHeaderKey:  "Authorization",
 		authorizationType:       "token",
 		authorizationPayloadKey: "authorization_payload",
 		authorizationTokenKey:   "authorization_token",
 	}
+	for _, opt := range opts {
+		opt(&a)
+	}
+
+	return a
 }
 
 type authError struct {
@@ -65,21 +93,37 @@ func (a Auth) handle(c *gin.Context, processAuthError func(c *gin.Context, authE
 
 	c.Set(a.authorizationTokenKey, accessToken)
 	c.Set(a.authorizationPayloadKey, payload)
+	if payload.UserID != uuid.Nil {
+		c.Set(authenticatedUserKey, payload.UserID.String())
+	}
+
 	c.Next()
 }
 
 // OptionalHandle is a middleware that optional parses the authorization header and sets the payload to the context.
 func (a Auth) OptionalHandle(c *gin.Context) {
-	a.handle(c, func(c *gin.Context, authErr *authError) {})
+	a.handle(c, func(c *gin.Context, authErr *authError) {
+		// A missing token is fine for the optional authorization.
+		if authErr.slug != emptyTokenSlug {
+			a.observeFailure(authErr.slug)
+		}
+	})
 }
 
 // Handle is a middleware that parses the authorization header and sets the payload to the context.
 func (a Auth) Handle(c *gin.Context) {
 	a.handle(c, func(c *gin.Context, authErr *authError) {
+		a.observeFailure(authErr.slug)
 		httperr.Unauthorised(c, authErr.slug, authErr.err)
 	})
 }
 
+func (a Auth) observeFailure(slug string) {
+	if a.failureObserver != nil {
+		a.failureObserver.ObserveTokenFailure(slug)
+	}
+}
+
 // GetPayload returns the payload from the context.
 func (a Auth) GetPayload(c *gin.Context) *token.Payload {
 	v, exists := c.Get(a.authorizationPayloadKey)
@@ -111,7 +155,7 @@ func (a Auth) parseAuthHeader(c *gin.Context) (*token.Payload, string, *authErro
 
 	authorizationHeader := c.GetHeader(a.authorizationHeaderKey)
 	if authorizationHeader == "" {
-		return nil, "", a.wrapError(errAuthHeaderNotProvided, "empty-token")
+		return nil, "", a.wrapError(errAuthHeaderNotProvided, emptyTokenSlug)
 	}
 
 	fields := strings.Fields(authorizationHeader)
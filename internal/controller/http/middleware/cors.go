@@ -20,7 +20,8 @@ func corsMiddleware() gin.HandlerFunc {
 			http.MethodDelete,
 			http.MethodOptions,
 		},
-		AllowHeaders:     []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
+		AllowHeaders:     []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", RequestIDHeader},
+		ExposeHeaders:    []string{RequestIDHeader},
 		AllowCredentials: true,
 	})
 
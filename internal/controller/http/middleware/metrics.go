@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// unmatchedRoute is the route of the requests that match no route, their paths are not used as labels
+// to keep the number of series bounded.
+const unmatchedRoute = "unmatched"
+
+// RequestObserver records the handled requests.
+type RequestObserver interface {
+	ObserveRequest(route, method, status string, duration time.Duration)
+}
+
+// Metrics is a middleware that records the route template, method, status and latency of every request.
+func Metrics(observer RequestObserver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		startedAt := time.Now()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+
+		observer.ObserveRequest(route, c.Request.Method, strconv.Itoa(c.Writer.Status()), time.Since(startedAt))
+	}
+}
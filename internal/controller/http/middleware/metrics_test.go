@@ -0,0 +1,121 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/maypok86/conduit/internal/controller/http/middleware"
+	"github.com/maypok86/conduit/pkg/token"
+	"github.com/stretchr/testify/require"
+)
+
+type observedRequest struct {
+	route  string
+	method string
+	status string
+}
+
+type observer struct {
+	mu            sync.Mutex
+	requests      []observedRequest
+	tokenFailures []string
+}
+
+func (o *observer) ObserveRequest(route, method, status string, _ time.Duration) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.requests = append(o.requests, observedRequest{route: route, method: method, status: status})
+}
+
+func (o *observer) ObserveTokenFailure(slug string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.tokenFailures = append(o.tokenFailures, slug)
+}
+
+func TestMetrics(t *testing.T) {
+	t.Parallel()
+
+	tokenMaker, err := token.NewJWTMaker("metrics-middleware-test-secret-key")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name              string
+		path              string
+		authorization     string
+		wantRequest       observedRequest
+		wantTokenFailures []string
+	}{
+		{
+			name:        "route template",
+			path:        "/api/profiles/jake",
+			wantRequest: observedRequest{route: "/api/profiles/:username", method: http.MethodGet, status: "200"},
+		},
+		{
+			name:        "unmatched route",
+			path:        "/api/unknown/jake",
+			wantRequest: observedRequest{route: "unmatched", method: http.MethodGet, status: "404"},
+		},
+		{
+			name:              "missing token",
+			path:              "/api/user/",
+			wantRequest:       observedRequest{route: "/api/user/", method: http.MethodGet, status: "401"},
+			wantTokenFailures: []string{"empty-token"},
+		},
+		{
+			name:              "invalid token",
+			path:              "/api/user/",
+			authorization:     "Token invalid",
+			wantRequest:       observedRequest{route: "/api/user/", method: http.MethodGet, status: "401"},
+			wantTokenFailures: []string{"unable-to-verify-jwt"},
+		},
+		{
+			name:        "missing optional token",
+			path:        "/api/profiles/jake",
+			wantRequest: observedRequest{route: "/api/profiles/:username", method: http.MethodGet, status: "200"},
+		},
+		{
+			name:              "invalid optional token",
+			path:              "/api/profiles/jake",
+			authorization:     "Bearer invalid",
+			wantRequest:       observedRequest{route: "/api/profiles/:username", method: http.MethodGet, status: "200"},
+			wantTokenFailures: []string{"unsupported-token"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			o := &observer{}
+			auth := middleware.NewAuth(tokenMaker, middleware.WithTokenFailureObserver(o))
+
+			router := gin.New()
+			router.Use(middleware.Metrics(o))
+			router.GET("/api/profiles/:username", auth.OptionalHandle, func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+			router.GET("/api/user/", auth.Handle, func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			request := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.authorization != "" {
+				request.Header.Set("Authorization", tt.authorization)
+			}
+
+			router.ServeHTTP(httptest.NewRecorder(), request)
+
+			require.Equal(t, []observedRequest{tt.wantRequest}, o.requests)
+			require.Equal(t, tt.wantTokenFailures, o.tokenFailures)
+		})
+	}
+}
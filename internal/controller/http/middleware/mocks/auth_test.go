@@ -9,6 +9,7 @@ import (
 	time "time"
 
 	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
 	token "github.com/maypok86/conduit/pkg/token"
 )
 
@@ -36,18 +37,18 @@ func (m *MockTokenMaker) EXPECT() *MockTokenMakerMockRecorder {
 }
 
 // CreateToken mocks base method.
-func (m *MockTokenMaker) CreateToken(email string, duration time.Duration) (string, error) {
+func (m *MockTokenMaker) CreateToken(userID uuid.UUID, email string, duration time.Duration) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateToken", email, duration)
+	ret := m.ctrl.Call(m, "CreateToken", userID, email, duration)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateToken indicates an expected call of CreateToken.
-func (mr *MockTokenMakerMockRecorder) CreateToken(email, duration interface{}) *gomock.Call {
+func (mr *MockTokenMakerMockRecorder) CreateToken(userID, email, duration interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateToken", reflect.TypeOf((*MockTokenMaker)(nil).CreateToken), email, duration)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateToken", reflect.TypeOf((*MockTokenMaker)(nil).CreateToken), userID, email, duration)
 }
 
 // VerifyToken mocks base method.
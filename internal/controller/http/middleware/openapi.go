@@ -0,0 +1,211 @@
+package middleware
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/gin-gonic/gin"
+	"github.com/maypok86/conduit/internal/controller/http/httperr"
+	"github.com/maypok86/conduit/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// binaryContentTypes are the binary media types of the api, kin-openapi decodes only octet-stream out of the box.
+var binaryContentTypes = []string{"application/zip"}
+
+// registerDecoders registers the decoders once, the registry of kin-openapi is global and not synchronized.
+var registerDecoders sync.Once
+
+// OpenAPIOption is an option for the OpenAPI middleware.
+type OpenAPIOption func(o *OpenAPI)
+
+// WithStrictValidation makes mismatches fail the requests instead of only logging them. Invalid requests
+// are answered with 422 and invalid responses are replaced with 500.
+func WithStrictValidation(strict bool) OpenAPIOption {
+	return func(o *OpenAPI) {
+		o.strict = strict
+	}
+}
+
+// WithResponseValidation makes the middleware validate the responses too. The responses are buffered
+// until they are validated.
+func WithResponseValidation(validateResponses bool) OpenAPIOption {
+	return func(o *OpenAPI) {
+		o.validateResponses = validateResponses
+	}
+}
+
+// OpenAPI is a middleware that validates the api traffic against the OpenAPI specification, so the handlers
+// can not drift from it unnoticed. Authentication is left to the Auth middleware.
+type OpenAPI struct {
+	router            routers.Router
+	strict            bool
+	validateResponses bool
+}
+
+// NewOpenAPI returns a new OpenAPI middleware for the specification in yaml or json.
+func NewOpenAPI(spec []byte, opts ...OpenAPIOption) (OpenAPI, error) {
+	loader := openapi3.NewLoader()
+
+	doc, err := loader.LoadFromData(spec)
+	if err != nil {
+		return OpenAPI{}, fmt.Errorf("can not load openapi spec: %w", err)
+	}
+
+	if err := doc.Validate(loader.Context); err != nil {
+		return OpenAPI{}, fmt.Errorf("can not validate openapi spec: %w", err)
+	}
+
+	registerDecoders.Do(func() {
+		for _, contentType := range binaryContentTypes {
+			openapi3filter.RegisterBodyDecoder(contentType, openapi3filter.FileBodyDecoder)
+		}
+	})
+
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		return OpenAPI{}, fmt.Errorf("can not create openapi router: %w", err)
+	}
+
+	o := OpenAPI{
+		router: router,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o, nil
+}
+
+// Handle is a middleware that validates the request and, if enabled, the response.
+func (o OpenAPI) Handle(c *gin.Context) {
+	// The routes are registered with a trailing slash, the specification has the paths without it.
+	request := *c.Request
+	requestURL := *c.Request.URL
+	requestURL.Path = strings.TrimSuffix(requestURL.Path, "/")
+	request.URL = &requestURL
+
+	route, pathParams, err := o.router.FindRoute(&request)
+	if err != nil {
+		if !o.reject(c, "openapi-route-not-found", err, httperr.InternalError) {
+			c.Next()
+		}
+
+		return
+	}
+
+	input := &openapi3filter.RequestValidationInput{
+		Request:    &request,
+		PathParams: pathParams,
+		Route:      route,
+		Options: &openapi3filter.Options{
+			AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+		},
+	}
+
+	err = openapi3filter.ValidateRequest(c.Request.Context(), input)
+	// The validation reads the body and puts a copy back for the handler.
+	c.Request.Body = request.Body
+	if err != nil && o.reject(c, "openapi-request-mismatch", err, httperr.UnprocessableEntity) {
+		return
+	}
+
+	if !o.validateResponses {
+		c.Next()
+
+		return
+	}
+
+	writer := &bufferedWriter{ResponseWriter: c.Writer, status: http.StatusOK}
+	c.Writer = writer
+	c.Next()
+	c.Writer = writer.ResponseWriter
+
+	err = openapi3filter.ValidateResponse(c.Request.Context(), (&openapi3filter.ResponseValidationInput{
+		RequestValidationInput: input,
+		Status:                 writer.status,
+		Header:                 writer.Header(),
+		Options: &openapi3filter.Options{
+			IncludeResponseStatus: true,
+		},
+	}).SetBodyBytes(writer.body.Bytes()))
+	if err != nil && o.reject(c, "openapi-response-mismatch", err, httperr.InternalError) {
+		return
+	}
+
+	writer.flush()
+}
+
+// reject fails the request with respond in the strict mode and logs the mismatch otherwise.
+// It reports whether the request was failed.
+func (o OpenAPI) reject(c *gin.Context, slug string, err error, respond func(*gin.Context, string, error)) bool {
+	if o.strict {
+		respond(c, slug, err)
+
+		return true
+	}
+
+	logger.FromRequest(c).Warn("OpenAPI mismatch", zap.Error(err), zap.String("error-slug", slug))
+
+	return false
+}
+
+// bufferedWriter holds the response back until it is validated.
+type bufferedWriter struct {
+	gin.ResponseWriter
+	body    bytes.Buffer
+	status  int
+	written bool
+}
+
+func (w *bufferedWriter) WriteHeader(code int) {
+	if !w.written {
+		w.status = code
+	}
+}
+
+func (w *bufferedWriter) WriteHeaderNow() {
+	w.written = true
+}
+
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	w.written = true
+
+	return w.body.Write(data) //nolint:wrapcheck
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	w.written = true
+
+	return w.body.WriteString(s) //nolint:wrapcheck
+}
+
+func (w *bufferedWriter) Status() int {
+	return w.status
+}
+
+func (w *bufferedWriter) Size() int {
+	if !w.written {
+		return -1
+	}
+
+	return w.body.Len()
+}
+
+func (w *bufferedWriter) Written() bool {
+	return w.written
+}
+
+// flush writes the held response.
+func (w *bufferedWriter) flush() {
+	w.ResponseWriter.WriteHeader(w.status)
+	w.ResponseWriter.WriteHeaderNow()
+	_, _ = w.ResponseWriter.Write(w.body.Bytes())
+}
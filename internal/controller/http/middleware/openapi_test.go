@@ -0,0 +1,221 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/maypok86/conduit/api/openapi"
+	"github.com/maypok86/conduit/internal/controller/http/middleware"
+	"github.com/stretchr/testify/require"
+)
+
+const spec = `
+openapi: 3.0.1
+info:
+  title: Echo
+  version: 1.0.0
+servers:
+  - url: /api
+paths:
+  /echo:
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Echo'
+      responses:
+        200:
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Echo'
+  /archive:
+    get:
+      responses:
+        200:
+          description: OK
+          content:
+            application/zip:
+              schema:
+                type: string
+                format: binary
+components:
+  schemas:
+    Echo:
+      type: object
+      required:
+        - message
+      properties:
+        message:
+          type: string
+`
+
+// archive is an empty zip archive.
+var archive = []byte{'P', 'K', 5, 6, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}
+
+func newRouter(t *testing.T, response gin.H, opts ...middleware.OpenAPIOption) *gin.Engine {
+	t.Helper()
+
+	openAPI, err := middleware.NewOpenAPI([]byte(spec), opts...)
+	require.NoError(t, err)
+
+	router := gin.New()
+	api := router.Group("/api", openAPI.Handle)
+	api.POST("/echo", func(c *gin.Context) {
+		c.JSON(http.StatusOK, response)
+	})
+	api.GET("/undocumented", func(c *gin.Context) {
+		c.JSON(http.StatusOK, response)
+	})
+	api.GET("/archive", func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/zip", archive)
+	})
+
+	return router
+}
+
+func TestNewOpenAPI(t *testing.T) {
+	t.Parallel()
+
+	_, err := middleware.NewOpenAPI(openapi.Spec)
+	require.NoError(t, err)
+
+	_, err = middleware.NewOpenAPI([]byte("openapi: 3.0.1\npaths: {}"))
+	require.Error(t, err)
+}
+
+func TestOpenAPI_Handle(t *testing.T) {
+	t.Parallel()
+
+	validResponse := gin.H{"message": "hello"}
+	invalidResponse := gin.H{"text": "hello"}
+	strict := []middleware.OpenAPIOption{
+		middleware.WithStrictValidation(true),
+		middleware.WithResponseValidation(true),
+	}
+
+	tests := []struct {
+		name       string
+		opts       []middleware.OpenAPIOption
+		method     string
+		path       string
+		body       string
+		response   gin.H
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "valid request and response",
+			opts:       strict,
+			method:     http.MethodPost,
+			path:       "/api/echo",
+			body:       `{"message":"hello"}`,
+			response:   validResponse,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"message":"hello"}`,
+		},
+		{
+			name:       "strict invalid request",
+			opts:       []middleware.OpenAPIOption{middleware.WithStrictValidation(true)},
+			method:     http.MethodPost,
+			path:       "/api/echo",
+			body:       `{"text":"hello"}`,
+			response:   validResponse,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantBody:   `{"errors":{"body":["openapi-request-mismatch"]}}`,
+		},
+		{
+			name:       "strict invalid response",
+			opts:       strict,
+			method:     http.MethodPost,
+			path:       "/api/echo",
+			body:       `{"message":"hello"}`,
+			response:   invalidResponse,
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   `{"errors":{"body":["openapi-response-mismatch"]}}`,
+		},
+		{
+			name:       "strict undocumented route",
+			opts:       []middleware.OpenAPIOption{middleware.WithStrictValidation(true)},
+			method:     http.MethodGet,
+			path:       "/api/undocumented",
+			response:   validResponse,
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   `{"errors":{"body":["openapi-route-not-found"]}}`,
+		},
+		{
+			name:       "logged invalid request",
+			method:     http.MethodPost,
+			path:       "/api/echo",
+			body:       `{"text":"hello"}`,
+			response:   validResponse,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"message":"hello"}`,
+		},
+		{
+			name:       "logged invalid response",
+			opts:       []middleware.OpenAPIOption{middleware.WithResponseValidation(true)},
+			method:     http.MethodPost,
+			path:       "/api/echo",
+			body:       `{"message":"hello"}`,
+			response:   invalidResponse,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"text":"hello"}`,
+		},
+		{
+			name:       "responses are not validated by default",
+			opts:       []middleware.OpenAPIOption{middleware.WithStrictValidation(true)},
+			method:     http.MethodPost,
+			path:       "/api/echo",
+			body:       `{"message":"hello"}`,
+			response:   invalidResponse,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"text":"hello"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			router := newRouter(t, tt.response, tt.opts...)
+
+			request := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			request.Header.Set("Content-Type", "application/json")
+			recorder := httptest.NewRecorder()
+
+			router.ServeHTTP(recorder, request)
+
+			require.Equal(t, tt.wantStatus, recorder.Code)
+			require.JSONEq(t, tt.wantBody, recorder.Body.String())
+		})
+	}
+}
+
+func TestOpenAPI_HandleZip(t *testing.T) {
+	t.Parallel()
+
+	router := newRouter(
+		t,
+		nil,
+		middleware.WithStrictValidation(true),
+		middleware.WithResponseValidation(true),
+	)
+
+	request := httptest.NewRequest(http.MethodGet, "/api/archive", nil)
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "application/zip", recorder.Header().Get("Content-Type"))
+	require.Equal(t, archive, recorder.Body.Bytes())
+}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader is the header of the request id, it is accepted from the clients and echoed in the responses.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds the accepted request ids, so the clients can not flood the logs.
+const maxRequestIDLength = 128
+
+type ctxRequestID struct{}
+
+// RequestID is a middleware that sets the request id to the request context and the response. The id of
+// the client is kept when it is valid, a new one is generated otherwise.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !isValidRequestID(requestID) {
+			requestID = uuid.NewString()
+		}
+
+		c.Header(RequestIDHeader, requestID)
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), ctxRequestID{}, requestID))
+		c.Next()
+	}
+}
+
+// RequestIDFromContext returns the request id from the context, it is empty outside of the requests.
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(ctxRequestID{}).(string)
+
+	return requestID
+}
+
+// isValidRequestID check that the request id is short and consists of printable ASCII characters without spaces.
+func isValidRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+
+	for i := 0; i < len(requestID); i++ {
+		if requestID[i] <= ' ' || requestID[i] > '~' {
+			return false
+		}
+	}
+
+	return true
+}
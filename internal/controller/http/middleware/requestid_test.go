@@ -0,0 +1,77 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/maypok86/conduit/internal/controller/http/middleware"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestID(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		requestID string
+		wantKept  bool
+	}{
+		{
+			name:      "accepted",
+			requestID: "b7c1a2f0-request",
+			wantKept:  true,
+		},
+		{
+			name: "generated",
+		},
+		{
+			name:      "too long",
+			requestID: strings.Repeat("a", 129),
+		},
+		{
+			name:      "not printable",
+			requestID: "bad id",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var fromContext string
+
+			router := gin.New()
+			router.Use(middleware.RequestID())
+			router.GET("/", func(c *gin.Context) {
+				fromContext = middleware.RequestIDFromContext(c.Request.Context())
+				c.Status(http.StatusNoContent)
+			})
+
+			request := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.requestID != "" {
+				request.Header.Set(middleware.RequestIDHeader, tt.requestID)
+			}
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+
+			requestID := recorder.Header().Get(middleware.RequestIDHeader)
+			require.Equal(t, requestID, fromContext)
+
+			if tt.wantKept {
+				require.Equal(t, tt.requestID, requestID)
+
+				return
+			}
+
+			_, err := uuid.Parse(requestID)
+			require.NoError(t, err)
+		})
+	}
+}
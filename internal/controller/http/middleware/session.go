@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/gin-gonic/gin"
+	"github.com/maypok86/conduit/pkg/postgres"
+)
+
+// sessionMiddleware starts a postgres session for the request, so reads after a write see the write
+// even when they are routed to replicas. Clients are told apart by the hash of their authorization header,
+// the tokens are not kept in memory. Anonymous requests get no session.
+func sessionMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authorization := c.GetHeader("Authorization")
+		if authorization == "" {
+			c.Next()
+
+			return
+		}
+
+		key := sha256.Sum256([]byte(authorization))
+		c.Request = c.Request.WithContext(postgres.WithSession(c.Request.Context(), hex.EncodeToString(key[:])))
+		c.Next()
+	}
+}
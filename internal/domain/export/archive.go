@@ -0,0 +1,130 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type profileJSON struct {
+	Username  string    `json:"username"`
+	Email     string    `json:"email"`
+	Bio       string    `json:"bio"`
+	Image     string    `json:"image"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type followJSON struct {
+	Username  string    `json:"username"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type dataJSON struct {
+	Profile   profileJSON  `json:"profile"`
+	Following []followJSON `json:"following"`
+	Followers []followJSON `json:"followers"`
+}
+
+// Filename returns the name of the export archive.
+func (d Data) Filename() string {
+	return fmt.Sprintf("conduit-export-%s.zip", d.User.Username)
+}
+
+// Archive builds a zip archive with the data in JSON and Markdown.
+func (d Data) Archive() ([]byte, error) {
+	jsonData, err := json.MarshalIndent(d.toJSON(), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal export data: %w", err)
+	}
+
+	var buffer bytes.Buffer
+
+	archive := zip.NewWriter(&buffer)
+
+	files := []struct {
+		name    string
+		content []byte
+	}{
+		{name: "data.json", content: jsonData},
+		{name: "README.md", content: []byte(d.markdown())},
+	}
+
+	for _, file := range files {
+		w, err := archive.Create(file.name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create %s in export archive: %w", file.name, err)
+		}
+
+		if _, err := w.Write(file.content); err != nil {
+			return nil, fmt.Errorf("failed to write %s to export archive: %w", file.name, err)
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close export archive: %w", err)
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func (d Data) toJSON() dataJSON {
+	return dataJSON{
+		Profile: profileJSON{
+			Username:  d.User.Username,
+			Email:     d.User.Email,
+			Bio:       d.User.GetBio(),
+			Image:     d.User.GetImage(),
+			CreatedAt: d.User.CreatedAt,
+			UpdatedAt: d.User.UpdatedAt,
+		},
+		Following: followsToJSON(d.Following),
+		Followers: followsToJSON(d.Followers),
+	}
+}
+
+func followsToJSON(follows []Follow) []followJSON {
+	result := make([]followJSON, 0, len(follows))
+	for _, follow := range follows {
+		result = append(result, followJSON(follow))
+	}
+
+	return result
+}
+
+func (d Data) markdown() string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "# Conduit data export for %s\n\n", d.User.Username)
+
+	sb.WriteString("## Profile\n\n")
+	fmt.Fprintf(&sb, "- Username: %s\n", d.User.Username)
+	fmt.Fprintf(&sb, "- Email: %s\n", d.User.Email)
+	fmt.Fprintf(&sb, "- Bio: %s\n", d.User.GetBio())
+	fmt.Fprintf(&sb, "- Image: %s\n", d.User.GetImage())
+	fmt.Fprintf(&sb, "- Registered: %s\n", d.User.CreatedAt.Format(time.RFC3339))
+
+	writeFollows(&sb, "Following", d.Following)
+	writeFollows(&sb, "Followers", d.Followers)
+
+	return sb.String()
+}
+
+func writeFollows(sb *strings.Builder, title string, follows []Follow) {
+	fmt.Fprintf(sb, "\n## %s (%d)\n\n", title, len(follows))
+
+	if len(follows) == 0 {
+		sb.WriteString("_None_\n")
+
+		return
+	}
+
+	sb.WriteString("| Username | Since |\n| --- | --- |\n")
+
+	for _, follow := range follows {
+		fmt.Fprintf(sb, "| %s | %s |\n", follow.Username, follow.CreatedAt.Format(time.RFC3339))
+	}
+}
@@ -0,0 +1,56 @@
+// Package export represents a user data export domain.
+package export
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maypok86/conduit/internal/domain/user"
+)
+
+// ErrNotFound is an error that indicates that export not found.
+var ErrNotFound = errors.New("export not found")
+
+// Status is an export status.
+type Status string
+
+const (
+	// StatusPending means that the export is being generated in background.
+	StatusPending Status = "pending"
+	// StatusReady means that the export archive can be downloaded.
+	StatusReady Status = "ready"
+	// StatusFailed means that the export archive generation failed.
+	StatusFailed Status = "failed"
+)
+
+// Job is a background export job.
+type Job struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	Status    Status
+	Archive   []byte
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Follow is an exported follow relationship.
+type Follow struct {
+	Username  string
+	CreatedAt time.Time
+}
+
+// Data is all data exported for a single user.
+type Data struct {
+	User      user.User
+	Following []Follow
+	Followers []Follow
+}
+
+// Export is a result of an export request. Archive is set only for ready exports.
+type Export struct {
+	Status      Status
+	Filename    string
+	Archive     []byte
+	RequestedAt time.Time
+}
@@ -0,0 +1,235 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maypok86/conduit/internal/domain/user"
+	"github.com/maypok86/conduit/pkg/logger"
+	"github.com/maypok86/conduit/pkg/tracing"
+	"go.opentelemetry.io/otel"
+	"go.uber.org/zap"
+)
+
+//go:generate mockgen -source=service.go -destination=mock_test.go -package=export_test
+
+// tracer creates the spans of the service calls.
+var tracer = otel.Tracer("github.com/maypok86/conduit/internal/domain/export")
+
+const (
+	defaultSyncLimit      = 1000
+	defaultTTL            = 24 * time.Hour
+	defaultPendingTimeout = time.Hour
+)
+
+// Repository is an export repository.
+type Repository interface {
+	CountFollows(ctx context.Context, userID uuid.UUID) (int, error)
+	GetFollowing(ctx context.Context, userID uuid.UUID) ([]Follow, error)
+	GetFollowers(ctx context.Context, userID uuid.UUID) ([]Follow, error)
+	CreateJob(ctx context.Context, userID uuid.UUID) (Job, error)
+	GetLastJob(ctx context.Context, userID uuid.UUID) (Job, error)
+	CompleteJob(ctx context.Context, id uuid.UUID, archive []byte) error
+	FailJob(ctx context.Context, id uuid.UUID, reason string) error
+}
+
+// UserService is a user service.
+type UserService interface {
+	GetByEmail(ctx context.Context, email string) (user.User, error)
+}
+
+// Dispatcher runs tasks in background.
+type Dispatcher interface {
+	Dispatch(task func(ctx context.Context)) error
+}
+
+// Option is a functional option for configuring a Service.
+type Option func(*Service)
+
+// WithSyncLimit sets the number of exported items up to which the archive is generated synchronously.
+func WithSyncLimit(syncLimit int) Option {
+	return func(s *Service) {
+		s.syncLimit = syncLimit
+	}
+}
+
+// WithTTL sets the period during which a generated archive is served without regeneration.
+func WithTTL(ttl time.Duration) Option {
+	return func(s *Service) {
+		s.ttl = ttl
+	}
+}
+
+// WithPendingTimeout sets the period after which a pending job is considered abandoned, for example because
+// the process running it has stopped, and a new job is created instead.
+func WithPendingTimeout(pendingTimeout time.Duration) Option {
+	return func(s *Service) {
+		s.pendingTimeout = pendingTimeout
+	}
+}
+
+// Service is an export service.
+type Service struct {
+	exportRepository Repository
+	userService      UserService
+	dispatcher       Dispatcher
+	syncLimit        int
+	ttl              time.Duration
+	pendingTimeout   time.Duration
+}
+
+// NewService creates a new export service.
+func NewService(exportRepository Repository, userService UserService, dispatcher Dispatcher, opts ...Option) Service {
+	service := Service{
+		exportRepository: exportRepository,
+		userService:      userService,
+		dispatcher:       dispatcher,
+		syncLimit:        defaultSyncLimit,
+		ttl:              defaultTTL,
+		pendingTimeout:   defaultPendingTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(&service)
+	}
+
+	return service
+}
+
+// Export returns an archive with the user data. Archives of large accounts are generated in background,
+// in this case a pending export is returned and the archive is available on subsequent calls.
+func (s Service) Export(ctx context.Context, email string) (Export, error) {
+	ctx, span := tracing.Start(ctx, tracer, "export.Service.Export")
+	defer span.End()
+
+	u, err := s.userService.GetByEmail(ctx, email)
+	if err != nil {
+		return Export{}, err
+	}
+
+	count, err := s.exportRepository.CountFollows(ctx, u.ID)
+	if err != nil {
+		return Export{}, fmt.Errorf("failed to count exported items: %w", err)
+	}
+
+	if count <= s.syncLimit {
+		data, err := s.collect(ctx, u)
+		if err != nil {
+			return Export{}, err
+		}
+
+		archive, err := data.Archive()
+		if err != nil {
+			return Export{}, fmt.Errorf("failed to build export archive: %w", err)
+		}
+
+		return Export{
+			Status:      StatusReady,
+			Filename:    data.Filename(),
+			Archive:     archive,
+			RequestedAt: time.Now(),
+		}, nil
+	}
+
+	job, err := s.exportRepository.GetLastJob(ctx, u.ID)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return Export{}, fmt.Errorf("failed to get last export job: %w", err)
+	}
+
+	if err == nil && time.Since(job.CreatedAt) < s.ttl {
+		switch job.Status {
+		case StatusPending:
+			if time.Since(job.CreatedAt) < s.pendingTimeout {
+				return Export{Status: StatusPending, RequestedAt: job.CreatedAt}, nil
+			}
+			// abandoned jobs are retried with a new job.
+		case StatusReady:
+			return Export{
+				Status:      StatusReady,
+				Filename:    Data{User: u}.Filename(),
+				Archive:     job.Archive,
+				RequestedAt: job.CreatedAt,
+			}, nil
+		case StatusFailed:
+			// failed exports are retried with a new job.
+		}
+	}
+
+	return s.schedule(ctx, u)
+}
+
+func (s Service) schedule(ctx context.Context, u user.User) (Export, error) {
+	job, err := s.exportRepository.CreateJob(ctx, u.ID)
+	if err != nil {
+		return Export{}, fmt.Errorf("failed to create export job: %w", err)
+	}
+
+	l := logger.FromContext(ctx)
+
+	if err := s.dispatcher.Dispatch(func(ctx context.Context) {
+		s.process(logger.ContextWithLogger(ctx, l), job, u)
+	}); err != nil {
+		if err := s.exportRepository.FailJob(ctx, job.ID, err.Error()); err != nil {
+			l.Error("failed to mark export job as failed", zap.Error(err))
+		}
+
+		return Export{}, fmt.Errorf("failed to dispatch export job: %w", err)
+	}
+
+	return Export{Status: StatusPending, RequestedAt: job.CreatedAt}, nil
+}
+
+func (s Service) process(ctx context.Context, job Job, u user.User) {
+	l := logger.FromContext(ctx).With(zap.String("export_job_id", job.ID.String()))
+
+	if err := s.complete(ctx, job, u); err != nil {
+		l.Error("failed to export user data", zap.Error(err))
+
+		if err := s.exportRepository.FailJob(ctx, job.ID, err.Error()); err != nil {
+			l.Error("failed to mark export job as failed", zap.Error(err))
+		}
+
+		return
+	}
+
+	l.Info("user data is exported")
+}
+
+func (s Service) complete(ctx context.Context, job Job, u user.User) error {
+	data, err := s.collect(ctx, u)
+	if err != nil {
+		return err
+	}
+
+	archive, err := data.Archive()
+	if err != nil {
+		return fmt.Errorf("failed to build export archive: %w", err)
+	}
+
+	if err := s.exportRepository.CompleteJob(ctx, job.ID, archive); err != nil {
+		return fmt.Errorf("failed to complete export job: %w", err)
+	}
+
+	return nil
+}
+
+func (s Service) collect(ctx context.Context, u user.User) (Data, error) {
+	following, err := s.exportRepository.GetFollowing(ctx, u.ID)
+	if err != nil {
+		return Data{}, fmt.Errorf("failed to get following: %w", err)
+	}
+
+	followers, err := s.exportRepository.GetFollowers(ctx, u.ID)
+	if err != nil {
+		return Data{}, fmt.Errorf("failed to get followers: %w", err)
+	}
+
+	return Data{
+		User:      u,
+		Following: following,
+		Followers: followers,
+	}, nil
+}
@@ -0,0 +1,260 @@
+package export_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/bxcodec/faker/v3"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/maypok86/conduit/internal/domain/export"
+	"github.com/maypok86/conduit/internal/domain/user"
+	"github.com/maypok86/conduit/pkg/logger"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+var (
+	errUserService = errors.New("user service error")
+	errRepository  = errors.New("repository error")
+	errDispatcher  = errors.New("dispatcher error")
+)
+
+const syncLimit = 10
+
+func mockService(t *testing.T) (export.Service, *MockRepository, *MockUserService, *MockDispatcher) {
+	t.Helper()
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	repository := NewMockRepository(mockCtrl)
+	userService := NewMockUserService(mockCtrl)
+	dispatcher := NewMockDispatcher(mockCtrl)
+	service := export.NewService(repository, userService, dispatcher, export.WithSyncLimit(syncLimit))
+
+	return service, repository, userService, dispatcher
+}
+
+func readArchive(t *testing.T, archive []byte) map[string]string {
+	t.Helper()
+
+	reader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	require.NoError(t, err)
+
+	files := make(map[string]string, len(reader.File))
+
+	for _, file := range reader.File {
+		f, err := file.Open()
+		require.NoError(t, err)
+
+		content, err := io.ReadAll(f)
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+
+		files[file.Name] = string(content)
+	}
+
+	return files
+}
+
+func TestService_Export(t *testing.T) {
+	t.Parallel()
+
+	ctx := logger.ContextWithLogger(context.Background(), zap.L())
+	now := time.Now()
+	validUser := user.User{
+		ID:        uuid.New(),
+		Username:  faker.Username(),
+		Email:     faker.Email(),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	following := []export.Follow{{Username: faker.Username(), CreatedAt: now}}
+	followers := []export.Follow{{Username: faker.Username(), CreatedAt: now}}
+	readyJob := export.Job{
+		ID:        uuid.New(),
+		UserID:    validUser.ID,
+		Status:    export.StatusReady,
+		Archive:   []byte("archive"),
+		CreatedAt: now,
+	}
+	pendingJob := readyJob
+	pendingJob.Status = export.StatusPending
+	pendingJob.Archive = nil
+	expiredJob := readyJob
+	expiredJob.CreatedAt = now.Add(-48 * time.Hour)
+	abandonedJob := pendingJob
+	abandonedJob.CreatedAt = now.Add(-2 * time.Hour)
+
+	tests := []struct {
+		name    string
+		mock    func(*MockRepository, *MockUserService, *MockDispatcher)
+		want    export.Status
+		check   func(*testing.T, export.Export)
+		wantErr bool
+	}{
+		{
+			name: "small account is exported synchronously",
+			mock: func(repository *MockRepository, userService *MockUserService, dispatcher *MockDispatcher) {
+				userService.EXPECT().GetByEmail(ctx, validUser.Email).Return(validUser, nil)
+				repository.EXPECT().CountFollows(ctx, validUser.ID).Return(syncLimit, nil)
+				repository.EXPECT().GetFollowing(ctx, validUser.ID).Return(following, nil)
+				repository.EXPECT().GetFollowers(ctx, validUser.ID).Return(followers, nil)
+			},
+			want: export.StatusReady,
+			check: func(t *testing.T, got export.Export) {
+				t.Helper()
+
+				require.Equal(t, fmt.Sprintf("conduit-export-%s.zip", validUser.Username), got.Filename)
+
+				files := readArchive(t, got.Archive)
+				require.Contains(t, files["data.json"], validUser.Email)
+				require.Contains(t, files["data.json"], following[0].Username)
+				require.Contains(t, files["README.md"], followers[0].Username)
+			},
+		},
+		{
+			name: "ready job archive is returned",
+			mock: func(repository *MockRepository, userService *MockUserService, dispatcher *MockDispatcher) {
+				userService.EXPECT().GetByEmail(ctx, validUser.Email).Return(validUser, nil)
+				repository.EXPECT().CountFollows(ctx, validUser.ID).Return(syncLimit+1, nil)
+				repository.EXPECT().GetLastJob(ctx, validUser.ID).Return(readyJob, nil)
+			},
+			want: export.StatusReady,
+			check: func(t *testing.T, got export.Export) {
+				t.Helper()
+
+				require.Equal(t, readyJob.Archive, got.Archive)
+			},
+		},
+		{
+			name: "pending job is reported",
+			mock: func(repository *MockRepository, userService *MockUserService, dispatcher *MockDispatcher) {
+				userService.EXPECT().GetByEmail(ctx, validUser.Email).Return(validUser, nil)
+				repository.EXPECT().CountFollows(ctx, validUser.ID).Return(syncLimit+1, nil)
+				repository.EXPECT().GetLastJob(ctx, validUser.ID).Return(pendingJob, nil)
+			},
+			want: export.StatusPending,
+		},
+		{
+			name: "large account is exported in background",
+			mock: func(repository *MockRepository, userService *MockUserService, dispatcher *MockDispatcher) {
+				userService.EXPECT().GetByEmail(ctx, validUser.Email).Return(validUser, nil)
+				repository.EXPECT().CountFollows(ctx, validUser.ID).Return(syncLimit+1, nil)
+				repository.EXPECT().GetLastJob(ctx, validUser.ID).Return(export.Job{}, export.ErrNotFound)
+				repository.EXPECT().CreateJob(ctx, validUser.ID).Return(pendingJob, nil)
+				dispatcher.EXPECT().Dispatch(gomock.Any()).DoAndReturn(func(task func(ctx context.Context)) error {
+					task(context.Background())
+
+					return nil
+				})
+				repository.EXPECT().GetFollowing(gomock.Any(), validUser.ID).Return(following, nil)
+				repository.EXPECT().GetFollowers(gomock.Any(), validUser.ID).Return(followers, nil)
+				repository.EXPECT().CompleteJob(gomock.Any(), pendingJob.ID, gomock.Any()).Return(nil)
+			},
+			want: export.StatusPending,
+		},
+		{
+			name: "expired job is regenerated",
+			mock: func(repository *MockRepository, userService *MockUserService, dispatcher *MockDispatcher) {
+				userService.EXPECT().GetByEmail(ctx, validUser.Email).Return(validUser, nil)
+				repository.EXPECT().CountFollows(ctx, validUser.ID).Return(syncLimit+1, nil)
+				repository.EXPECT().GetLastJob(ctx, validUser.ID).Return(expiredJob, nil)
+				repository.EXPECT().CreateJob(ctx, validUser.ID).Return(pendingJob, nil)
+				dispatcher.EXPECT().Dispatch(gomock.Any()).Return(nil)
+			},
+			want: export.StatusPending,
+		},
+		{
+			name: "abandoned pending job is regenerated",
+			mock: func(repository *MockRepository, userService *MockUserService, dispatcher *MockDispatcher) {
+				userService.EXPECT().GetByEmail(ctx, validUser.Email).Return(validUser, nil)
+				repository.EXPECT().CountFollows(ctx, validUser.ID).Return(syncLimit+1, nil)
+				repository.EXPECT().GetLastJob(ctx, validUser.ID).Return(abandonedJob, nil)
+				repository.EXPECT().CreateJob(ctx, validUser.ID).Return(pendingJob, nil)
+				dispatcher.EXPECT().Dispatch(gomock.Any()).Return(nil)
+			},
+			want: export.StatusPending,
+		},
+		{
+			name: "background export error",
+			mock: func(repository *MockRepository, userService *MockUserService, dispatcher *MockDispatcher) {
+				userService.EXPECT().GetByEmail(ctx, validUser.Email).Return(validUser, nil)
+				repository.EXPECT().CountFollows(ctx, validUser.ID).Return(syncLimit+1, nil)
+				repository.EXPECT().GetLastJob(ctx, validUser.ID).Return(export.Job{}, export.ErrNotFound)
+				repository.EXPECT().CreateJob(ctx, validUser.ID).Return(pendingJob, nil)
+				dispatcher.EXPECT().Dispatch(gomock.Any()).DoAndReturn(func(task func(ctx context.Context)) error {
+					task(context.Background())
+
+					return nil
+				})
+				repository.EXPECT().GetFollowing(gomock.Any(), validUser.ID).Return(nil, errRepository)
+				repository.EXPECT().FailJob(gomock.Any(), pendingJob.ID, gomock.Any()).Return(nil)
+			},
+			want: export.StatusPending,
+		},
+		{
+			name: "dispatcher error",
+			mock: func(repository *MockRepository, userService *MockUserService, dispatcher *MockDispatcher) {
+				userService.EXPECT().GetByEmail(ctx, validUser.Email).Return(validUser, nil)
+				repository.EXPECT().CountFollows(ctx, validUser.ID).Return(syncLimit+1, nil)
+				repository.EXPECT().GetLastJob(ctx, validUser.ID).Return(export.Job{}, export.ErrNotFound)
+				repository.EXPECT().CreateJob(ctx, validUser.ID).Return(pendingJob, nil)
+				dispatcher.EXPECT().Dispatch(gomock.Any()).Return(errDispatcher)
+				repository.EXPECT().FailJob(ctx, pendingJob.ID, errDispatcher.Error()).Return(nil)
+			},
+			wantErr: true,
+		},
+		{
+			name: "last job error",
+			mock: func(repository *MockRepository, userService *MockUserService, dispatcher *MockDispatcher) {
+				userService.EXPECT().GetByEmail(ctx, validUser.Email).Return(validUser, nil)
+				repository.EXPECT().CountFollows(ctx, validUser.ID).Return(syncLimit+1, nil)
+				repository.EXPECT().GetLastJob(ctx, validUser.ID).Return(export.Job{}, errRepository)
+			},
+			wantErr: true,
+		},
+		{
+			name: "count error",
+			mock: func(repository *MockRepository, userService *MockUserService, dispatcher *MockDispatcher) {
+				userService.EXPECT().GetByEmail(ctx, validUser.Email).Return(validUser, nil)
+				repository.EXPECT().CountFollows(ctx, validUser.ID).Return(0, errRepository)
+			},
+			wantErr: true,
+		},
+		{
+			name: "user service error",
+			mock: func(repository *MockRepository, userService *MockUserService, dispatcher *MockDispatcher) {
+				userService.EXPECT().GetByEmail(ctx, validUser.Email).Return(user.User{}, errUserService)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			service, repository, userService, dispatcher := mockService(t)
+
+			tt.mock(repository, userService, dispatcher)
+
+			got, err := service.Export(ctx, validUser.Email)
+			require.True(t, (err != nil) == tt.wantErr)
+			require.Equal(t, tt.want, got.Status)
+
+			if tt.check != nil {
+				tt.check(t, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package profile
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maypok86/conduit/pkg/slugerr"
+)
+
+// ErrInvalidCursor is an error that indicates that pagination cursor is malformed.
+var ErrInvalidCursor = slugerr.NewIncorrectInputError("invalid cursor", "invalid-cursor")
+
+// Cursor points to the last follow of the previous page. Follows are listed from the newest to the oldest,
+// the profile id breaks ties between follows made at the same time.
+type Cursor struct {
+	FollowedAt time.Time
+	ID         uuid.UUID
+}
+
+// String returns an opaque representation of the cursor.
+func (c Cursor) String() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%s", c.FollowedAt.UnixNano(), c.ID)))
+}
+
+// ParseCursor parses a cursor returned by Cursor.String.
+func ParseCursor(s string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	followedAt, id, found := strings.Cut(string(raw), ":")
+	if !found {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	nanos, err := strconv.ParseInt(followedAt, 10, 64)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	profileID, err := uuid.Parse(id)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	return Cursor{
+		FollowedAt: time.Unix(0, nanos).UTC(),
+		ID:         profileID,
+	}, nil
+}
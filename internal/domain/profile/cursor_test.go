@@ -0,0 +1,65 @@
+package profile_test
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maypok86/conduit/internal/domain/profile"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCursor(t *testing.T) {
+	t.Parallel()
+
+	cursor := profile.Cursor{
+		FollowedAt: time.Date(2022, time.August, 14, 13, 56, 16, 123456000, time.UTC),
+		ID:         uuid.New(),
+	}
+
+	tests := []struct {
+		name    string
+		cursor  string
+		want    profile.Cursor
+		wantErr bool
+	}{
+		{
+			name:   "valid cursor",
+			cursor: cursor.String(),
+			want:   cursor,
+		},
+		{
+			name:    "not base64",
+			cursor:  "!!!",
+			wantErr: true,
+		},
+		{
+			name:    "without separator",
+			cursor:  base64.RawURLEncoding.EncodeToString([]byte("cursor")),
+			wantErr: true,
+		},
+		{
+			name:    "invalid time",
+			cursor:  base64.RawURLEncoding.EncodeToString([]byte("now:" + cursor.ID.String())),
+			wantErr: true,
+		},
+		{
+			name:    "invalid id",
+			cursor:  base64.RawURLEncoding.EncodeToString([]byte("1660485376:id")),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := profile.ParseCursor(tt.cursor)
+			require.True(t, (err != nil) == tt.wantErr)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package profile
+
+import "github.com/google/uuid"
+
+// ListDTO is a followers or following list request dto.
+type ListDTO struct {
+	Username string
+	// ViewerEmail is the email of the user who requests the list, empty for anonymous requests.
+	ViewerEmail string
+	Cursor      string
+	Limit       int
+}
+
+// ListRequestsDTO is an incoming follow requests list request dto.
+type ListRequestsDTO struct {
+	// Email is the email of the user whose requests are listed.
+	Email  string
+	Cursor string
+	Limit  int
+}
+
+// ListFollowsDTO is a followers, following or follow requests list query dto.
+type ListFollowsDTO struct {
+	ProfileID uuid.UUID
+	// ViewerID is used to compute the following flag of listed profiles, uuid.Nil for anonymous requests.
+	ViewerID uuid.UUID
+	After    *Cursor
+	Limit    int
+}
@@ -6,10 +6,35 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/maypok86/conduit/pkg/slugerr"
 )
 
-// ErrNotFound is an error that indicates that profile not found.
-var ErrNotFound = errors.New("profile not found")
+var (
+	// ErrNotFound is an error that indicates that profile not found.
+	ErrNotFound = errors.New("profile not found")
+	// ErrCannotFollowSelf is an error that indicates that user tries to follow their own profile.
+	ErrCannotFollowSelf = slugerr.NewIncorrectInputError("you can not follow yourself", "cannot-follow-self")
+	// ErrNotFollowing is an error that indicates that user tries to unfollow a profile they do not follow.
+	ErrNotFollowing = slugerr.NewIncorrectInputError("you are not following the user", "not-following")
+	// ErrFollowBlocked is an error that indicates that one of the users blocks the other one.
+	ErrFollowBlocked = slugerr.NewIncorrectInputError("you can not follow the user", "follow-blocked")
+	// ErrCannotBlockSelf is an error that indicates that user tries to block their own profile.
+	ErrCannotBlockSelf = slugerr.NewIncorrectInputError("you can not block yourself", "cannot-block-self")
+	// ErrNotBlocking is an error that indicates that user tries to unblock a profile they do not block.
+	ErrNotBlocking = slugerr.NewIncorrectInputError("you are not blocking the user", "not-blocking")
+	// ErrCannotMuteSelf is an error that indicates that user tries to mute their own profile.
+	ErrCannotMuteSelf = slugerr.NewIncorrectInputError("you can not mute yourself", "cannot-mute-self")
+	// ErrNotMuting is an error that indicates that user tries to unmute a profile they do not mute.
+	ErrNotMuting = slugerr.NewIncorrectInputError("you are not muting the user", "not-muting")
+	// ErrFollowRequestNotFound is an error that indicates that there is no pending follow request from the user.
+	ErrFollowRequestNotFound = slugerr.NewIncorrectInputError(
+		"there is no follow request from the user",
+		"follow-request-not-found",
+	)
+	// ErrPrivateProfile is an error that indicates that only the owner and the approved followers
+	// can see the follows of the profile.
+	ErrPrivateProfile = slugerr.NewIncorrectInputError("the profile is private", "private-profile")
+)
 
 // Profile is a profile entity.
 type Profile struct {
@@ -17,9 +42,45 @@ type Profile struct {
 	Username  string
 	Bio       *string
 	Image     *string
+	Private   bool
 	Following bool
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	// Requested reports that the viewer has a pending follow request to the profile.
+	Requested bool
+	Blocking  bool
+	Muting    bool
+	// BlockedBy reports that the profile blocks the viewer, such profiles are hidden from the viewer.
+	BlockedBy      bool
+	FollowersCount int
+	FollowingCount int
+	// RenamedFrom is the previous username the profile was found by, empty if it was found by the current one.
+	RenamedFrom string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// Restrictions are safety controls and pending approvals between the viewer and a profile.
+type Restrictions struct {
+	// Blocking reports that the viewer blocks the profile.
+	Blocking bool
+	// BlockedBy reports that the profile blocks the viewer.
+	BlockedBy bool
+	// Muting reports that the viewer mutes the profile.
+	Muting bool
+	// Requested reports that the viewer has a pending follow request to the profile.
+	Requested bool
+}
+
+// Follow is a profile in a followers, following or follow requests list.
+type Follow struct {
+	Profile    Profile
+	FollowedAt time.Time
+}
+
+// FollowsPage is a page of a followers, following or follow requests list.
+type FollowsPage struct {
+	Profiles []Profile
+	// NextCursor is the cursor of the next page, empty if the page is the last one.
+	NextCursor string
 }
 
 // GetBio returns bio.
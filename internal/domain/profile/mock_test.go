@@ -36,6 +36,34 @@ func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
 	return m.recorder
 }
 
+// ApproveFollowRequest mocks base method.
+func (m *MockRepository) ApproveFollowRequest(ctx context.Context, followeeID, followerID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApproveFollowRequest", ctx, followeeID, followerID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ApproveFollowRequest indicates an expected call of ApproveFollowRequest.
+func (mr *MockRepositoryMockRecorder) ApproveFollowRequest(ctx, followeeID, followerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApproveFollowRequest", reflect.TypeOf((*MockRepository)(nil).ApproveFollowRequest), ctx, followeeID, followerID)
+}
+
+// Block mocks base method.
+func (m *MockRepository) Block(ctx context.Context, blockerID, blockedID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Block", ctx, blockerID, blockedID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Block indicates an expected call of Block.
+func (mr *MockRepositoryMockRecorder) Block(ctx, blockerID, blockedID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Block", reflect.TypeOf((*MockRepository)(nil).Block), ctx, blockerID, blockedID)
+}
+
 // CheckFollowing mocks base method.
 func (m *MockRepository) CheckFollowing(ctx context.Context, followeeID, followerID uuid.UUID) error {
 	m.ctrl.T.Helper()
@@ -50,6 +78,20 @@ func (mr *MockRepositoryMockRecorder) CheckFollowing(ctx, followeeID, followerID
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckFollowing", reflect.TypeOf((*MockRepository)(nil).CheckFollowing), ctx, followeeID, followerID)
 }
 
+// DeleteFollowRequest mocks base method.
+func (m *MockRepository) DeleteFollowRequest(ctx context.Context, followeeID, followerID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFollowRequest", ctx, followeeID, followerID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFollowRequest indicates an expected call of DeleteFollowRequest.
+func (mr *MockRepositoryMockRecorder) DeleteFollowRequest(ctx, followeeID, followerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFollowRequest", reflect.TypeOf((*MockRepository)(nil).DeleteFollowRequest), ctx, followeeID, followerID)
+}
+
 // Follow mocks base method.
 func (m *MockRepository) Follow(ctx context.Context, followeeID, followerID uuid.UUID) error {
 	m.ctrl.T.Helper()
@@ -79,6 +121,21 @@ func (mr *MockRepositoryMockRecorder) GetByEmail(ctx, email interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByEmail", reflect.TypeOf((*MockRepository)(nil).GetByEmail), ctx, email)
 }
 
+// GetByPreviousUsername mocks base method.
+func (m *MockRepository) GetByPreviousUsername(ctx context.Context, username string) (profile.Profile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByPreviousUsername", ctx, username)
+	ret0, _ := ret[0].(profile.Profile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByPreviousUsername indicates an expected call of GetByPreviousUsername.
+func (mr *MockRepositoryMockRecorder) GetByPreviousUsername(ctx, username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByPreviousUsername", reflect.TypeOf((*MockRepository)(nil).GetByPreviousUsername), ctx, username)
+}
+
 // GetByUsername mocks base method.
 func (m *MockRepository) GetByUsername(ctx context.Context, username string) (profile.Profile, error) {
 	m.ctrl.T.Helper()
@@ -94,6 +151,108 @@ func (mr *MockRepositoryMockRecorder) GetByUsername(ctx, username interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUsername", reflect.TypeOf((*MockRepository)(nil).GetByUsername), ctx, username)
 }
 
+// GetRestrictions mocks base method.
+func (m *MockRepository) GetRestrictions(ctx context.Context, profileID, viewerID uuid.UUID) (profile.Restrictions, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRestrictions", ctx, profileID, viewerID)
+	ret0, _ := ret[0].(profile.Restrictions)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRestrictions indicates an expected call of GetRestrictions.
+func (mr *MockRepositoryMockRecorder) GetRestrictions(ctx, profileID, viewerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRestrictions", reflect.TypeOf((*MockRepository)(nil).GetRestrictions), ctx, profileID, viewerID)
+}
+
+// ListFollowRequests mocks base method.
+func (m *MockRepository) ListFollowRequests(ctx context.Context, dto profile.ListFollowsDTO) ([]profile.Follow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFollowRequests", ctx, dto)
+	ret0, _ := ret[0].([]profile.Follow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFollowRequests indicates an expected call of ListFollowRequests.
+func (mr *MockRepositoryMockRecorder) ListFollowRequests(ctx, dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFollowRequests", reflect.TypeOf((*MockRepository)(nil).ListFollowRequests), ctx, dto)
+}
+
+// ListFollowers mocks base method.
+func (m *MockRepository) ListFollowers(ctx context.Context, dto profile.ListFollowsDTO) ([]profile.Follow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFollowers", ctx, dto)
+	ret0, _ := ret[0].([]profile.Follow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFollowers indicates an expected call of ListFollowers.
+func (mr *MockRepositoryMockRecorder) ListFollowers(ctx, dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFollowers", reflect.TypeOf((*MockRepository)(nil).ListFollowers), ctx, dto)
+}
+
+// ListFollowing mocks base method.
+func (m *MockRepository) ListFollowing(ctx context.Context, dto profile.ListFollowsDTO) ([]profile.Follow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFollowing", ctx, dto)
+	ret0, _ := ret[0].([]profile.Follow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFollowing indicates an expected call of ListFollowing.
+func (mr *MockRepositoryMockRecorder) ListFollowing(ctx, dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFollowing", reflect.TypeOf((*MockRepository)(nil).ListFollowing), ctx, dto)
+}
+
+// Mute mocks base method.
+func (m *MockRepository) Mute(ctx context.Context, muterID, mutedID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Mute", ctx, muterID, mutedID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Mute indicates an expected call of Mute.
+func (mr *MockRepositoryMockRecorder) Mute(ctx, muterID, mutedID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Mute", reflect.TypeOf((*MockRepository)(nil).Mute), ctx, muterID, mutedID)
+}
+
+// RequestFollow mocks base method.
+func (m *MockRepository) RequestFollow(ctx context.Context, followeeID, followerID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestFollow", ctx, followeeID, followerID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequestFollow indicates an expected call of RequestFollow.
+func (mr *MockRepositoryMockRecorder) RequestFollow(ctx, followeeID, followerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestFollow", reflect.TypeOf((*MockRepository)(nil).RequestFollow), ctx, followeeID, followerID)
+}
+
+// Unblock mocks base method.
+func (m *MockRepository) Unblock(ctx context.Context, blockerID, blockedID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unblock", ctx, blockerID, blockedID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unblock indicates an expected call of Unblock.
+func (mr *MockRepositoryMockRecorder) Unblock(ctx, blockerID, blockedID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unblock", reflect.TypeOf((*MockRepository)(nil).Unblock), ctx, blockerID, blockedID)
+}
+
 // Unfollow mocks base method.
 func (m *MockRepository) Unfollow(ctx context.Context, followeeID, followerID uuid.UUID) error {
 	m.ctrl.T.Helper()
@@ -107,3 +266,54 @@ func (mr *MockRepositoryMockRecorder) Unfollow(ctx, followeeID, followerID inter
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unfollow", reflect.TypeOf((*MockRepository)(nil).Unfollow), ctx, followeeID, followerID)
 }
+
+// Unmute mocks base method.
+func (m *MockRepository) Unmute(ctx context.Context, muterID, mutedID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unmute", ctx, muterID, mutedID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unmute indicates an expected call of Unmute.
+func (mr *MockRepositoryMockRecorder) Unmute(ctx, muterID, mutedID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unmute", reflect.TypeOf((*MockRepository)(nil).Unmute), ctx, muterID, mutedID)
+}
+
+// MockTransactor is a mock of Transactor interface.
+type MockTransactor struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransactorMockRecorder
+}
+
+// MockTransactorMockRecorder is the mock recorder for MockTransactor.
+type MockTransactorMockRecorder struct {
+	mock *MockTransactor
+}
+
+// NewMockTransactor creates a new mock instance.
+func NewMockTransactor(ctrl *gomock.Controller) *MockTransactor {
+	mock := &MockTransactor{ctrl: ctrl}
+	mock.recorder = &MockTransactorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransactor) EXPECT() *MockTransactorMockRecorder {
+	return m.recorder
+}
+
+// WithinTransaction mocks base method.
+func (m *MockTransactor) WithinTransaction(ctx context.Context, fn func(context.Context) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithinTransaction", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithinTransaction indicates an expected call of WithinTransaction.
+func (mr *MockTransactorMockRecorder) WithinTransaction(ctx, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithinTransaction", reflect.TypeOf((*MockTransactor)(nil).WithinTransaction), ctx, fn)
+}
@@ -6,43 +6,90 @@ import (
 	"fmt"
 
 	"github.com/google/uuid"
+	"github.com/maypok86/conduit/pkg/tracing"
+	"go.opentelemetry.io/otel"
 )
 
 //go:generate mockgen -source=service.go -destination=mock_test.go -package=profile_test
 
+// tracer creates the spans of the service calls.
+var tracer = otel.Tracer("github.com/maypok86/conduit/internal/domain/profile")
+
 // Repository is a profile repository.
 type Repository interface {
 	GetByUsername(ctx context.Context, username string) (Profile, error)
+	GetByPreviousUsername(ctx context.Context, username string) (Profile, error)
 	GetByEmail(ctx context.Context, email string) (Profile, error)
 	CheckFollowing(ctx context.Context, followeeID, followerID uuid.UUID) error
 	Follow(ctx context.Context, followeeID, followerID uuid.UUID) error
 	Unfollow(ctx context.Context, followeeID, followerID uuid.UUID) error
+	ListFollowers(ctx context.Context, dto ListFollowsDTO) ([]Follow, error)
+	ListFollowing(ctx context.Context, dto ListFollowsDTO) ([]Follow, error)
+	GetRestrictions(ctx context.Context, profileID, viewerID uuid.UUID) (Restrictions, error)
+	Block(ctx context.Context, blockerID, blockedID uuid.UUID) error
+	Unblock(ctx context.Context, blockerID, blockedID uuid.UUID) error
+	Mute(ctx context.Context, muterID, mutedID uuid.UUID) error
+	Unmute(ctx context.Context, muterID, mutedID uuid.UUID) error
+	RequestFollow(ctx context.Context, followeeID, followerID uuid.UUID) error
+	ApproveFollowRequest(ctx context.Context, followeeID, followerID uuid.UUID) error
+	DeleteFollowRequest(ctx context.Context, followeeID, followerID uuid.UUID) error
+	ListFollowRequests(ctx context.Context, dto ListFollowsDTO) ([]Follow, error)
+}
+
+// Transactor runs functions in a database transaction.
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
 }
 
+const (
+	defaultListLimit = 20
+	maxListLimit     = 100
+)
+
 // Service a profile service interface.
 type Service struct {
 	profileRepository Repository
+	transactor        Transactor
 }
 
 // NewService creates a new profile service.
-func NewService(profileRepository Repository) Service {
+func NewService(profileRepository Repository, transactor Transactor) Service {
 	return Service{
 		profileRepository: profileRepository,
+		transactor:        transactor,
 	}
 }
 
-// GetByUsername gets a profile by username.
+// GetByUsername gets a profile by username. If nobody uses the username now, the profile of the user
+// who was renamed from it is returned.
 func (s Service) GetByUsername(ctx context.Context, username string) (Profile, error) {
+	ctx, span := tracing.Start(ctx, tracer, "profile.Service.GetByUsername")
+	defer span.End()
+
 	profile, err := s.profileRepository.GetByUsername(ctx, username)
-	if err != nil {
+	if err == nil {
+		return profile, nil
+	}
+
+	if !errors.Is(err, ErrNotFound) {
 		return Profile{}, fmt.Errorf("failed to get profile by username: %w", err)
 	}
 
+	profile, err = s.profileRepository.GetByPreviousUsername(ctx, username)
+	if err != nil {
+		return Profile{}, fmt.Errorf("failed to get profile by previous username: %w", err)
+	}
+
+	profile.RenamedFrom = username
+
 	return profile, nil
 }
 
 // GetByEmail gets a profile by email.
 func (s Service) GetByEmail(ctx context.Context, email string) (Profile, error) {
+	ctx, span := tracing.Start(ctx, tracer, "profile.Service.GetByEmail")
+	defer span.End()
+
 	profile, err := s.profileRepository.GetByEmail(ctx, email)
 	if err != nil {
 		return Profile{}, fmt.Errorf("failed to get profile by email: %w", err)
@@ -51,70 +98,440 @@ func (s Service) GetByEmail(ctx context.Context, email string) (Profile, error)
 	return profile, nil
 }
 
-// GetWithFollow gets a profile with follow checking.
+// GetWithFollow gets a profile with follow checking. Profiles blocking the user are not found.
 func (s Service) GetWithFollow(ctx context.Context, email, username string) (Profile, error) {
-	followee, err := s.GetByUsername(ctx, username)
+	ctx, span := tracing.Start(ctx, tracer, "profile.Service.GetWithFollow")
+	defer span.End()
+
+	followee, follower, err := s.getPair(ctx, email, username)
 	if err != nil {
 		return Profile{}, err
 	}
 
-	follower, err := s.GetByEmail(ctx, email)
+	followee, err = s.withRelationship(ctx, followee, follower.ID)
 	if err != nil {
 		return Profile{}, err
 	}
 
-	if err := s.profileRepository.CheckFollowing(ctx, followee.ID, follower.ID); err != nil {
-		if errors.Is(err, ErrNotFound) {
-			followee.Following = false
-			return followee, nil
+	if followee.BlockedBy {
+		return Profile{}, fmt.Errorf("failed to get profile by username: %w", ErrNotFound)
+	}
+
+	return followee, nil
+}
+
+// Follow make a follow relationship. Following a private profile creates a pending follow request instead.
+// Following the same profile again succeeds.
+func (s Service) Follow(ctx context.Context, email, username string) (Profile, error) {
+	ctx, span := tracing.Start(ctx, tracer, "profile.Service.Follow")
+	defer span.End()
+
+	return s.changeRelationship(ctx, email, username, func(ctx context.Context, followee, follower Profile) error {
+		if followee.ID == follower.ID {
+			return ErrCannotFollowSelf
 		}
 
-		return Profile{}, fmt.Errorf("failed to check following: %w", err)
+		// The repository refuses the follow of a blocked pair too, this check does not see concurrent blocks.
+		restrictions, err := s.profileRepository.GetRestrictions(ctx, followee.ID, follower.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get restrictions: %w", err)
+		}
+
+		if restrictions.Blocking || restrictions.BlockedBy {
+			return ErrFollowBlocked
+		}
+
+		if followee.Private {
+			return s.requestFollow(ctx, followee, follower)
+		}
+
+		if err := s.profileRepository.Follow(ctx, followee.ID, follower.ID); err != nil {
+			return fmt.Errorf("failed to follow: %w", err)
+		}
+
+		return nil
+	})
+}
+
+func (s Service) requestFollow(ctx context.Context, followee, follower Profile) error {
+	err := s.profileRepository.CheckFollowing(ctx, followee.ID, follower.ID)
+	if err == nil {
+		return nil
+	}
+
+	if !errors.Is(err, ErrNotFound) {
+		return fmt.Errorf("failed to check following: %w", err)
 	}
 
-	followee.Following = true
+	if err := s.profileRepository.RequestFollow(ctx, followee.ID, follower.ID); err != nil {
+		return fmt.Errorf("failed to request follow: %w", err)
+	}
 
-	return followee, nil
+	return nil
 }
 
-// Follow make a follow relationship.
-func (s Service) Follow(ctx context.Context, email, username string) (Profile, error) {
-	followee, err := s.GetByUsername(ctx, username)
+// Unfollow delete a follow relationship, a pending follow request is cancelled instead if there is one.
+// It returns ErrNotFollowing if the user neither follows the profile nor requested to.
+func (s Service) Unfollow(ctx context.Context, email, username string) (Profile, error) {
+	ctx, span := tracing.Start(ctx, tracer, "profile.Service.Unfollow")
+	defer span.End()
+
+	return s.changeRelationship(ctx, email, username, func(ctx context.Context, followee, follower Profile) error {
+		err := s.profileRepository.Unfollow(ctx, followee.ID, follower.ID)
+		if err == nil {
+			return nil
+		}
+
+		if !errors.Is(err, ErrNotFollowing) {
+			return fmt.Errorf("failed to unfollow: %w", err)
+		}
+
+		if err := s.profileRepository.DeleteFollowRequest(ctx, followee.ID, follower.ID); err != nil {
+			if errors.Is(err, ErrFollowRequestNotFound) {
+				return ErrNotFollowing
+			}
+
+			return fmt.Errorf("failed to cancel follow request: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// ApproveFollowRequest makes the user with username a follower of the user with email.
+// It returns ErrFollowRequestNotFound if there is no pending follow request.
+func (s Service) ApproveFollowRequest(ctx context.Context, email, username string) (Profile, error) {
+	ctx, span := tracing.Start(ctx, tracer, "profile.Service.ApproveFollowRequest")
+	defer span.End()
+
+	return s.changeRelationship(ctx, email, username, func(ctx context.Context, follower, followee Profile) error {
+		if err := s.profileRepository.ApproveFollowRequest(ctx, followee.ID, follower.ID); err != nil {
+			return fmt.Errorf("failed to approve follow request: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// RejectFollowRequest removes the follow request of the user with username to the user with email.
+// It returns ErrFollowRequestNotFound if there is no pending follow request.
+func (s Service) RejectFollowRequest(ctx context.Context, email, username string) (Profile, error) {
+	ctx, span := tracing.Start(ctx, tracer, "profile.Service.RejectFollowRequest")
+	defer span.End()
+
+	return s.changeRelationship(ctx, email, username, func(ctx context.Context, follower, followee Profile) error {
+		if err := s.profileRepository.DeleteFollowRequest(ctx, followee.ID, follower.ID); err != nil {
+			return fmt.Errorf("failed to reject follow request: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// Block blocks the profile and removes follows in both directions. Blocking the same profile again succeeds.
+func (s Service) Block(ctx context.Context, email, username string) (Profile, error) {
+	ctx, span := tracing.Start(ctx, tracer, "profile.Service.Block")
+	defer span.End()
+
+	return s.changeRelationship(ctx, email, username, func(ctx context.Context, blocked, blocker Profile) error {
+		if blocked.ID == blocker.ID {
+			return ErrCannotBlockSelf
+		}
+
+		if err := s.profileRepository.Block(ctx, blocker.ID, blocked.ID); err != nil {
+			return fmt.Errorf("failed to block: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// Unblock removes the block. It returns ErrNotBlocking if the user does not block the profile.
+func (s Service) Unblock(ctx context.Context, email, username string) (Profile, error) {
+	ctx, span := tracing.Start(ctx, tracer, "profile.Service.Unblock")
+	defer span.End()
+
+	return s.changeRelationship(ctx, email, username, func(ctx context.Context, blocked, blocker Profile) error {
+		if err := s.profileRepository.Unblock(ctx, blocker.ID, blocked.ID); err != nil {
+			return fmt.Errorf("failed to unblock: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// Mute mutes the profile. Muting the same profile again succeeds.
+func (s Service) Mute(ctx context.Context, email, username string) (Profile, error) {
+	ctx, span := tracing.Start(ctx, tracer, "profile.Service.Mute")
+	defer span.End()
+
+	return s.changeRelationship(ctx, email, username, func(ctx context.Context, muted, muter Profile) error {
+		if muted.ID == muter.ID {
+			return ErrCannotMuteSelf
+		}
+
+		if err := s.profileRepository.Mute(ctx, muter.ID, muted.ID); err != nil {
+			return fmt.Errorf("failed to mute: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// Unmute removes the mute. It returns ErrNotMuting if the user does not mute the profile.
+func (s Service) Unmute(ctx context.Context, email, username string) (Profile, error) {
+	ctx, span := tracing.Start(ctx, tracer, "profile.Service.Unmute")
+	defer span.End()
+
+	return s.changeRelationship(ctx, email, username, func(ctx context.Context, muted, muter Profile) error {
+		if err := s.profileRepository.Unmute(ctx, muter.ID, muted.ID); err != nil {
+			return fmt.Errorf("failed to unmute: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// changeRelationship looks the profile and the user up, changes the relationship between them and
+// reads the resulting relationship in one transaction.
+func (s Service) changeRelationship(
+	ctx context.Context,
+	email string,
+	username string,
+	change func(ctx context.Context, profile, user Profile) error,
+) (Profile, error) {
+	var profile Profile
+
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		target, user, err := s.getPair(ctx, email, username)
+		if err != nil {
+			return err
+		}
+
+		if err := change(ctx, target, user); err != nil {
+			return err
+		}
+
+		profile, err = s.withRelationship(ctx, target, user.ID)
+
+		return err
+	})
 	if err != nil {
 		return Profile{}, err
 	}
 
-	follower, err := s.GetByEmail(ctx, email)
+	return profile, nil
+}
+
+func (s Service) getPair(ctx context.Context, email, username string) (Profile, Profile, error) {
+	profile, err := s.GetByUsername(ctx, username)
 	if err != nil {
-		return Profile{}, err
+		return Profile{}, Profile{}, err
 	}
 
-	if err := s.profileRepository.Follow(ctx, followee.ID, follower.ID); err != nil {
-		return Profile{}, fmt.Errorf("failed to follow: %w", err)
+	user, err := s.GetByEmail(ctx, email)
+	if err != nil {
+		return Profile{}, Profile{}, err
 	}
 
-	followee.Following = true
+	return profile, user, nil
+}
 
-	return followee, nil
+// withRelationship fills the following, requested, blocking and muting flags of the profile as seen by the viewer.
+func (s Service) withRelationship(ctx context.Context, profile Profile, viewerID uuid.UUID) (Profile, error) {
+	profile.Following = true
+
+	if err := s.profileRepository.CheckFollowing(ctx, profile.ID, viewerID); err != nil {
+		if !errors.Is(err, ErrNotFound) {
+			return Profile{}, fmt.Errorf("failed to check following: %w", err)
+		}
+
+		profile.Following = false
+	}
+
+	restrictions, err := s.profileRepository.GetRestrictions(ctx, profile.ID, viewerID)
+	if err != nil {
+		return Profile{}, fmt.Errorf("failed to get restrictions: %w", err)
+	}
+
+	profile.Blocking = restrictions.Blocking
+	profile.BlockedBy = restrictions.BlockedBy
+	profile.Muting = restrictions.Muting
+	profile.Requested = restrictions.Requested
+
+	return profile, nil
 }
 
-// Unfollow delete a follow relationship.
-func (s Service) Unfollow(ctx context.Context, email, username string) (Profile, error) {
-	followee, err := s.GetByUsername(ctx, username)
+// ListFollowers returns a page of users following the profile.
+func (s Service) ListFollowers(ctx context.Context, dto ListDTO) (FollowsPage, error) {
+	ctx, span := tracing.Start(ctx, tracer, "profile.Service.ListFollowers")
+	defer span.End()
+
+	page, err := s.listFollows(ctx, dto, s.profileRepository.ListFollowers)
 	if err != nil {
-		return Profile{}, err
+		return FollowsPage{}, fmt.Errorf("failed to list followers: %w", err)
+	}
+
+	return page, nil
+}
+
+// ListFollowing returns a page of users followed by the profile.
+func (s Service) ListFollowing(ctx context.Context, dto ListDTO) (FollowsPage, error) {
+	ctx, span := tracing.Start(ctx, tracer, "profile.Service.ListFollowing")
+	defer span.End()
+
+	page, err := s.listFollows(ctx, dto, s.profileRepository.ListFollowing)
+	if err != nil {
+		return FollowsPage{}, fmt.Errorf("failed to list following: %w", err)
 	}
 
-	follower, err := s.GetByEmail(ctx, email)
+	return page, nil
+}
+
+// ListFollowRequests returns a page of users requesting to follow the user.
+func (s Service) ListFollowRequests(ctx context.Context, dto ListRequestsDTO) (FollowsPage, error) {
+	ctx, span := tracing.Start(ctx, tracer, "profile.Service.ListFollowRequests")
+	defer span.End()
+
+	listDTO, err := newListFollowsDTO(dto.Cursor, dto.Limit)
 	if err != nil {
-		return Profile{}, err
+		return FollowsPage{}, fmt.Errorf("failed to list follow requests: %w", err)
 	}
 
-	if err := s.profileRepository.Unfollow(ctx, followee.ID, follower.ID); err != nil {
-		return Profile{}, fmt.Errorf("failed to unfollow: %w", err)
+	owner, err := s.GetByEmail(ctx, dto.Email)
+	if err != nil {
+		return FollowsPage{}, fmt.Errorf("failed to list follow requests: %w", err)
 	}
 
-	followee.Following = false
+	listDTO.ProfileID = owner.ID
+	listDTO.ViewerID = owner.ID
 
-	return followee, nil
+	page, err := listPage(ctx, listDTO, s.profileRepository.ListFollowRequests)
+	if err != nil {
+		return FollowsPage{}, fmt.Errorf("failed to list follow requests: %w", err)
+	}
+
+	return page, nil
+}
+
+func (s Service) listFollows(
+	ctx context.Context,
+	dto ListDTO,
+	list func(context.Context, ListFollowsDTO) ([]Follow, error),
+) (FollowsPage, error) {
+	listDTO, err := newListFollowsDTO(dto.Cursor, dto.Limit)
+	if err != nil {
+		return FollowsPage{}, err
+	}
+
+	profile, err := s.GetByUsername(ctx, dto.Username)
+	if err != nil {
+		return FollowsPage{}, err
+	}
+
+	listDTO.ProfileID = profile.ID
+
+	if dto.ViewerEmail != "" {
+		viewer, err := s.GetByEmail(ctx, dto.ViewerEmail)
+		if err != nil {
+			return FollowsPage{}, err
+		}
+
+		listDTO.ViewerID = viewer.ID
+	}
+
+	if err := s.checkFollowsVisible(ctx, profile, listDTO.ViewerID); err != nil {
+		return FollowsPage{}, err
+	}
+
+	return listPage(ctx, listDTO, list)
+}
+
+// checkFollowsVisible returns ErrNotFound if the profile blocks the viewer, as the profile itself is hidden
+// from them, and ErrPrivateProfile if the profile is private and the viewer is neither its owner
+// nor an approved follower.
+func (s Service) checkFollowsVisible(ctx context.Context, profile Profile, viewerID uuid.UUID) error {
+	if viewerID == profile.ID {
+		return nil
+	}
+
+	if viewerID != uuid.Nil {
+		restrictions, err := s.profileRepository.GetRestrictions(ctx, profile.ID, viewerID)
+		if err != nil {
+			return fmt.Errorf("failed to get restrictions: %w", err)
+		}
+
+		if restrictions.BlockedBy {
+			return fmt.Errorf("failed to get profile by username: %w", ErrNotFound)
+		}
+	}
+
+	if !profile.Private {
+		return nil
+	}
+
+	if viewerID == uuid.Nil {
+		return ErrPrivateProfile
+	}
+
+	if err := s.profileRepository.CheckFollowing(ctx, profile.ID, viewerID); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return ErrPrivateProfile
+		}
+
+		return fmt.Errorf("failed to check following: %w", err)
+	}
+
+	return nil
+}
+
+func newListFollowsDTO(cursor string, limit int) (ListFollowsDTO, error) {
+	listDTO := ListFollowsDTO{Limit: limit}
+	if listDTO.Limit <= 0 {
+		listDTO.Limit = defaultListLimit
+	}
+
+	if listDTO.Limit > maxListLimit {
+		listDTO.Limit = maxListLimit
+	}
+
+	if cursor != "" {
+		after, err := ParseCursor(cursor)
+		if err != nil {
+			return ListFollowsDTO{}, err
+		}
+
+		listDTO.After = &after
+	}
+
+	return listDTO, nil
+}
+
+func listPage(
+	ctx context.Context,
+	listDTO ListFollowsDTO,
+	list func(context.Context, ListFollowsDTO) ([]Follow, error),
+) (FollowsPage, error) {
+	limit := listDTO.Limit
+	// One more follow is requested to find out if there is a next page.
+	listDTO.Limit++
+
+	follows, err := list(ctx, listDTO)
+	if err != nil {
+		return FollowsPage{}, err
+	}
+
+	var page FollowsPage
+	if len(follows) > limit {
+		follows = follows[:limit]
+		last := follows[limit-1]
+		page.NextCursor = Cursor{FollowedAt: last.FollowedAt, ID: last.Profile.ID}.String()
+	}
+
+	page.Profiles = make([]Profile, 0, len(follows))
+	for _, follow := range follows {
+		page.Profiles = append(page.Profiles, follow.Profile)
+	}
+
+	return page, nil
 }
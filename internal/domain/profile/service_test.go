@@ -18,12 +18,15 @@ import (
 )
 
 var (
-	errGetByUsernameRepository  = errors.New("get by username repository error")
-	errGetByEmailRepository     = errors.New("get by email repository error")
-	errNotFoundFollowRepository = fmt.Errorf("not found follow repository: %w", profile.ErrNotFound)
-	errCheckFollowingRepository = errors.New("check following repository error")
-	errFollowRepository         = errors.New("follow repository error")
-	errUnfollowRepository       = errors.New("unfollow repository error")
+	errGetByUsernameRepository   = errors.New("get by username repository error")
+	errGetByEmailRepository      = errors.New("get by email repository error")
+	errNotFoundFollowRepository  = fmt.Errorf("not found follow repository: %w", profile.ErrNotFound)
+	errNotFoundProfileRepository = fmt.Errorf("not found profile repository: %w", profile.ErrNotFound)
+	errCheckFollowingRepository  = errors.New("check following repository error")
+	errGetRestrictionsRepository = errors.New("get restrictions repository error")
+	errBlockRepository           = errors.New("block repository error")
+	errFollowRepository          = errors.New("follow repository error")
+	errUnfollowRepository        = errors.New("unfollow repository error")
 )
 
 func mockService(t *testing.T) (profile.Service, *MockRepository) {
@@ -33,7 +36,13 @@ func mockService(t *testing.T) (profile.Service, *MockRepository) {
 	defer mockCtrl.Finish()
 
 	repository := NewMockRepository(mockCtrl)
-	service := profile.NewService(repository)
+	transactor := NewMockTransactor(mockCtrl)
+	transactor.EXPECT().WithinTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		},
+	).AnyTimes()
+	service := profile.NewService(repository, transactor)
 
 	return service, repository
 }
@@ -63,6 +72,9 @@ func TestService_GetByUsername(t *testing.T) {
 
 	ctx := logger.ContextWithLogger(context.Background(), zap.L())
 	validProfile := createProfile(t, false)
+	previousUsername := faker.Username()
+	renamedProfile := validProfile
+	renamedProfile.RenamedFrom = previousUsername
 
 	type args struct {
 		username string
@@ -98,6 +110,35 @@ func TestService_GetByUsername(t *testing.T) {
 			want:    profile.Profile{},
 			wantErr: true,
 		},
+		{
+			name: "renamed profile",
+			mock: func(repository *MockRepository) {
+				repository.EXPECT().
+					GetByUsername(ctx, previousUsername).
+					Return(profile.Profile{}, errNotFoundProfileRepository)
+				repository.EXPECT().GetByPreviousUsername(ctx, previousUsername).Return(validProfile, nil)
+			},
+			args: args{
+				username: previousUsername,
+			},
+			want: renamedProfile,
+		},
+		{
+			name: "unknown username",
+			mock: func(repository *MockRepository) {
+				repository.EXPECT().
+					GetByUsername(ctx, previousUsername).
+					Return(profile.Profile{}, errNotFoundProfileRepository)
+				repository.EXPECT().
+					GetByPreviousUsername(ctx, previousUsername).
+					Return(profile.Profile{}, errNotFoundProfileRepository)
+			},
+			args: args{
+				username: previousUsername,
+			},
+			want:    profile.Profile{},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -204,6 +245,7 @@ func TestService_GetWithFollow(t *testing.T) {
 				repository.EXPECT().GetByUsername(ctx, followee.Username).Return(followee, nil)
 				repository.EXPECT().GetByEmail(ctx, email).Return(follower, nil)
 				repository.EXPECT().CheckFollowing(ctx, followee.ID, follower.ID).Return(nil)
+				repository.EXPECT().GetRestrictions(ctx, followee.ID, follower.ID).Return(profile.Restrictions{}, nil)
 			},
 			args: args{
 				followeeUsername: followee.Username,
@@ -258,6 +300,7 @@ func TestService_GetWithFollow(t *testing.T) {
 				repository.EXPECT().GetByUsername(ctx, followee.Username).Return(followee, nil)
 				repository.EXPECT().GetByEmail(ctx, email).Return(follower, nil)
 				repository.EXPECT().CheckFollowing(ctx, followee.ID, follower.ID).Return(errNotFoundFollowRepository)
+				repository.EXPECT().GetRestrictions(ctx, followee.ID, follower.ID).Return(profile.Restrictions{}, nil)
 			},
 			args: args{
 				followeeUsername: followee.Username,
@@ -265,6 +308,40 @@ func TestService_GetWithFollow(t *testing.T) {
 			},
 			want: followee,
 		},
+		{
+			name: "blocked by profile",
+			mock: func(repository *MockRepository) {
+				repository.EXPECT().GetByUsername(ctx, followee.Username).Return(followee, nil)
+				repository.EXPECT().GetByEmail(ctx, email).Return(follower, nil)
+				repository.EXPECT().CheckFollowing(ctx, followee.ID, follower.ID).Return(errNotFoundFollowRepository)
+				repository.EXPECT().
+					GetRestrictions(ctx, followee.ID, follower.ID).
+					Return(profile.Restrictions{BlockedBy: true}, nil)
+			},
+			args: args{
+				followeeUsername: followee.Username,
+				email:            email,
+			},
+			want:    profile.Profile{},
+			wantErr: true,
+		},
+		{
+			name: "get restrictions error",
+			mock: func(repository *MockRepository) {
+				repository.EXPECT().GetByUsername(ctx, followee.Username).Return(followee, nil)
+				repository.EXPECT().GetByEmail(ctx, email).Return(follower, nil)
+				repository.EXPECT().CheckFollowing(ctx, followee.ID, follower.ID).Return(nil)
+				repository.EXPECT().
+					GetRestrictions(ctx, followee.ID, follower.ID).
+					Return(profile.Restrictions{}, errGetRestrictionsRepository)
+			},
+			args: args{
+				followeeUsername: followee.Username,
+				email:            email,
+			},
+			want:    profile.Profile{},
+			wantErr: true,
+		},
 		{
 			name: "check following error",
 			mock: func(repository *MockRepository) {
@@ -326,7 +403,12 @@ func TestService_Follow(t *testing.T) {
 			mock: func(repository *MockRepository) {
 				repository.EXPECT().GetByUsername(ctx, followee.Username).Return(followee, nil)
 				repository.EXPECT().GetByEmail(ctx, email).Return(follower, nil)
+				repository.EXPECT().
+					GetRestrictions(ctx, followee.ID, follower.ID).
+					Return(profile.Restrictions{}, nil).
+					Times(2)
 				repository.EXPECT().Follow(ctx, followee.ID, follower.ID).Return(nil)
+				repository.EXPECT().CheckFollowing(ctx, followee.ID, follower.ID).Return(nil)
 			},
 			args: args{
 				followeeUsername: followee.Username,
@@ -361,11 +443,41 @@ func TestService_Follow(t *testing.T) {
 			want:    profile.Profile{},
 			wantErr: true,
 		},
+		{
+			name: "follow self",
+			mock: func(repository *MockRepository) {
+				repository.EXPECT().GetByUsername(ctx, followee.Username).Return(followee, nil)
+				repository.EXPECT().GetByEmail(ctx, email).Return(followee, nil)
+			},
+			args: args{
+				followeeUsername: followee.Username,
+				email:            email,
+			},
+			want:    profile.Profile{},
+			wantErr: true,
+		},
+		{
+			name: "check following error",
+			mock: func(repository *MockRepository) {
+				repository.EXPECT().GetByUsername(ctx, followee.Username).Return(followee, nil)
+				repository.EXPECT().GetByEmail(ctx, email).Return(follower, nil)
+				repository.EXPECT().GetRestrictions(ctx, followee.ID, follower.ID).Return(profile.Restrictions{}, nil)
+				repository.EXPECT().Follow(ctx, followee.ID, follower.ID).Return(nil)
+				repository.EXPECT().CheckFollowing(ctx, followee.ID, follower.ID).Return(errCheckFollowingRepository)
+			},
+			args: args{
+				followeeUsername: followee.Username,
+				email:            email,
+			},
+			want:    profile.Profile{},
+			wantErr: true,
+		},
 		{
 			name: "follow error",
 			mock: func(repository *MockRepository) {
 				repository.EXPECT().GetByUsername(ctx, followee.Username).Return(followee, nil)
 				repository.EXPECT().GetByEmail(ctx, email).Return(follower, nil)
+				repository.EXPECT().GetRestrictions(ctx, followee.ID, follower.ID).Return(profile.Restrictions{}, nil)
 				repository.EXPECT().Follow(ctx, followee.ID, follower.ID).Return(errFollowRepository)
 			},
 			args: args{
@@ -375,6 +487,22 @@ func TestService_Follow(t *testing.T) {
 			want:    profile.Profile{},
 			wantErr: true,
 		},
+		{
+			name: "follow blocked",
+			mock: func(repository *MockRepository) {
+				repository.EXPECT().GetByUsername(ctx, followee.Username).Return(followee, nil)
+				repository.EXPECT().GetByEmail(ctx, email).Return(follower, nil)
+				repository.EXPECT().
+					GetRestrictions(ctx, followee.ID, follower.ID).
+					Return(profile.Restrictions{Blocking: true}, nil)
+			},
+			args: args{
+				followeeUsername: followee.Username,
+				email:            email,
+			},
+			want:    profile.Profile{},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -394,6 +522,37 @@ func TestService_Follow(t *testing.T) {
 	}
 }
 
+func TestService_FollowPrivate(t *testing.T) {
+	t.Parallel()
+
+	ctx := logger.ContextWithLogger(context.Background(), zap.L())
+	email := faker.Email()
+
+	followee := createProfile(t, false)
+	followee.Private = true
+	follower := createProfile(t, false)
+	requestedFollowee := followee
+	requestedFollowee.Requested = true
+
+	service, repository := mockService(t)
+
+	repository.EXPECT().GetByUsername(ctx, followee.Username).Return(followee, nil)
+	repository.EXPECT().GetByEmail(ctx, email).Return(follower, nil)
+	gomock.InOrder(
+		repository.EXPECT().GetRestrictions(ctx, followee.ID, follower.ID).Return(profile.Restrictions{}, nil),
+		repository.EXPECT().GetRestrictions(ctx, followee.ID, follower.ID).Return(profile.Restrictions{Requested: true}, nil),
+	)
+	repository.EXPECT().
+		CheckFollowing(ctx, followee.ID, follower.ID).
+		Return(errNotFoundFollowRepository).
+		Times(2)
+	repository.EXPECT().RequestFollow(ctx, followee.ID, follower.ID).Return(nil)
+
+	got, err := service.Follow(ctx, email, followee.Username)
+	require.NoError(t, err)
+	require.Equal(t, requestedFollowee, got)
+}
+
 func TestService_Unfollow(t *testing.T) {
 	t.Parallel()
 
@@ -423,6 +582,8 @@ func TestService_Unfollow(t *testing.T) {
 				repository.EXPECT().GetByUsername(ctx, followee.Username).Return(followee, nil)
 				repository.EXPECT().GetByEmail(ctx, email).Return(follower, nil)
 				repository.EXPECT().Unfollow(ctx, followee.ID, follower.ID).Return(nil)
+				repository.EXPECT().CheckFollowing(ctx, followee.ID, follower.ID).Return(errNotFoundFollowRepository)
+				repository.EXPECT().GetRestrictions(ctx, followee.ID, follower.ID).Return(profile.Restrictions{}, nil)
 			},
 			args: args{
 				followeeUsername: followee.Username,
@@ -457,6 +618,43 @@ func TestService_Unfollow(t *testing.T) {
 			want:    profile.Profile{},
 			wantErr: true,
 		},
+		{
+			name: "not following",
+			mock: func(repository *MockRepository) {
+				repository.EXPECT().GetByUsername(ctx, followee.Username).Return(followee, nil)
+				repository.EXPECT().GetByEmail(ctx, email).Return(follower, nil)
+				repository.EXPECT().
+					Unfollow(ctx, followee.ID, follower.ID).
+					Return(fmt.Errorf("not following: %w", profile.ErrNotFollowing))
+				repository.EXPECT().
+					DeleteFollowRequest(ctx, followee.ID, follower.ID).
+					Return(fmt.Errorf("no follow request: %w", profile.ErrFollowRequestNotFound))
+			},
+			args: args{
+				followeeUsername: followee.Username,
+				email:            email,
+			},
+			want:    profile.Profile{},
+			wantErr: true,
+		},
+		{
+			name: "cancel follow request",
+			mock: func(repository *MockRepository) {
+				repository.EXPECT().GetByUsername(ctx, followee.Username).Return(followee, nil)
+				repository.EXPECT().GetByEmail(ctx, email).Return(follower, nil)
+				repository.EXPECT().
+					Unfollow(ctx, followee.ID, follower.ID).
+					Return(fmt.Errorf("not following: %w", profile.ErrNotFollowing))
+				repository.EXPECT().DeleteFollowRequest(ctx, followee.ID, follower.ID).Return(nil)
+				repository.EXPECT().CheckFollowing(ctx, followee.ID, follower.ID).Return(errNotFoundFollowRepository)
+				repository.EXPECT().GetRestrictions(ctx, followee.ID, follower.ID).Return(profile.Restrictions{}, nil)
+			},
+			args: args{
+				followeeUsername: followee.Username,
+				email:            email,
+			},
+			want: realFollowee,
+		},
 		{
 			name: "unfollow error",
 			mock: func(repository *MockRepository) {
@@ -489,3 +687,429 @@ func TestService_Unfollow(t *testing.T) {
 		})
 	}
 }
+
+func TestService_Block(t *testing.T) {
+	t.Parallel()
+
+	ctx := logger.ContextWithLogger(context.Background(), zap.L())
+	email := faker.Email()
+
+	blocked := createProfile(t, false)
+	blocker := createProfile(t, false)
+	realBlocked := blocked
+	realBlocked.Blocking = true
+
+	tests := []struct {
+		name    string
+		mock    func(*MockRepository)
+		want    profile.Profile
+		wantErr bool
+	}{
+		{
+			name: "success block",
+			mock: func(repository *MockRepository) {
+				repository.EXPECT().GetByUsername(ctx, blocked.Username).Return(blocked, nil)
+				repository.EXPECT().GetByEmail(ctx, email).Return(blocker, nil)
+				repository.EXPECT().Block(ctx, blocker.ID, blocked.ID).Return(nil)
+				repository.EXPECT().CheckFollowing(ctx, blocked.ID, blocker.ID).Return(errNotFoundFollowRepository)
+				repository.EXPECT().
+					GetRestrictions(ctx, blocked.ID, blocker.ID).
+					Return(profile.Restrictions{Blocking: true}, nil)
+			},
+			want: realBlocked,
+		},
+		{
+			name: "block self",
+			mock: func(repository *MockRepository) {
+				repository.EXPECT().GetByUsername(ctx, blocked.Username).Return(blocked, nil)
+				repository.EXPECT().GetByEmail(ctx, email).Return(blocked, nil)
+			},
+			want:    profile.Profile{},
+			wantErr: true,
+		},
+		{
+			name: "block error",
+			mock: func(repository *MockRepository) {
+				repository.EXPECT().GetByUsername(ctx, blocked.Username).Return(blocked, nil)
+				repository.EXPECT().GetByEmail(ctx, email).Return(blocker, nil)
+				repository.EXPECT().Block(ctx, blocker.ID, blocked.ID).Return(errBlockRepository)
+			},
+			want:    profile.Profile{},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			service, repository := mockService(t)
+
+			tt.mock(repository)
+
+			got, err := service.Block(ctx, email, blocked.Username)
+			require.True(t, (err != nil) == tt.wantErr)
+			require.True(t, reflect.DeepEqual(tt.want, got))
+		})
+	}
+}
+
+func TestService_Unmute(t *testing.T) {
+	t.Parallel()
+
+	ctx := logger.ContextWithLogger(context.Background(), zap.L())
+	email := faker.Email()
+
+	muted := createProfile(t, false)
+	muter := createProfile(t, false)
+
+	service, repository := mockService(t)
+
+	repository.EXPECT().GetByUsername(ctx, muted.Username).Return(muted, nil)
+	repository.EXPECT().GetByEmail(ctx, email).Return(muter, nil)
+	repository.EXPECT().Unmute(ctx, muter.ID, muted.ID).Return(fmt.Errorf("not muting: %w", profile.ErrNotMuting))
+
+	_, err := service.Unmute(ctx, email, muted.Username)
+	require.ErrorIs(t, err, profile.ErrNotMuting)
+}
+
+func TestService_ListFollowers(t *testing.T) {
+	t.Parallel()
+
+	ctx := logger.ContextWithLogger(context.Background(), zap.L())
+	owner := createProfile(t, false)
+	viewer := createProfile(t, false)
+	email := faker.Email()
+	now := time.Now().UTC()
+	follows := []profile.Follow{
+		{Profile: createProfile(t, true), FollowedAt: now},
+		{Profile: createProfile(t, false), FollowedAt: now.Add(-time.Minute)},
+		{Profile: createProfile(t, false), FollowedAt: now.Add(-time.Hour)},
+	}
+	cursor := profile.Cursor{FollowedAt: follows[1].FollowedAt, ID: follows[1].Profile.ID}
+
+	tests := []struct {
+		name    string
+		mock    func(*MockRepository)
+		dto     profile.ListDTO
+		want    profile.FollowsPage
+		wantErr bool
+	}{
+		{
+			name: "page with next cursor",
+			mock: func(repository *MockRepository) {
+				repository.EXPECT().GetByUsername(ctx, owner.Username).Return(owner, nil)
+				repository.EXPECT().GetByEmail(ctx, email).Return(viewer, nil)
+				repository.EXPECT().GetRestrictions(ctx, owner.ID, viewer.ID).Return(profile.Restrictions{}, nil)
+				repository.EXPECT().ListFollowers(ctx, profile.ListFollowsDTO{
+					ProfileID: owner.ID,
+					ViewerID:  viewer.ID,
+					Limit:     3,
+				}).Return(follows, nil)
+			},
+			dto: profile.ListDTO{Username: owner.Username, ViewerEmail: email, Limit: 2},
+			want: profile.FollowsPage{
+				Profiles:   []profile.Profile{follows[0].Profile, follows[1].Profile},
+				NextCursor: cursor.String(),
+			},
+		},
+		{
+			name: "last page of anonymous request",
+			mock: func(repository *MockRepository) {
+				repository.EXPECT().GetByUsername(ctx, owner.Username).Return(owner, nil)
+				repository.EXPECT().ListFollowers(ctx, profile.ListFollowsDTO{
+					ProfileID: owner.ID,
+					After:     &cursor,
+					Limit:     21,
+				}).Return(follows[2:], nil)
+			},
+			dto: profile.ListDTO{Username: owner.Username, Cursor: cursor.String()},
+			want: profile.FollowsPage{
+				Profiles: []profile.Profile{follows[2].Profile},
+			},
+		},
+		{
+			name:    "invalid cursor",
+			mock:    func(repository *MockRepository) {},
+			dto:     profile.ListDTO{Username: owner.Username, Cursor: "cursor"},
+			want:    profile.FollowsPage{},
+			wantErr: true,
+		},
+		{
+			name: "profile error",
+			mock: func(repository *MockRepository) {
+				repository.EXPECT().
+					GetByUsername(ctx, owner.Username).
+					Return(profile.Profile{}, errGetByUsernameRepository)
+			},
+			dto:     profile.ListDTO{Username: owner.Username},
+			want:    profile.FollowsPage{},
+			wantErr: true,
+		},
+		{
+			name: "viewer error",
+			mock: func(repository *MockRepository) {
+				repository.EXPECT().GetByUsername(ctx, owner.Username).Return(owner, nil)
+				repository.EXPECT().GetByEmail(ctx, email).Return(profile.Profile{}, errGetByEmailRepository)
+			},
+			dto:     profile.ListDTO{Username: owner.Username, ViewerEmail: email},
+			want:    profile.FollowsPage{},
+			wantErr: true,
+		},
+		{
+			name: "restrictions error",
+			mock: func(repository *MockRepository) {
+				repository.EXPECT().GetByUsername(ctx, owner.Username).Return(owner, nil)
+				repository.EXPECT().GetByEmail(ctx, email).Return(viewer, nil)
+				repository.EXPECT().
+					GetRestrictions(ctx, owner.ID, viewer.ID).
+					Return(profile.Restrictions{}, errFollowRepository)
+			},
+			dto:     profile.ListDTO{Username: owner.Username, ViewerEmail: email},
+			want:    profile.FollowsPage{},
+			wantErr: true,
+		},
+		{
+			name: "repository error",
+			mock: func(repository *MockRepository) {
+				repository.EXPECT().GetByUsername(ctx, owner.Username).Return(owner, nil)
+				repository.EXPECT().ListFollowers(ctx, gomock.Any()).Return(nil, errFollowRepository)
+			},
+			dto:     profile.ListDTO{Username: owner.Username},
+			want:    profile.FollowsPage{},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			service, repository := mockService(t)
+
+			tt.mock(repository)
+
+			got, err := service.ListFollowers(ctx, tt.dto)
+			require.True(t, (err != nil) == tt.wantErr)
+			require.True(t, reflect.DeepEqual(tt.want, got))
+		})
+	}
+}
+
+func TestService_ListFollowing(t *testing.T) {
+	t.Parallel()
+
+	ctx := logger.ContextWithLogger(context.Background(), zap.L())
+	owner := createProfile(t, false)
+	followee := createProfile(t, false)
+
+	service, repository := mockService(t)
+
+	repository.EXPECT().GetByUsername(ctx, owner.Username).Return(owner, nil)
+	repository.EXPECT().ListFollowing(ctx, profile.ListFollowsDTO{
+		ProfileID: owner.ID,
+		Limit:     101,
+	}).Return([]profile.Follow{{Profile: followee, FollowedAt: time.Now()}}, nil)
+
+	got, err := service.ListFollowing(ctx, profile.ListDTO{Username: owner.Username, Limit: 1000})
+	require.NoError(t, err)
+	require.Equal(t, profile.FollowsPage{Profiles: []profile.Profile{followee}}, got)
+}
+
+func TestService_ListPrivateFollows(t *testing.T) {
+	t.Parallel()
+
+	ctx := logger.ContextWithLogger(context.Background(), zap.L())
+	email := faker.Email()
+	owner := createProfile(t, false)
+	owner.Private = true
+	viewer := createProfile(t, false)
+	followee := createProfile(t, false)
+	follows := []profile.Follow{{Profile: followee, FollowedAt: time.Now()}}
+
+	tests := []struct {
+		name    string
+		mock    func(*MockRepository)
+		dto     profile.ListDTO
+		want    profile.FollowsPage
+		wantErr error
+	}{
+		{
+			name: "owner",
+			mock: func(repository *MockRepository) {
+				repository.EXPECT().GetByUsername(ctx, owner.Username).Return(owner, nil)
+				repository.EXPECT().GetByEmail(ctx, email).Return(owner, nil)
+				repository.EXPECT().ListFollowing(ctx, gomock.Any()).Return(follows, nil)
+			},
+			dto:  profile.ListDTO{Username: owner.Username, ViewerEmail: email},
+			want: profile.FollowsPage{Profiles: []profile.Profile{followee}},
+		},
+		{
+			name: "approved follower",
+			mock: func(repository *MockRepository) {
+				repository.EXPECT().GetByUsername(ctx, owner.Username).Return(owner, nil)
+				repository.EXPECT().GetByEmail(ctx, email).Return(viewer, nil)
+				repository.EXPECT().GetRestrictions(ctx, owner.ID, viewer.ID).Return(profile.Restrictions{}, nil)
+				repository.EXPECT().CheckFollowing(ctx, owner.ID, viewer.ID).Return(nil)
+				repository.EXPECT().ListFollowing(ctx, gomock.Any()).Return(follows, nil)
+			},
+			dto:  profile.ListDTO{Username: owner.Username, ViewerEmail: email},
+			want: profile.FollowsPage{Profiles: []profile.Profile{followee}},
+		},
+		{
+			name: "not a follower",
+			mock: func(repository *MockRepository) {
+				repository.EXPECT().GetByUsername(ctx, owner.Username).Return(owner, nil)
+				repository.EXPECT().GetByEmail(ctx, email).Return(viewer, nil)
+				repository.EXPECT().GetRestrictions(ctx, owner.ID, viewer.ID).Return(profile.Restrictions{}, nil)
+				repository.EXPECT().CheckFollowing(ctx, owner.ID, viewer.ID).Return(errNotFoundFollowRepository)
+			},
+			dto:     profile.ListDTO{Username: owner.Username, ViewerEmail: email},
+			wantErr: profile.ErrPrivateProfile,
+		},
+		{
+			name: "anonymous",
+			mock: func(repository *MockRepository) {
+				repository.EXPECT().GetByUsername(ctx, owner.Username).Return(owner, nil)
+			},
+			dto:     profile.ListDTO{Username: owner.Username},
+			wantErr: profile.ErrPrivateProfile,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			service, repository := mockService(t)
+
+			tt.mock(repository)
+
+			got, err := service.ListFollowing(ctx, tt.dto)
+			require.ErrorIs(t, err, tt.wantErr)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestService_ListBlockedFollows(t *testing.T) {
+	t.Parallel()
+
+	ctx := logger.ContextWithLogger(context.Background(), zap.L())
+	email := faker.Email()
+	owner := createProfile(t, false)
+	viewer := createProfile(t, false)
+
+	for _, private := range []bool{false, true} {
+		private := private
+
+		t.Run(fmt.Sprintf("private %t", private), func(t *testing.T) {
+			t.Parallel()
+
+			owner := owner
+			owner.Private = private
+
+			service, repository := mockService(t)
+
+			repository.EXPECT().GetByUsername(ctx, owner.Username).Return(owner, nil).Times(2)
+			repository.EXPECT().GetByEmail(ctx, email).Return(viewer, nil).Times(2)
+			repository.EXPECT().
+				GetRestrictions(ctx, owner.ID, viewer.ID).
+				Return(profile.Restrictions{BlockedBy: true}, nil).
+				Times(2)
+
+			dto := profile.ListDTO{Username: owner.Username, ViewerEmail: email}
+
+			_, err := service.ListFollowers(ctx, dto)
+			require.ErrorIs(t, err, profile.ErrNotFound)
+
+			_, err = service.ListFollowing(ctx, dto)
+			require.ErrorIs(t, err, profile.ErrNotFound)
+		})
+	}
+}
+
+func TestService_ApproveFollowRequest(t *testing.T) {
+	t.Parallel()
+
+	ctx := logger.ContextWithLogger(context.Background(), zap.L())
+	email := faker.Email()
+
+	follower := createProfile(t, false)
+	followee := createProfile(t, false)
+
+	tests := []struct {
+		name    string
+		mock    func(*MockRepository)
+		want    profile.Profile
+		wantErr error
+	}{
+		{
+			name: "success approve",
+			mock: func(repository *MockRepository) {
+				repository.EXPECT().GetByUsername(ctx, follower.Username).Return(follower, nil)
+				repository.EXPECT().GetByEmail(ctx, email).Return(followee, nil)
+				repository.EXPECT().ApproveFollowRequest(ctx, followee.ID, follower.ID).Return(nil)
+				repository.EXPECT().CheckFollowing(ctx, follower.ID, followee.ID).Return(errNotFoundFollowRepository)
+				repository.EXPECT().GetRestrictions(ctx, follower.ID, followee.ID).Return(profile.Restrictions{}, nil)
+			},
+			want: follower,
+		},
+		{
+			name: "no follow request",
+			mock: func(repository *MockRepository) {
+				repository.EXPECT().GetByUsername(ctx, follower.Username).Return(follower, nil)
+				repository.EXPECT().GetByEmail(ctx, email).Return(followee, nil)
+				repository.EXPECT().
+					ApproveFollowRequest(ctx, followee.ID, follower.ID).
+					Return(fmt.Errorf("no follow request: %w", profile.ErrFollowRequestNotFound))
+			},
+			want:    profile.Profile{},
+			wantErr: profile.ErrFollowRequestNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			service, repository := mockService(t)
+
+			tt.mock(repository)
+
+			got, err := service.ApproveFollowRequest(ctx, email, follower.Username)
+			require.ErrorIs(t, err, tt.wantErr)
+			require.True(t, reflect.DeepEqual(tt.want, got))
+		})
+	}
+}
+
+func TestService_ListFollowRequests(t *testing.T) {
+	t.Parallel()
+
+	ctx := logger.ContextWithLogger(context.Background(), zap.L())
+	email := faker.Email()
+	owner := createProfile(t, false)
+	requester := createProfile(t, false)
+
+	service, repository := mockService(t)
+
+	repository.EXPECT().GetByEmail(ctx, email).Return(owner, nil)
+	repository.EXPECT().ListFollowRequests(ctx, profile.ListFollowsDTO{
+		ProfileID: owner.ID,
+		ViewerID:  owner.ID,
+		Limit:     21,
+	}).Return([]profile.Follow{{Profile: requester, FollowedAt: time.Now()}}, nil)
+
+	got, err := service.ListFollowRequests(ctx, profile.ListRequestsDTO{Email: email})
+	require.NoError(t, err)
+	require.Equal(t, profile.FollowsPage{Profiles: []profile.Profile{requester}}, got)
+}
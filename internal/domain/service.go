@@ -2,21 +2,52 @@
 package domain
 
 import (
+	"github.com/maypok86/conduit/internal/domain/export"
 	"github.com/maypok86/conduit/internal/domain/profile"
 	"github.com/maypok86/conduit/internal/domain/user"
-	"github.com/maypok86/conduit/internal/repository/psql"
 )
 
 // Services is a collection of all services in the system.
 type Services struct {
 	User    user.Service
 	Profile profile.Service
+	Export  export.Service
+}
+
+// Repositories is a collection of repositories the services depend on.
+type Repositories struct {
+	User    user.Repository
+	Profile profile.Repository
+	Export  export.Repository
+}
+
+// Deps is a domain services dependencies.
+type Deps struct {
+	Repositories     Repositories
+	PasswordHasher   user.PasswordHasher
+	Transactor       profile.Transactor
+	ExportDispatcher export.Dispatcher
+	UserOptions      []user.Option
+	ExportOptions    []export.Option
 }
 
 // NewServices returns a new instance of Services.
-func NewServices(repositories psql.Repositories, passwordHasher user.PasswordHasher) Services {
+func NewServices(deps Deps) Services {
+	userService := user.NewService(
+		deps.Repositories.User,
+		deps.PasswordHasher,
+		deps.Transactor,
+		deps.UserOptions...,
+	)
+
 	return Services{
-		User:    user.NewService(repositories.User, passwordHasher),
-		Profile: profile.NewService(repositories.Profile),
+		User:    userService,
+		Profile: profile.NewService(deps.Repositories.Profile, deps.Transactor),
+		Export: export.NewService(
+			deps.Repositories.Export,
+			userService,
+			deps.ExportDispatcher,
+			deps.ExportOptions...,
+		),
 	}
 }
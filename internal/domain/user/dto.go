@@ -15,5 +15,16 @@ type UpdateDTO struct {
 	Email     *string
 	Bio       *string
 	Image     *string
+	Private   *bool
 	UpdatedAt time.Time
+	// UsernameCooldownUntil is the time until the previous username can be claimed only by its owner.
+	UsernameCooldownUntil time.Time
+}
+
+// PurgeDTO is a purge deleted users dto.
+type PurgeDTO struct {
+	Mode             DeletionMode
+	DeletedBefore    time.Time
+	QuarantinedUntil time.Time
+	PurgedAt         time.Time
 }
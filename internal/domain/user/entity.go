@@ -6,13 +6,34 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/maypok86/conduit/pkg/slugerr"
 )
 
 var (
 	// ErrAlreadyExist is an error that indicates that user already exists.
 	ErrAlreadyExist = errors.New("user with given email or nickname already exist")
+	// ErrUsernameAlreadyExist is an error that indicates that username is taken by another user.
+	ErrUsernameAlreadyExist = slugerr.NewConflictError("username has already been taken", "username-already-exist")
+	// ErrEmailAlreadyExist is an error that indicates that email is taken by another user.
+	ErrEmailAlreadyExist = slugerr.NewConflictError("email has already been taken", "email-already-exist")
 	// ErrNotFound is an error that indicates that user not found.
 	ErrNotFound = errors.New("user not found")
+	// ErrUsernameQuarantined is an error that indicates that username was released recently and can not be claimed yet.
+	ErrUsernameQuarantined = slugerr.NewConflictError("username is not available yet", "username-quarantined")
+	// ErrRenameLimitExceeded is an error that indicates that user changed username too many times recently.
+	ErrRenameLimitExceeded = slugerr.NewTooManyRequestsError("username can not be changed so often", "rename-limit-exceeded")
+	// ErrUnknownDeletionMode is an error that indicates that deletion mode is not supported.
+	ErrUnknownDeletionMode = errors.New("unknown deletion mode")
+)
+
+// DeletionMode defines what happens to the account data when the deletion grace period ends.
+type DeletionMode string
+
+const (
+	// DeletionModeAnonymize keeps the user row under a placeholder identity and drops the personal data.
+	DeletionModeAnonymize DeletionMode = "anonymize"
+	// DeletionModeCascade removes the user row and everything that references it.
+	DeletionModeCascade DeletionMode = "cascade"
 )
 
 // User is a user entity.
@@ -23,8 +44,16 @@ type User struct {
 	Password  string
 	Bio       *string
 	Image     *string
+	Private   bool
 	CreatedAt time.Time
 	UpdatedAt time.Time
+	DeletedAt *time.Time
+}
+
+// Deletion is a scheduled account deletion.
+type Deletion struct {
+	DeletedAt time.Time
+	PurgeAt   time.Time
 }
 
 // GetBio returns bio.
@@ -44,3 +73,8 @@ func (u User) GetImage() string {
 
 	return *u.Image
 }
+
+// IsDeleted checks that user requested account deletion.
+func (u User) IsDeleted() bool {
+	return u.DeletedAt != nil
+}
@@ -7,8 +7,10 @@ package user_test
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
 	user "github.com/maypok86/conduit/internal/domain/user"
 )
 
@@ -35,6 +37,35 @@ func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
 	return m.recorder
 }
 
+// CheckUsernameQuarantine mocks base method.
+func (m *MockRepository) CheckUsernameQuarantine(ctx context.Context, username string, userID uuid.UUID, now time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckUsernameQuarantine", ctx, username, userID, now)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckUsernameQuarantine indicates an expected call of CheckUsernameQuarantine.
+func (mr *MockRepositoryMockRecorder) CheckUsernameQuarantine(ctx, username, userID, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckUsernameQuarantine", reflect.TypeOf((*MockRepository)(nil).CheckUsernameQuarantine), ctx, username, userID, now)
+}
+
+// CountRenames mocks base method.
+func (m *MockRepository) CountRenames(ctx context.Context, userID uuid.UUID, since time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountRenames", ctx, userID, since)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountRenames indicates an expected call of CountRenames.
+func (mr *MockRepositoryMockRecorder) CountRenames(ctx, userID, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountRenames", reflect.TypeOf((*MockRepository)(nil).CountRenames), ctx, userID, since)
+}
+
 // Create mocks base method.
 func (m *MockRepository) Create(ctx context.Context, dto user.User) (user.User, error) {
 	m.ctrl.T.Helper()
@@ -50,6 +81,20 @@ func (mr *MockRepositoryMockRecorder) Create(ctx, dto interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRepository)(nil).Create), ctx, dto)
 }
 
+// DeleteByEmail mocks base method.
+func (m *MockRepository) DeleteByEmail(ctx context.Context, email string, deletedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByEmail", ctx, email, deletedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByEmail indicates an expected call of DeleteByEmail.
+func (mr *MockRepositoryMockRecorder) DeleteByEmail(ctx, email, deletedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByEmail", reflect.TypeOf((*MockRepository)(nil).DeleteByEmail), ctx, email, deletedAt)
+}
+
 // GetByEmail mocks base method.
 func (m *MockRepository) GetByEmail(ctx context.Context, email string) (user.User, error) {
 	m.ctrl.T.Helper()
@@ -65,6 +110,50 @@ func (mr *MockRepositoryMockRecorder) GetByEmail(ctx, email interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByEmail", reflect.TypeOf((*MockRepository)(nil).GetByEmail), ctx, email)
 }
 
+// LockByEmail mocks base method.
+func (m *MockRepository) LockByEmail(ctx context.Context, email string) (user.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockByEmail", ctx, email)
+	ret0, _ := ret[0].(user.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockByEmail indicates an expected call of LockByEmail.
+func (mr *MockRepositoryMockRecorder) LockByEmail(ctx, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockByEmail", reflect.TypeOf((*MockRepository)(nil).LockByEmail), ctx, email)
+}
+
+// Purge mocks base method.
+func (m *MockRepository) Purge(ctx context.Context, dto user.PurgeDTO) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx, dto)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockRepositoryMockRecorder) Purge(ctx, dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockRepository)(nil).Purge), ctx, dto)
+}
+
+// Restore mocks base method.
+func (m *MockRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockRepositoryMockRecorder) Restore(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockRepository)(nil).Restore), ctx, id)
+}
+
 // UpdateByEmail mocks base method.
 func (m *MockRepository) UpdateByEmail(ctx context.Context, email string, updateDTO user.UpdateDTO) (user.User, error) {
 	m.ctrl.T.Helper()
@@ -131,3 +220,40 @@ func (mr *MockPasswordHasherMockRecorder) Hash(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Hash", reflect.TypeOf((*MockPasswordHasher)(nil).Hash), arg0)
 }
+
+// MockTransactor is a mock of Transactor interface.
+type MockTransactor struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransactorMockRecorder
+}
+
+// MockTransactorMockRecorder is the mock recorder for MockTransactor.
+type MockTransactorMockRecorder struct {
+	mock *MockTransactor
+}
+
+// NewMockTransactor creates a new mock instance.
+func NewMockTransactor(ctrl *gomock.Controller) *MockTransactor {
+	mock := &MockTransactor{ctrl: ctrl}
+	mock.recorder = &MockTransactorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransactor) EXPECT() *MockTransactorMockRecorder {
+	return m.recorder
+}
+
+// WithinTransaction mocks base method.
+func (m *MockTransactor) WithinTransaction(ctx context.Context, fn func(context.Context) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithinTransaction", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithinTransaction indicates an expected call of WithinTransaction.
+func (mr *MockTransactorMockRecorder) WithinTransaction(ctx, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithinTransaction", reflect.TypeOf((*MockTransactor)(nil).WithinTransaction), ctx, fn)
+}
@@ -3,16 +3,38 @@ package user
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
+
+	"github.com/google/uuid"
+	"github.com/maypok86/conduit/pkg/tracing"
+	"go.opentelemetry.io/otel"
 )
 
 //go:generate mockgen -source=service.go -destination=mock_test.go -package=user_test
 
+// tracer creates the spans of the service calls.
+var tracer = otel.Tracer("github.com/maypok86/conduit/internal/domain/user")
+
+const (
+	defaultDeletionGracePeriod = 30 * 24 * time.Hour
+	defaultUsernameQuarantine  = 90 * 24 * time.Hour
+	defaultUsernameCooldown    = 30 * 24 * time.Hour
+	defaultRenameLimit         = 3
+	defaultRenameWindow        = 30 * 24 * time.Hour
+)
+
 // Repository is a user repository.
 type Repository interface {
 	Create(ctx context.Context, dto User) (User, error)
 	GetByEmail(ctx context.Context, email string) (User, error)
+	LockByEmail(ctx context.Context, email string) (User, error)
 	UpdateByEmail(ctx context.Context, email string, updateDTO UpdateDTO) (User, error)
+	DeleteByEmail(ctx context.Context, email string, deletedAt time.Time) error
+	Restore(ctx context.Context, id uuid.UUID) error
+	Purge(ctx context.Context, dto PurgeDTO) (int64, error)
+	CheckUsernameQuarantine(ctx context.Context, username string, userID uuid.UUID, now time.Time) error
+	CountRenames(ctx context.Context, userID uuid.UUID, since time.Time) (int, error)
 }
 
 // PasswordHasher is a password hasher.
@@ -21,23 +43,103 @@ type PasswordHasher interface {
 	Check(string, string) error
 }
 
+// Transactor runs functions in a database transaction.
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// Option is a functional option for configuring a Service.
+type Option func(*Service)
+
+// WithDeletionGracePeriod sets the period during which a deleted account can still be restored.
+func WithDeletionGracePeriod(deletionGracePeriod time.Duration) Option {
+	return func(s *Service) {
+		s.deletionGracePeriod = deletionGracePeriod
+	}
+}
+
+// WithDeletionMode sets the way deleted accounts are purged.
+func WithDeletionMode(deletionMode DeletionMode) Option {
+	return func(s *Service) {
+		s.deletionMode = deletionMode
+	}
+}
+
+// WithUsernameQuarantine sets the period during which usernames of purged accounts can not be claimed.
+func WithUsernameQuarantine(usernameQuarantine time.Duration) Option {
+	return func(s *Service) {
+		s.usernameQuarantine = usernameQuarantine
+	}
+}
+
+// WithUsernameCooldown sets the period during which a released username can be claimed only by its previous owner.
+func WithUsernameCooldown(usernameCooldown time.Duration) Option {
+	return func(s *Service) {
+		s.usernameCooldown = usernameCooldown
+	}
+}
+
+// WithRenameLimit sets the maximum number of username changes per account within the window.
+func WithRenameLimit(limit int, window time.Duration) Option {
+	return func(s *Service) {
+		s.renameLimit = limit
+		s.renameWindow = window
+	}
+}
+
 // Service is a user service interface.
 type Service struct {
-	userRepository Repository
-	passwordHasher PasswordHasher
+	userRepository      Repository
+	passwordHasher      PasswordHasher
+	transactor          Transactor
+	deletionGracePeriod time.Duration
+	deletionMode        DeletionMode
+	usernameQuarantine  time.Duration
+	usernameCooldown    time.Duration
+	renameLimit         int
+	renameWindow        time.Duration
 }
 
 // NewService creates a new user service.
-func NewService(userRepository Repository, passwordHasher PasswordHasher) Service {
-	return Service{
-		userRepository: userRepository,
-		passwordHasher: passwordHasher,
+func NewService(
+	userRepository Repository,
+	passwordHasher PasswordHasher,
+	transactor Transactor,
+	opts ...Option,
+) Service {
+	service := Service{
+		userRepository:      userRepository,
+		passwordHasher:      passwordHasher,
+		transactor:          transactor,
+		deletionGracePeriod: defaultDeletionGracePeriod,
+		deletionMode:        DeletionModeAnonymize,
+		usernameQuarantine:  defaultUsernameQuarantine,
+		usernameCooldown:    defaultUsernameCooldown,
+		renameLimit:         defaultRenameLimit,
+		renameWindow:        defaultRenameWindow,
+	}
+
+	for _, opt := range opts {
+		opt(&service)
 	}
+
+	return service
 }
 
 // Create creates a new user.
 func (s Service) Create(ctx context.Context, dto CreateDTO) (User, error) {
+	ctx, span := tracing.Start(ctx, tracer, "user.Service.Create")
+	defer span.End()
+
+	if err := s.userRepository.CheckUsernameQuarantine(ctx, dto.Username, uuid.Nil, time.Now()); err != nil {
+		return User{}, fmt.Errorf("can not create user: %w", err)
+	}
+
+	// Hashing is slow on purpose, its own span tells it apart from the queries.
+	_, hashSpan := tracing.Start(ctx, tracer, "user.PasswordHasher.Hash")
 	passwordHash, err := s.passwordHasher.Hash(dto.Password)
+	hashSpan.End()
+
 	if err != nil {
 		return User{}, fmt.Errorf("can not hash password: %w", err)
 	}
@@ -61,36 +163,148 @@ func (s Service) Create(ctx context.Context, dto CreateDTO) (User, error) {
 
 // GetByEmail returns user by email.
 func (s Service) GetByEmail(ctx context.Context, email string) (User, error) {
+	ctx, span := tracing.Start(ctx, tracer, "user.Service.GetByEmail")
+	defer span.End()
+
 	user, err := s.userRepository.GetByEmail(ctx, email)
 	if err != nil {
 		return User{}, fmt.Errorf("can not get user by email: %w", err)
 	}
 
+	if user.IsDeleted() {
+		return User{}, fmt.Errorf("can not get user by email: %w", ErrNotFound)
+	}
+
 	return user, nil
 }
 
-// Login provides user login.
+// Login provides user login. Logging in during the deletion grace period restores the account.
 func (s Service) Login(ctx context.Context, email, password string) (User, error) {
-	user, err := s.GetByEmail(ctx, email)
+	ctx, span := tracing.Start(ctx, tracer, "user.Service.Login")
+	defer span.End()
+
+	user, err := s.userRepository.GetByEmail(ctx, email)
 	if err != nil {
-		return User{}, err
+		return User{}, fmt.Errorf("can not get user by email: %w", err)
+	}
+
+	if user.IsDeleted() && time.Since(*user.DeletedAt) >= s.deletionGracePeriod {
+		return User{}, fmt.Errorf("can not get user by email: %w", ErrNotFound)
 	}
 
-	if err := s.passwordHasher.Check(password, user.Password); err != nil {
+	_, checkSpan := tracing.Start(ctx, tracer, "user.PasswordHasher.Check")
+	err = s.passwordHasher.Check(password, user.Password)
+	checkSpan.End()
+
+	if err != nil {
 		return User{}, fmt.Errorf("can not check password: %w", err)
 	}
 
+	if user.IsDeleted() {
+		if err := s.userRepository.Restore(ctx, user.ID); err != nil {
+			return User{}, fmt.Errorf("can not restore user: %w", err)
+		}
+
+		user.DeletedAt = nil
+	}
+
 	return user, nil
 }
 
-// UpdateByEmail updates user by email.
+// UpdateByEmail updates user by email. The previous username is kept in the history and
+// can be claimed only by the same user until the cooldown ends. Making the account public approves
+// the pending follow requests.
 func (s Service) UpdateByEmail(ctx context.Context, email string, dto UpdateDTO) (User, error) {
+	ctx, span := tracing.Start(ctx, tracer, "user.Service.UpdateByEmail")
+	defer span.End()
+
 	dto.UpdatedAt = time.Now()
 
-	user, err := s.userRepository.UpdateByEmail(ctx, email, dto)
+	var user User
+
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if dto.Username != nil {
+			if err := s.checkRename(ctx, email, *dto.Username, dto.UpdatedAt); err != nil {
+				return err
+			}
+
+			dto.UsernameCooldownUntil = dto.UpdatedAt.Add(s.usernameCooldown)
+		}
+
+		updated, err := s.userRepository.UpdateByEmail(ctx, email, dto)
+		if err != nil {
+			return err
+		}
+
+		user = updated
+
+		return nil
+	})
 	if err != nil {
 		return User{}, fmt.Errorf("can not update user: %w", err)
 	}
 
 	return user, nil
 }
+
+// checkRename checks that the user can take username. The user stays locked until the update, so concurrent
+// renames are counted one after another and can not exceed the limit together. A rename that changes only
+// the case keeps the username, it is neither counted nor recorded in the history.
+func (s Service) checkRename(ctx context.Context, email, username string, now time.Time) error {
+	user, err := s.userRepository.LockByEmail(ctx, email)
+	if err != nil {
+		return fmt.Errorf("can not lock user: %w", err)
+	}
+
+	if strings.EqualFold(user.Username, username) {
+		return nil
+	}
+
+	renames, err := s.userRepository.CountRenames(ctx, user.ID, now.Add(-s.renameWindow))
+	if err != nil {
+		return fmt.Errorf("can not count renames: %w", err)
+	}
+
+	if renames >= s.renameLimit {
+		return ErrRenameLimitExceeded
+	}
+
+	return s.userRepository.CheckUsernameQuarantine(ctx, username, user.ID, now)
+}
+
+// DeleteByEmail schedules user deletion. The account is hidden immediately and purged after the grace period.
+func (s Service) DeleteByEmail(ctx context.Context, email string) (Deletion, error) {
+	ctx, span := tracing.Start(ctx, tracer, "user.Service.DeleteByEmail")
+	defer span.End()
+
+	now := time.Now()
+
+	if err := s.userRepository.DeleteByEmail(ctx, email, now); err != nil {
+		return Deletion{}, fmt.Errorf("can not delete user: %w", err)
+	}
+
+	return Deletion{
+		DeletedAt: now,
+		PurgeAt:   now.Add(s.deletionGracePeriod),
+	}, nil
+}
+
+// PurgeDeleted purges users whose deletion grace period has ended and returns the number of purged users.
+func (s Service) PurgeDeleted(ctx context.Context) (int64, error) {
+	ctx, span := tracing.Start(ctx, tracer, "user.Service.PurgeDeleted")
+	defer span.End()
+
+	now := time.Now()
+
+	purged, err := s.userRepository.Purge(ctx, PurgeDTO{
+		Mode:             s.deletionMode,
+		DeletedBefore:    now.Add(-s.deletionGracePeriod),
+		QuarantinedUntil: now.Add(s.usernameQuarantine),
+		PurgedAt:         now,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("can not purge deleted users: %w", err)
+	}
+
+	return purged, nil
+}
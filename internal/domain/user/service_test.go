@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -29,7 +30,13 @@ func mockService(t *testing.T) (user.Service, *MockRepository, *MockPasswordHash
 
 	repository := NewMockRepository(mockCtrl)
 	passwordHasher := NewMockPasswordHasher(mockCtrl)
-	service := user.NewService(repository, passwordHasher)
+	transactor := NewMockTransactor(mockCtrl)
+	transactor.EXPECT().WithinTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		},
+	).AnyTimes()
+	service := user.NewService(repository, passwordHasher, transactor)
 
 	return service, repository, passwordHasher
 }
@@ -71,6 +78,7 @@ func TestService_Create(t *testing.T) {
 		{
 			name: "creation user",
 			mock: func(repository *MockRepository, hasher *MockPasswordHasher) {
+				repository.EXPECT().CheckUsernameQuarantine(ctx, dto.Username, uuid.Nil, gomock.Any()).Return(nil)
 				repository.EXPECT().Create(ctx, gomock.Any()).Return(validUser, nil)
 				hasher.EXPECT().Hash(dto.Password).Return(validUser.Password, nil)
 			},
@@ -79,9 +87,23 @@ func TestService_Create(t *testing.T) {
 			},
 			want: validUser,
 		},
+		{
+			name: "quarantined username",
+			mock: func(repository *MockRepository, hasher *MockPasswordHasher) {
+				repository.EXPECT().
+					CheckUsernameQuarantine(ctx, dto.Username, uuid.Nil, gomock.Any()).
+					Return(user.ErrUsernameQuarantined)
+			},
+			args: args{
+				dto: dto,
+			},
+			want:    user.User{},
+			wantErr: true,
+		},
 		{
 			name: "hasher error",
 			mock: func(repository *MockRepository, hasher *MockPasswordHasher) {
+				repository.EXPECT().CheckUsernameQuarantine(ctx, dto.Username, uuid.Nil, gomock.Any()).Return(nil)
 				hasher.EXPECT().Hash(dto.Password).Return("", errHasher)
 			},
 			args: args{
@@ -93,6 +115,7 @@ func TestService_Create(t *testing.T) {
 		{
 			name: "repository error",
 			mock: func(repository *MockRepository, hasher *MockPasswordHasher) {
+				repository.EXPECT().CheckUsernameQuarantine(ctx, dto.Username, uuid.Nil, gomock.Any()).Return(nil)
 				hasher.EXPECT().Hash(dto.Password).Return(validUser.Password, nil)
 				repository.EXPECT().Create(ctx, gomock.Any()).Return(user.User{}, errRepository)
 			},
@@ -139,6 +162,8 @@ func TestService_GetByEmail(t *testing.T) {
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
+	deletedUser := validUser
+	deletedUser.DeletedAt = &now
 
 	type args struct {
 		email string
@@ -161,6 +186,17 @@ func TestService_GetByEmail(t *testing.T) {
 			},
 			want: validUser,
 		},
+		{
+			name: "deleted user",
+			mock: func(repository *MockRepository) {
+				repository.EXPECT().GetByEmail(ctx, email).Return(deletedUser, nil)
+			},
+			args: args{
+				email: email,
+			},
+			want:    user.User{},
+			wantErr: true,
+		},
 		{
 			name: "repository error",
 			mock: func(repository *MockRepository) {
@@ -210,6 +246,11 @@ func TestService_Login(t *testing.T) {
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
+	deletedUser := validUser
+	deletedUser.DeletedAt = &now
+	expiredAt := now.Add(-31 * 24 * time.Hour)
+	expiredUser := validUser
+	expiredUser.DeletedAt = &expiredAt
 
 	type args struct {
 		email    string
@@ -235,6 +276,45 @@ func TestService_Login(t *testing.T) {
 			},
 			want: validUser,
 		},
+		{
+			name: "restore deleted user",
+			mock: func(repository *MockRepository, hasher *MockPasswordHasher) {
+				repository.EXPECT().GetByEmail(ctx, email).Return(deletedUser, nil)
+				hasher.EXPECT().Check(password, deletedUser.Password).Return(nil)
+				repository.EXPECT().Restore(ctx, deletedUser.ID).Return(nil)
+			},
+			args: args{
+				email:    email,
+				password: password,
+			},
+			want: validUser,
+		},
+		{
+			name: "restore error",
+			mock: func(repository *MockRepository, hasher *MockPasswordHasher) {
+				repository.EXPECT().GetByEmail(ctx, email).Return(deletedUser, nil)
+				hasher.EXPECT().Check(password, deletedUser.Password).Return(nil)
+				repository.EXPECT().Restore(ctx, deletedUser.ID).Return(errRepository)
+			},
+			args: args{
+				email:    email,
+				password: password,
+			},
+			want:    user.User{},
+			wantErr: true,
+		},
+		{
+			name: "grace period ended",
+			mock: func(repository *MockRepository, hasher *MockPasswordHasher) {
+				repository.EXPECT().GetByEmail(ctx, email).Return(expiredUser, nil)
+			},
+			args: args{
+				email:    email,
+				password: password,
+			},
+			want:    user.User{},
+			wantErr: true,
+		},
 		{
 			name: "hasher error",
 			mock: func(repository *MockRepository, hasher *MockPasswordHasher) {
@@ -305,6 +385,14 @@ func TestService_UpdateByEmail(t *testing.T) {
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
+	currentUser := validUser
+	currentUser.Username = faker.Username() + "old"
+	currentUser.Email = email
+	sameUsernameDTO := dto
+	sameUsernameDTO.Username = &currentUser.Username
+	caseOnlyUsername := strings.ToUpper(currentUser.Username)
+	caseOnlyDTO := dto
+	caseOnlyDTO.Username = &caseOnlyUsername
 
 	type args struct {
 		email string
@@ -319,9 +407,20 @@ func TestService_UpdateByEmail(t *testing.T) {
 		wantErr bool
 	}{
 		{
-			name: "success get user by email",
+			name: "success rename",
 			mock: func(repository *MockRepository) {
-				repository.EXPECT().UpdateByEmail(ctx, email, gomock.Any()).Return(validUser, nil)
+				repository.EXPECT().LockByEmail(ctx, email).Return(currentUser, nil)
+				repository.EXPECT().CountRenames(ctx, currentUser.ID, gomock.Any()).Return(2, nil)
+				repository.EXPECT().CheckUsernameQuarantine(ctx, dtoUsername, currentUser.ID, gomock.Any()).Return(nil)
+				repository.EXPECT().UpdateByEmail(ctx, email, gomock.Any()).DoAndReturn(
+					func(_ context.Context, _ string, dto user.UpdateDTO) (user.User, error) {
+						if !dto.UsernameCooldownUntil.After(dto.UpdatedAt) {
+							return user.User{}, errRepository
+						}
+
+						return validUser, nil
+					},
+				)
 			},
 			args: args{
 				email: email,
@@ -329,9 +428,90 @@ func TestService_UpdateByEmail(t *testing.T) {
 			},
 			want: validUser,
 		},
+		{
+			name: "same username is not a rename",
+			mock: func(repository *MockRepository) {
+				repository.EXPECT().LockByEmail(ctx, email).Return(currentUser, nil)
+				repository.EXPECT().UpdateByEmail(ctx, email, gomock.Any()).Return(currentUser, nil)
+			},
+			args: args{
+				email: email,
+				dto:   sameUsernameDTO,
+			},
+			want: currentUser,
+		},
+		{
+			name: "case only rename is not counted",
+			mock: func(repository *MockRepository) {
+				repository.EXPECT().LockByEmail(ctx, email).Return(currentUser, nil)
+				repository.EXPECT().UpdateByEmail(ctx, email, gomock.Any()).Return(currentUser, nil)
+			},
+			args: args{
+				email: email,
+				dto:   caseOnlyDTO,
+			},
+			want: currentUser,
+		},
+		{
+			name: "rename limit exceeded",
+			mock: func(repository *MockRepository) {
+				repository.EXPECT().LockByEmail(ctx, email).Return(currentUser, nil)
+				repository.EXPECT().CountRenames(ctx, currentUser.ID, gomock.Any()).Return(3, nil)
+			},
+			args: args{
+				email: email,
+				dto:   dto,
+			},
+			want:    user.User{},
+			wantErr: true,
+		},
+		{
+			name: "quarantined username",
+			mock: func(repository *MockRepository) {
+				repository.EXPECT().LockByEmail(ctx, email).Return(currentUser, nil)
+				repository.EXPECT().CountRenames(ctx, currentUser.ID, gomock.Any()).Return(0, nil)
+				repository.EXPECT().
+					CheckUsernameQuarantine(ctx, dtoUsername, currentUser.ID, gomock.Any()).
+					Return(user.ErrUsernameQuarantined)
+			},
+			args: args{
+				email: email,
+				dto:   dto,
+			},
+			want:    user.User{},
+			wantErr: true,
+		},
+		{
+			name: "count renames error",
+			mock: func(repository *MockRepository) {
+				repository.EXPECT().LockByEmail(ctx, email).Return(currentUser, nil)
+				repository.EXPECT().CountRenames(ctx, currentUser.ID, gomock.Any()).Return(0, errRepository)
+			},
+			args: args{
+				email: email,
+				dto:   dto,
+			},
+			want:    user.User{},
+			wantErr: true,
+		},
+		{
+			name: "lock user error",
+			mock: func(repository *MockRepository) {
+				repository.EXPECT().LockByEmail(ctx, email).Return(user.User{}, user.ErrNotFound)
+			},
+			args: args{
+				email: email,
+				dto:   dto,
+			},
+			want:    user.User{},
+			wantErr: true,
+		},
 		{
 			name: "repository error",
 			mock: func(repository *MockRepository) {
+				repository.EXPECT().LockByEmail(ctx, email).Return(currentUser, nil)
+				repository.EXPECT().CountRenames(ctx, currentUser.ID, gomock.Any()).Return(0, nil)
+				repository.EXPECT().CheckUsernameQuarantine(ctx, dtoUsername, currentUser.ID, gomock.Any()).Return(nil)
 				repository.EXPECT().UpdateByEmail(ctx, email, gomock.Any()).Return(user.User{}, errRepository)
 			},
 			args: args{
@@ -359,3 +539,111 @@ func TestService_UpdateByEmail(t *testing.T) {
 		})
 	}
 }
+
+func TestService_DeleteByEmail(t *testing.T) {
+	t.Parallel()
+
+	ctx := logger.ContextWithLogger(context.Background(), zap.L())
+	email := faker.Email()
+
+	type args struct {
+		email string
+	}
+
+	tests := []struct {
+		name    string
+		mock    func(*MockRepository)
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success delete user by email",
+			mock: func(repository *MockRepository) {
+				repository.EXPECT().DeleteByEmail(ctx, email, gomock.Any()).Return(nil)
+			},
+			args: args{
+				email: email,
+			},
+		},
+		{
+			name: "repository error",
+			mock: func(repository *MockRepository) {
+				repository.EXPECT().DeleteByEmail(ctx, email, gomock.Any()).Return(errRepository)
+			},
+			args: args{
+				email: email,
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			service, repository, _ := mockService(t)
+
+			tt.mock(repository)
+
+			got, err := service.DeleteByEmail(ctx, tt.args.email)
+			require.True(t, (err != nil) == tt.wantErr)
+			if !tt.wantErr {
+				require.Equal(t, 30*24*time.Hour, got.PurgeAt.Sub(got.DeletedAt))
+			}
+		})
+	}
+}
+
+func TestService_PurgeDeleted(t *testing.T) {
+	t.Parallel()
+
+	ctx := logger.ContextWithLogger(context.Background(), zap.L())
+
+	tests := []struct {
+		name    string
+		mock    func(*MockRepository)
+		want    int64
+		wantErr bool
+	}{
+		{
+			name: "success purge",
+			mock: func(repository *MockRepository) {
+				repository.EXPECT().Purge(ctx, gomock.Any()).DoAndReturn(
+					func(_ context.Context, dto user.PurgeDTO) (int64, error) {
+						require.Equal(t, user.DeletionModeAnonymize, dto.Mode)
+						require.True(t, dto.DeletedBefore.Before(dto.PurgedAt))
+						require.True(t, dto.QuarantinedUntil.After(dto.PurgedAt))
+
+						return 2, nil
+					},
+				)
+			},
+			want: 2,
+		},
+		{
+			name: "repository error",
+			mock: func(repository *MockRepository) {
+				repository.EXPECT().Purge(ctx, gomock.Any()).Return(int64(0), errRepository)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			service, repository, _ := mockService(t)
+
+			tt.mock(repository)
+
+			got, err := service.PurgeDeleted(ctx)
+			require.True(t, (err != nil) == tt.wantErr)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
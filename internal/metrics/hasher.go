@@ -0,0 +1,38 @@
+package metrics
+
+import "time"
+
+// PasswordHasher is a password hasher.
+type PasswordHasher interface {
+	Hash(plain string) (string, error)
+	Check(plain, hash string) error
+}
+
+type instrumentedHasher struct {
+	hasher  PasswordHasher
+	metrics *Metrics
+}
+
+// InstrumentPasswordHasher returns the hasher that records the duration of hashing and checking the passwords.
+func (m *Metrics) InstrumentPasswordHasher(hasher PasswordHasher) PasswordHasher {
+	return instrumentedHasher{
+		hasher:  hasher,
+		metrics: m,
+	}
+}
+
+func (h instrumentedHasher) Hash(plain string) (string, error) {
+	defer h.observe("hash", time.Now())
+
+	return h.hasher.Hash(plain) //nolint:wrapcheck
+}
+
+func (h instrumentedHasher) Check(plain, hash string) error {
+	defer h.observe("check", time.Now())
+
+	return h.hasher.Check(plain, hash) //nolint:wrapcheck
+}
+
+func (h instrumentedHasher) observe(operation string, startedAt time.Time) {
+	h.metrics.hashDuration.WithLabelValues(operation).Observe(time.Since(startedAt).Seconds())
+}
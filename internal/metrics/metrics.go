@@ -0,0 +1,123 @@
+// Package metrics provides the Prometheus metrics of the application.
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/maypok86/conduit/pkg/postgres"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "conduit"
+
+// Metrics holds the collectors of the application in its own registry, so only the metrics of the application
+// and the Go runtime are exposed.
+type Metrics struct {
+	registry        *prometheus.Registry
+	requests        *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+	queryDuration   *prometheus.HistogramVec
+	hashDuration    *prometheus.HistogramVec
+	tokenFailures   *prometheus.CounterVec
+}
+
+// New returns a new instance of Metrics.
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "Number of http requests by route template, method and status.",
+		}, []string{"route", "method", "status"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "Latency of http requests by route template, method and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"route", "method", "status"}),
+		queryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "postgres",
+			Name:      "query_duration_seconds",
+			Help:      "Latency of postgres queries by query name and result.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"query", "result"}),
+		hashDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "password",
+			Name:      "hash_duration_seconds",
+			Help:      "Duration of Argon2 password hashing by operation.",
+			// Argon2 is slow on purpose, the default buckets are too fine for it.
+			Buckets: []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"operation"}),
+		tokenFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "token",
+			Name:      "verification_failures_total",
+			Help:      "Number of rejected authorization tokens by error slug.",
+		}, []string{"slug"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests,
+		m.requestDuration,
+		m.queryDuration,
+		m.hashDuration,
+		m.tokenFailures,
+	)
+
+	return m
+}
+
+// Handler returns the handler that serves the metrics in the Prometheus exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// ObserveRequest records a handled http request. The route is the template of the route, not the requested path,
+// to keep the number of series bounded.
+func (m *Metrics) ObserveRequest(route, method, status string, duration time.Duration) {
+	m.requests.WithLabelValues(route, method, status).Inc()
+	m.requestDuration.WithLabelValues(route, method, status).Observe(duration.Seconds())
+}
+
+// ObserveTokenFailure records a rejected authorization token.
+func (m *Metrics) ObserveTokenFailure(slug string) {
+	m.tokenFailures.WithLabelValues(slug).Inc()
+}
+
+// RegisterPool registers the statistics of the postgres pool.
+func (m *Metrics) RegisterPool(pool StatPool) {
+	m.registry.MustRegister(newPoolCollector(pool))
+}
+
+// QueryHook returns the hook that records the latency of the postgres queries.
+func (m *Metrics) QueryHook() postgres.QueryHook {
+	return queryHook{duration: m.queryDuration}
+}
+
+type queryHook struct {
+	duration *prometheus.HistogramVec
+}
+
+func (h queryHook) BeforeQuery(ctx context.Context, _ *postgres.QueryEvent) context.Context {
+	return ctx
+}
+
+func (h queryHook) AfterQuery(_ context.Context, event *postgres.QueryEvent) {
+	result := "success"
+	if event.Err != nil {
+		result = "error"
+	}
+
+	h.duration.WithLabelValues(event.Name, result).Observe(event.Duration.Seconds())
+}
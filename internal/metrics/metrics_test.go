@@ -0,0 +1,64 @@
+package metrics_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/maypok86/conduit/internal/metrics"
+	"github.com/maypok86/conduit/pkg/hash"
+	"github.com/maypok86/conduit/pkg/postgres"
+	"github.com/stretchr/testify/require"
+)
+
+func scrape(t *testing.T, m *metrics.Metrics) string {
+	t.Helper()
+
+	recorder := httptest.NewRecorder()
+	m.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	body, err := io.ReadAll(recorder.Body)
+	require.NoError(t, err)
+
+	return string(body)
+}
+
+func TestMetrics(t *testing.T) {
+	t.Parallel()
+
+	m := metrics.New()
+
+	m.ObserveRequest("/api/profiles/:username", http.MethodGet, "200", 10*time.Millisecond)
+	m.ObserveRequest("/api/profiles/:username", http.MethodGet, "200", 20*time.Millisecond)
+	m.ObserveTokenFailure("unable-to-verify-jwt")
+
+	hasher := m.InstrumentPasswordHasher(hash.NewArgon2Hasher(hash.Memory(1024)))
+	hashed, err := hasher.Hash("password")
+	require.NoError(t, err)
+	require.NoError(t, hasher.Check("password", hashed))
+
+	hook := m.QueryHook()
+	ctx := hook.BeforeQuery(context.Background(), &postgres.QueryEvent{})
+	hook.AfterQuery(ctx, &postgres.QueryEvent{Name: "user.get", Duration: time.Millisecond})
+	hook.AfterQuery(ctx, &postgres.QueryEvent{Name: "user.get", Err: errors.New("timeout")})
+
+	body := scrape(t, m)
+
+	for _, want := range []string{
+		`conduit_http_requests_total{method="GET",route="/api/profiles/:username",status="200"} 2`,
+		`conduit_http_request_duration_seconds_count{method="GET",route="/api/profiles/:username",status="200"} 2`,
+		`conduit_token_verification_failures_total{slug="unable-to-verify-jwt"} 1`,
+		`conduit_password_hash_duration_seconds_count{operation="hash"} 1`,
+		`conduit_password_hash_duration_seconds_count{operation="check"} 1`,
+		`conduit_postgres_query_duration_seconds_count{query="user.get",result="success"} 1`,
+		`conduit_postgres_query_duration_seconds_count{query="user.get",result="error"} 1`,
+		`go_goroutines`,
+	} {
+		require.Contains(t, body, want)
+	}
+}
@@ -0,0 +1,77 @@
+package metrics
+
+import (
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// StatPool is a pool with statistics.
+type StatPool interface {
+	Stat() *pgxpool.Stat
+}
+
+// poolCollector reads the statistics of the pool on every scrape.
+type poolCollector struct {
+	pool StatPool
+
+	acquiredConns   *prometheus.Desc
+	idleConns       *prometheus.Desc
+	totalConns      *prometheus.Desc
+	maxConns        *prometheus.Desc
+	acquireCount    *prometheus.Desc
+	emptyAcquire    *prometheus.Desc
+	canceledAcquire *prometheus.Desc
+	acquireDuration *prometheus.Desc
+}
+
+func newPoolCollector(pool StatPool) *poolCollector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "postgres_pool", name), help, nil, nil)
+	}
+
+	return &poolCollector{
+		pool:            pool,
+		acquiredConns:   desc("acquired_connections", "Number of connections acquired from the pool."),
+		idleConns:       desc("idle_connections", "Number of idle connections in the pool."),
+		totalConns:      desc("total_connections", "Number of connections in the pool."),
+		maxConns:        desc("max_connections", "Maximum number of connections in the pool."),
+		acquireCount:    desc("acquires_total", "Number of successful acquires from the pool."),
+		emptyAcquire:    desc("empty_acquires_total", "Number of acquires that waited for a connection."),
+		canceledAcquire: desc("canceled_acquires_total", "Number of acquires canceled by the context."),
+		acquireDuration: desc("acquire_wait_seconds_total", "Total time spent acquiring connections."),
+	}
+}
+
+// Describe implements the prometheus.Collector interface.
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.acquiredConns
+	ch <- c.idleConns
+	ch <- c.totalConns
+	ch <- c.maxConns
+	ch <- c.acquireCount
+	ch <- c.emptyAcquire
+	ch <- c.canceledAcquire
+	ch <- c.acquireDuration
+}
+
+// Collect implements the prometheus.Collector interface.
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	stat := c.pool.Stat()
+
+	ch <- prometheus.MustNewConstMetric(c.acquiredConns, prometheus.GaugeValue, float64(stat.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(c.idleConns, prometheus.GaugeValue, float64(stat.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(c.totalConns, prometheus.GaugeValue, float64(stat.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(c.maxConns, prometheus.GaugeValue, float64(stat.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(c.acquireCount, prometheus.CounterValue, float64(stat.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.emptyAcquire, prometheus.CounterValue, float64(stat.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(
+		c.canceledAcquire,
+		prometheus.CounterValue,
+		float64(stat.CanceledAcquireCount()),
+	)
+	ch <- prometheus.MustNewConstMetric(
+		c.acquireDuration,
+		prometheus.CounterValue,
+		stat.AcquireDuration().Seconds(),
+	)
+}
@@ -0,0 +1,662 @@
+// Package contract provides the tests every implementation of the repositories must pass,
+// so the implementations are interchangeable for the services.
+package contract
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maypok86/conduit/internal/domain"
+	"github.com/maypok86/conduit/internal/domain/export"
+	"github.com/maypok86/conduit/internal/domain/profile"
+	"github.com/maypok86/conduit/internal/domain/user"
+	"github.com/stretchr/testify/require"
+)
+
+// Setup returns repositories without any data. The cases run one by one, so setup may reset a shared database.
+type Setup func(t *testing.T) domain.Repositories
+
+// RunUserRepository runs the user repository contract.
+func RunUserRepository(t *testing.T, setup Setup) {
+	t.Helper()
+
+	t.Run("create and get by email", func(t *testing.T) {
+		ctx := context.Background()
+		repositories := setup(t)
+
+		created, err := repositories.User.Create(ctx, user.User{
+			Username: "Alice",
+			Email:    "Alice@example.com",
+			Password: "hash",
+		})
+		require.NoError(t, err)
+		require.NotEqual(t, uuid.Nil, created.ID)
+
+		got, err := repositories.User.GetByEmail(ctx, "alice@EXAMPLE.com")
+		require.NoError(t, err)
+		require.Equal(t, created.ID, got.ID)
+		require.Equal(t, "Alice", got.Username)
+		require.Equal(t, "Alice@example.com", got.Email)
+		require.Equal(t, "hash", got.Password)
+		require.Nil(t, got.Bio)
+		require.Nil(t, got.Image)
+		require.False(t, got.Private)
+		require.False(t, got.IsDeleted())
+
+		_, err = repositories.User.GetByEmail(ctx, "bob@example.com")
+		require.ErrorIs(t, err, user.ErrNotFound)
+	})
+
+	t.Run("create with taken email", func(t *testing.T) {
+		ctx := context.Background()
+		repositories := setup(t)
+
+		createUser(t, repositories, "alice")
+
+		_, err := repositories.User.Create(ctx, user.User{
+			Username: "bob",
+			Email:    "ALICE@example.com",
+			Password: "hash",
+		})
+		require.ErrorIs(t, err, user.ErrEmailAlreadyExist)
+	})
+
+	t.Run("create with taken username", func(t *testing.T) {
+		ctx := context.Background()
+		repositories := setup(t)
+
+		createUser(t, repositories, "alice")
+
+		_, err := repositories.User.Create(ctx, user.User{
+			Username: "ALICE",
+			Email:    "bob@example.com",
+			Password: "hash",
+		})
+		require.ErrorIs(t, err, user.ErrUsernameAlreadyExist)
+	})
+
+	t.Run("update by email", func(t *testing.T) {
+		ctx := context.Background()
+		repositories := setup(t)
+
+		alice := createUser(t, repositories, "alice")
+		updatedAt := time.Date(2022, time.August, 1, 0, 0, 0, 0, time.UTC)
+
+		updated, err := repositories.User.UpdateByEmail(ctx, "ALICE@example.com", user.UpdateDTO{
+			Bio:       stringPtr("bio"),
+			Image:     stringPtr("https://example.com/alice.png"),
+			Private:   boolPtr(true),
+			UpdatedAt: updatedAt,
+		})
+		require.NoError(t, err)
+		require.Equal(t, alice.ID, updated.ID)
+		require.Equal(t, "alice", updated.Username)
+		require.Equal(t, "bio", updated.GetBio())
+		require.Equal(t, "https://example.com/alice.png", updated.GetImage())
+		require.True(t, updated.Private)
+		require.True(t, updatedAt.Equal(updated.UpdatedAt))
+
+		got, err := repositories.User.GetByEmail(ctx, "alice@example.com")
+		require.NoError(t, err)
+		require.Equal(t, "bio", got.GetBio())
+		require.True(t, got.Private)
+
+		_, err = repositories.User.UpdateByEmail(ctx, "bob@example.com", user.UpdateDTO{UpdatedAt: updatedAt})
+		require.ErrorIs(t, err, user.ErrNotFound)
+	})
+
+	t.Run("update to taken username", func(t *testing.T) {
+		ctx := context.Background()
+		repositories := setup(t)
+
+		createUser(t, repositories, "alice")
+		createUser(t, repositories, "bob")
+
+		_, err := repositories.User.UpdateByEmail(ctx, "bob@example.com", user.UpdateDTO{
+			Username:  stringPtr("Alice"),
+			UpdatedAt: time.Now().UTC(),
+		})
+		require.ErrorIs(t, err, user.ErrUsernameAlreadyExist)
+	})
+
+	t.Run("rename", func(t *testing.T) {
+		ctx := context.Background()
+		repositories := setup(t)
+
+		alice := createUser(t, repositories, "alice")
+		bob := createUser(t, repositories, "bob")
+		renamedAt := time.Now().UTC().Truncate(time.Second)
+
+		_, err := repositories.User.UpdateByEmail(ctx, "alice@example.com", user.UpdateDTO{
+			Username:              stringPtr("alicia"),
+			UpdatedAt:             renamedAt,
+			UsernameCooldownUntil: renamedAt.Add(time.Hour),
+		})
+		require.NoError(t, err)
+
+		count, err := repositories.User.CountRenames(ctx, alice.ID, renamedAt)
+		require.NoError(t, err)
+		require.Equal(t, 1, count)
+
+		count, err = repositories.User.CountRenames(ctx, alice.ID, renamedAt.Add(time.Second))
+		require.NoError(t, err)
+		require.Equal(t, 0, count)
+
+		err = repositories.User.CheckUsernameQuarantine(ctx, "ALICE", bob.ID, renamedAt)
+		require.ErrorIs(t, err, user.ErrUsernameQuarantined)
+		require.NoError(t, repositories.User.CheckUsernameQuarantine(ctx, "alice", alice.ID, renamedAt))
+		require.NoError(t, repositories.User.CheckUsernameQuarantine(ctx, "alice", bob.ID, renamedAt.Add(time.Hour)))
+
+		p, err := repositories.Profile.GetByPreviousUsername(ctx, "Alice")
+		require.NoError(t, err)
+		require.Equal(t, alice.ID, p.ID)
+		require.Equal(t, "alicia", p.Username)
+
+		_, err = repositories.Profile.GetByPreviousUsername(ctx, "bob")
+		require.ErrorIs(t, err, profile.ErrNotFound)
+	})
+
+	t.Run("case only rename", func(t *testing.T) {
+		ctx := context.Background()
+		repositories := setup(t)
+
+		alice := createUser(t, repositories, "alice")
+		bob := createUser(t, repositories, "bob")
+		renamedAt := time.Now().UTC().Truncate(time.Second)
+
+		updated, err := repositories.User.UpdateByEmail(ctx, "alice@example.com", user.UpdateDTO{
+			Username:              stringPtr("Alice"),
+			UpdatedAt:             renamedAt,
+			UsernameCooldownUntil: renamedAt.Add(time.Hour),
+		})
+		require.NoError(t, err)
+		require.Equal(t, "Alice", updated.Username)
+
+		count, err := repositories.User.CountRenames(ctx, alice.ID, renamedAt)
+		require.NoError(t, err)
+		require.Equal(t, 0, count)
+
+		require.NoError(t, repositories.User.CheckUsernameQuarantine(ctx, "alice", bob.ID, renamedAt))
+
+		_, err = repositories.Profile.GetByPreviousUsername(ctx, "alice")
+		require.ErrorIs(t, err, profile.ErrNotFound)
+	})
+
+	t.Run("lock by email", func(t *testing.T) {
+		ctx := context.Background()
+		repositories := setup(t)
+
+		alice := createUser(t, repositories, "alice")
+
+		got, err := repositories.User.LockByEmail(ctx, "ALICE@example.com")
+		require.NoError(t, err)
+		require.Equal(t, alice.ID, got.ID)
+
+		require.NoError(t, repositories.User.DeleteByEmail(ctx, "alice@example.com", time.Now().UTC()))
+
+		_, err = repositories.User.LockByEmail(ctx, "alice@example.com")
+		require.ErrorIs(t, err, user.ErrNotFound)
+	})
+
+	t.Run("delete and restore", func(t *testing.T) {
+		ctx := context.Background()
+		repositories := setup(t)
+
+		alice := createUser(t, repositories, "alice")
+		deletedAt := time.Now().UTC()
+
+		require.NoError(t, repositories.User.DeleteByEmail(ctx, "ALICE@example.com", deletedAt))
+
+		err := repositories.User.DeleteByEmail(ctx, "alice@example.com", deletedAt)
+		require.ErrorIs(t, err, user.ErrNotFound)
+
+		_, err = repositories.User.UpdateByEmail(ctx, "alice@example.com", user.UpdateDTO{UpdatedAt: deletedAt})
+		require.ErrorIs(t, err, user.ErrNotFound)
+
+		got, err := repositories.User.GetByEmail(ctx, "alice@example.com")
+		require.NoError(t, err)
+		require.True(t, got.IsDeleted())
+
+		_, err = repositories.Profile.GetByUsername(ctx, "alice")
+		require.ErrorIs(t, err, profile.ErrNotFound)
+
+		require.NoError(t, repositories.User.Restore(ctx, alice.ID))
+
+		_, err = repositories.Profile.GetByUsername(ctx, "alice")
+		require.NoError(t, err)
+
+		err = repositories.User.Restore(ctx, uuid.New())
+		require.ErrorIs(t, err, user.ErrNotFound)
+	})
+
+	for _, mode := range []user.DeletionMode{user.DeletionModeAnonymize, user.DeletionModeCascade} {
+		mode := mode
+
+		t.Run("purge in "+string(mode)+" mode", func(t *testing.T) {
+			ctx := context.Background()
+			repositories := setup(t)
+
+			alice := createUser(t, repositories, "alice")
+			bob := createUser(t, repositories, "bob")
+			createUser(t, repositories, "carol")
+			now := time.Now().UTC()
+
+			require.NoError(t, repositories.Profile.Follow(ctx, bob.ID, alice.ID))
+			require.NoError(t, repositories.User.DeleteByEmail(ctx, "alice@example.com", now.Add(-time.Hour)))
+			require.NoError(t, repositories.User.DeleteByEmail(ctx, "carol@example.com", now))
+
+			purged, err := repositories.User.Purge(ctx, user.PurgeDTO{
+				Mode:             mode,
+				DeletedBefore:    now.Add(-time.Minute),
+				QuarantinedUntil: now.Add(time.Hour),
+				PurgedAt:         now,
+			})
+			require.NoError(t, err)
+			require.Equal(t, int64(1), purged)
+
+			_, err = repositories.User.GetByEmail(ctx, "alice@example.com")
+			require.ErrorIs(t, err, user.ErrNotFound)
+
+			err = repositories.User.Restore(ctx, alice.ID)
+			require.ErrorIs(t, err, user.ErrNotFound)
+
+			err = repositories.User.CheckUsernameQuarantine(ctx, "alice", bob.ID, now)
+			require.ErrorIs(t, err, user.ErrUsernameQuarantined)
+
+			err = repositories.Profile.CheckFollowing(ctx, bob.ID, alice.ID)
+			require.ErrorIs(t, err, profile.ErrNotFound)
+
+			_, err = repositories.User.GetByEmail(ctx, "carol@example.com")
+			require.NoError(t, err)
+		})
+	}
+
+	t.Run("purge in unknown mode", func(t *testing.T) {
+		repositories := setup(t)
+
+		_, err := repositories.User.Purge(context.Background(), user.PurgeDTO{Mode: "unknown"})
+		require.ErrorIs(t, err, user.ErrUnknownDeletionMode)
+	})
+}
+
+// RunProfileRepository runs the profile repository contract.
+func RunProfileRepository(t *testing.T, setup Setup) {
+	t.Helper()
+
+	t.Run("get", func(t *testing.T) {
+		ctx := context.Background()
+		repositories := setup(t)
+
+		alice := createUser(t, repositories, "Alice")
+		bob := createUser(t, repositories, "bob")
+		carol := createUser(t, repositories, "carol")
+
+		require.NoError(t, repositories.Profile.Follow(ctx, alice.ID, bob.ID))
+		require.NoError(t, repositories.Profile.Follow(ctx, alice.ID, carol.ID))
+		require.NoError(t, repositories.Profile.Follow(ctx, carol.ID, alice.ID))
+		require.NoError(t, repositories.User.DeleteByEmail(ctx, "carol@example.com", time.Now().UTC()))
+
+		got, err := repositories.Profile.GetByUsername(ctx, "ALICE")
+		require.NoError(t, err)
+		require.Equal(t, alice.ID, got.ID)
+		require.Equal(t, "Alice", got.Username)
+		require.Equal(t, 1, got.FollowersCount)
+		require.Equal(t, 0, got.FollowingCount)
+
+		got, err = repositories.Profile.GetByUsername(ctx, "bob")
+		require.NoError(t, err)
+		require.Equal(t, 0, got.FollowersCount)
+		require.Equal(t, 1, got.FollowingCount)
+
+		got, err = repositories.Profile.GetByEmail(ctx, "BOB@example.com")
+		require.NoError(t, err)
+		require.Equal(t, bob.ID, got.ID)
+
+		_, err = repositories.Profile.GetByUsername(ctx, "carol")
+		require.ErrorIs(t, err, profile.ErrNotFound)
+
+		_, err = repositories.Profile.GetByEmail(ctx, "carol@example.com")
+		require.ErrorIs(t, err, profile.ErrNotFound)
+	})
+
+	t.Run("follow and unfollow", func(t *testing.T) {
+		ctx := context.Background()
+		repositories := setup(t)
+
+		alice := createUser(t, repositories, "alice")
+		bob := createUser(t, repositories, "bob")
+
+		err := repositories.Profile.CheckFollowing(ctx, alice.ID, bob.ID)
+		require.ErrorIs(t, err, profile.ErrNotFound)
+
+		require.NoError(t, repositories.Profile.Follow(ctx, alice.ID, bob.ID))
+		require.NoError(t, repositories.Profile.Follow(ctx, alice.ID, bob.ID))
+		require.NoError(t, repositories.Profile.CheckFollowing(ctx, alice.ID, bob.ID))
+
+		err = repositories.Profile.CheckFollowing(ctx, bob.ID, alice.ID)
+		require.ErrorIs(t, err, profile.ErrNotFound)
+
+		require.NoError(t, repositories.Profile.Unfollow(ctx, alice.ID, bob.ID))
+
+		err = repositories.Profile.Unfollow(ctx, alice.ID, bob.ID)
+		require.ErrorIs(t, err, profile.ErrNotFollowing)
+
+		err = repositories.Profile.Follow(ctx, alice.ID, alice.ID)
+		require.ErrorIs(t, err, profile.ErrCannotFollowSelf)
+	})
+
+	t.Run("list follows", func(t *testing.T) {
+		ctx := context.Background()
+		repositories := setup(t)
+
+		alice := createUser(t, repositories, "alice")
+		bob := createUser(t, repositories, "bob")
+		carol := createUser(t, repositories, "carol")
+		dave := createUser(t, repositories, "dave")
+
+		require.NoError(t, repositories.Profile.Follow(ctx, alice.ID, bob.ID))
+		require.NoError(t, repositories.Profile.Follow(ctx, alice.ID, carol.ID))
+		require.NoError(t, repositories.Profile.Follow(ctx, alice.ID, dave.ID))
+		require.NoError(t, repositories.Profile.Follow(ctx, carol.ID, bob.ID))
+		require.NoError(t, repositories.Profile.Mute(ctx, bob.ID, carol.ID))
+		require.NoError(t, repositories.Profile.Block(ctx, bob.ID, dave.ID))
+
+		followers, err := repositories.Profile.ListFollowers(ctx, profile.ListFollowsDTO{
+			ProfileID: alice.ID,
+			ViewerID:  bob.ID,
+			Limit:     10,
+		})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"bob", "carol", "dave"}, usernames(followers))
+
+		for _, follow := range followers {
+			require.Equal(t, follow.Profile.ID == carol.ID, follow.Profile.Following)
+			require.Equal(t, follow.Profile.ID == carol.ID, follow.Profile.Muting)
+			require.Equal(t, follow.Profile.ID == dave.ID, follow.Profile.Blocking)
+		}
+
+		followers, err = repositories.Profile.ListFollowers(ctx, profile.ListFollowsDTO{
+			ProfileID: alice.ID,
+			ViewerID:  dave.ID,
+			Limit:     10,
+		})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"carol", "dave"}, usernames(followers))
+
+		followers, err = repositories.Profile.ListFollowers(ctx, profile.ListFollowsDTO{
+			ProfileID: alice.ID,
+			ViewerID:  bob.ID,
+			Limit:     1,
+		})
+		require.NoError(t, err)
+		require.Len(t, followers, 1)
+
+		following, err := repositories.Profile.ListFollowing(ctx, profile.ListFollowsDTO{
+			ProfileID: bob.ID,
+			Limit:     10,
+		})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"alice", "carol"}, usernames(following))
+	})
+
+	t.Run("block", func(t *testing.T) {
+		ctx := context.Background()
+		repositories := setup(t)
+
+		alice := createUser(t, repositories, "alice")
+		bob := createUser(t, repositories, "bob")
+
+		require.NoError(t, repositories.Profile.Follow(ctx, alice.ID, bob.ID))
+		require.NoError(t, repositories.Profile.Follow(ctx, bob.ID, alice.ID))
+		require.NoError(t, repositories.Profile.RequestFollow(ctx, bob.ID, alice.ID))
+		require.NoError(t, repositories.Profile.Block(ctx, alice.ID, bob.ID))
+		require.NoError(t, repositories.Profile.Block(ctx, alice.ID, bob.ID))
+
+		err := repositories.Profile.CheckFollowing(ctx, alice.ID, bob.ID)
+		require.ErrorIs(t, err, profile.ErrNotFound)
+
+		err = repositories.Profile.CheckFollowing(ctx, bob.ID, alice.ID)
+		require.ErrorIs(t, err, profile.ErrNotFound)
+
+		restrictions, err := repositories.Profile.GetRestrictions(ctx, bob.ID, alice.ID)
+		require.NoError(t, err)
+		require.Equal(t, profile.Restrictions{Blocking: true}, restrictions)
+
+		restrictions, err = repositories.Profile.GetRestrictions(ctx, alice.ID, bob.ID)
+		require.NoError(t, err)
+		require.Equal(t, profile.Restrictions{BlockedBy: true}, restrictions)
+
+		require.NoError(t, repositories.Profile.Unblock(ctx, alice.ID, bob.ID))
+
+		err = repositories.Profile.Unblock(ctx, alice.ID, bob.ID)
+		require.ErrorIs(t, err, profile.ErrNotBlocking)
+
+		err = repositories.Profile.Block(ctx, alice.ID, alice.ID)
+		require.ErrorIs(t, err, profile.ErrCannotBlockSelf)
+	})
+
+	t.Run("follows hidden from blocked users", func(t *testing.T) {
+		ctx := context.Background()
+		repositories := setup(t)
+		service := profile.NewService(repositories.Profile, nil)
+
+		alice := createUser(t, repositories, "alice")
+		bob := createUser(t, repositories, "bob")
+		carol := createUser(t, repositories, "carol")
+
+		require.NoError(t, repositories.Profile.Follow(ctx, alice.ID, carol.ID))
+		require.NoError(t, repositories.Profile.Block(ctx, alice.ID, bob.ID))
+
+		_, err := service.ListFollowers(ctx, profile.ListDTO{Username: "alice", ViewerEmail: bob.Email})
+		require.ErrorIs(t, err, profile.ErrNotFound)
+
+		_, err = service.ListFollowing(ctx, profile.ListDTO{Username: "alice", ViewerEmail: bob.Email})
+		require.ErrorIs(t, err, profile.ErrNotFound)
+
+		page, err := service.ListFollowers(ctx, profile.ListDTO{Username: "alice", ViewerEmail: carol.Email})
+		require.NoError(t, err)
+		require.Len(t, page.Profiles, 1)
+		require.Equal(t, "carol", page.Profiles[0].Username)
+	})
+
+	t.Run("follow blocked", func(t *testing.T) {
+		ctx := context.Background()
+		repositories := setup(t)
+
+		alice := createUser(t, repositories, "alice")
+		bob := createUser(t, repositories, "bob")
+
+		require.NoError(t, repositories.Profile.Block(ctx, alice.ID, bob.ID))
+
+		err := repositories.Profile.Follow(ctx, alice.ID, bob.ID)
+		require.ErrorIs(t, err, profile.ErrFollowBlocked)
+
+		err = repositories.Profile.Follow(ctx, bob.ID, alice.ID)
+		require.ErrorIs(t, err, profile.ErrFollowBlocked)
+
+		err = repositories.Profile.RequestFollow(ctx, alice.ID, bob.ID)
+		require.ErrorIs(t, err, profile.ErrFollowBlocked)
+
+		err = repositories.Profile.CheckFollowing(ctx, alice.ID, bob.ID)
+		require.ErrorIs(t, err, profile.ErrNotFound)
+
+		restrictions, err := repositories.Profile.GetRestrictions(ctx, alice.ID, bob.ID)
+		require.NoError(t, err)
+		require.False(t, restrictions.Requested)
+	})
+
+	t.Run("mute", func(t *testing.T) {
+		ctx := context.Background()
+		repositories := setup(t)
+
+		alice := createUser(t, repositories, "alice")
+		bob := createUser(t, repositories, "bob")
+
+		require.NoError(t, repositories.Profile.Mute(ctx, alice.ID, bob.ID))
+		require.NoError(t, repositories.Profile.Mute(ctx, alice.ID, bob.ID))
+
+		restrictions, err := repositories.Profile.GetRestrictions(ctx, bob.ID, alice.ID)
+		require.NoError(t, err)
+		require.Equal(t, profile.Restrictions{Muting: true}, restrictions)
+
+		require.NoError(t, repositories.Profile.Unmute(ctx, alice.ID, bob.ID))
+
+		err = repositories.Profile.Unmute(ctx, alice.ID, bob.ID)
+		require.ErrorIs(t, err, profile.ErrNotMuting)
+
+		err = repositories.Profile.Mute(ctx, alice.ID, alice.ID)
+		require.ErrorIs(t, err, profile.ErrCannotMuteSelf)
+	})
+
+	t.Run("follow requests", func(t *testing.T) {
+		ctx := context.Background()
+		repositories := setup(t)
+
+		alice := createUser(t, repositories, "alice")
+		bob := createUser(t, repositories, "bob")
+		carol := createUser(t, repositories, "carol")
+
+		require.NoError(t, repositories.Profile.RequestFollow(ctx, alice.ID, bob.ID))
+		require.NoError(t, repositories.Profile.RequestFollow(ctx, alice.ID, bob.ID))
+		require.NoError(t, repositories.Profile.RequestFollow(ctx, alice.ID, carol.ID))
+
+		restrictions, err := repositories.Profile.GetRestrictions(ctx, alice.ID, bob.ID)
+		require.NoError(t, err)
+		require.Equal(t, profile.Restrictions{Requested: true}, restrictions)
+
+		requests, err := repositories.Profile.ListFollowRequests(ctx, profile.ListFollowsDTO{
+			ProfileID: alice.ID,
+			ViewerID:  alice.ID,
+			Limit:     10,
+		})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"bob", "carol"}, usernames(requests))
+
+		require.NoError(t, repositories.Profile.ApproveFollowRequest(ctx, alice.ID, bob.ID))
+		require.NoError(t, repositories.Profile.CheckFollowing(ctx, alice.ID, bob.ID))
+
+		err = repositories.Profile.ApproveFollowRequest(ctx, alice.ID, bob.ID)
+		require.ErrorIs(t, err, profile.ErrFollowRequestNotFound)
+
+		require.NoError(t, repositories.Profile.DeleteFollowRequest(ctx, alice.ID, carol.ID))
+
+		err = repositories.Profile.DeleteFollowRequest(ctx, alice.ID, carol.ID)
+		require.ErrorIs(t, err, profile.ErrFollowRequestNotFound)
+
+		requests, err = repositories.Profile.ListFollowRequests(ctx, profile.ListFollowsDTO{
+			ProfileID: alice.ID,
+			ViewerID:  alice.ID,
+			Limit:     10,
+		})
+		require.NoError(t, err)
+		require.Empty(t, requests)
+
+		err = repositories.Profile.RequestFollow(ctx, alice.ID, alice.ID)
+		require.ErrorIs(t, err, profile.ErrCannotFollowSelf)
+	})
+
+	t.Run("going public approves follow requests", func(t *testing.T) {
+		ctx := context.Background()
+		repositories := setup(t)
+
+		alice := createUser(t, repositories, "alice")
+		bob := createUser(t, repositories, "bob")
+
+		_, err := repositories.User.UpdateByEmail(ctx, alice.Email, user.UpdateDTO{
+			Private:   boolPtr(true),
+			UpdatedAt: time.Now(),
+		})
+		require.NoError(t, err)
+		require.NoError(t, repositories.Profile.RequestFollow(ctx, alice.ID, bob.ID))
+
+		_, err = repositories.User.UpdateByEmail(ctx, alice.Email, user.UpdateDTO{
+			Private:   boolPtr(false),
+			UpdatedAt: time.Now(),
+		})
+		require.NoError(t, err)
+		require.NoError(t, repositories.Profile.CheckFollowing(ctx, alice.ID, bob.ID))
+
+		requests, err := repositories.Profile.ListFollowRequests(ctx, profile.ListFollowsDTO{
+			ProfileID: alice.ID,
+			ViewerID:  alice.ID,
+			Limit:     10,
+		})
+		require.NoError(t, err)
+		require.Empty(t, requests)
+	})
+}
+
+// RunExportRepository runs the export repository contract.
+func RunExportRepository(t *testing.T, setup Setup) {
+	t.Helper()
+
+	t.Run("jobs", func(t *testing.T) {
+		ctx := context.Background()
+		repositories := setup(t)
+
+		alice := createUser(t, repositories, "alice")
+		bob := createUser(t, repositories, "bob")
+
+		_, err := repositories.Export.GetLastJob(ctx, alice.ID)
+		require.ErrorIs(t, err, export.ErrNotFound)
+
+		previous, err := repositories.Export.CreateJob(ctx, alice.ID)
+		require.NoError(t, err)
+		require.NoError(t, repositories.Export.CompleteJob(ctx, previous.ID, []byte("archive")))
+
+		bobJob, err := repositories.Export.CreateJob(ctx, bob.ID)
+		require.NoError(t, err)
+
+		got, err := repositories.Export.GetLastJob(ctx, alice.ID)
+		require.NoError(t, err)
+		require.Equal(t, export.StatusReady, got.Status)
+		require.Equal(t, []byte("archive"), got.Archive)
+
+		// The new job replaces the previous one with its archive.
+		job, err := repositories.Export.CreateJob(ctx, alice.ID)
+		require.NoError(t, err)
+		require.NoError(t, repositories.Export.CompleteJob(ctx, previous.ID, []byte("stale")))
+
+		got, err = repositories.Export.GetLastJob(ctx, alice.ID)
+		require.NoError(t, err)
+		require.Equal(t, job.ID, got.ID)
+		require.Equal(t, export.StatusPending, got.Status)
+		require.Empty(t, got.Archive)
+
+		got, err = repositories.Export.GetLastJob(ctx, bob.ID)
+		require.NoError(t, err)
+		require.Equal(t, bobJob.ID, got.ID)
+	})
+}
+
+func createUser(t *testing.T, repositories domain.Repositories, username string) user.User {
+	t.Helper()
+
+	u, err := repositories.User.Create(context.Background(), user.User{
+		Username: username,
+		Email:    username + "@example.com",
+		Password: "hash",
+	})
+	require.NoError(t, err)
+
+	return u
+}
+
+func usernames(follows []profile.Follow) []string {
+	usernames := make([]string, 0, len(follows))
+	for _, follow := range follows {
+		usernames = append(usernames, follow.Profile.Username)
+	}
+
+	return usernames
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
@@ -0,0 +1,154 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/maypok86/conduit/internal/domain/export"
+)
+
+// ExportRepository is a user data export repository.
+type ExportRepository struct {
+	store *Store
+}
+
+// NewExportRepository creates a new ExportRepository.
+func NewExportRepository(store *Store) ExportRepository {
+	return ExportRepository{
+		store: store,
+	}
+}
+
+// CountFollows returns the number of follow relationships in both directions.
+func (er ExportRepository) CountFollows(ctx context.Context, userID uuid.UUID) (int, error) {
+	er.store.mu.RLock()
+	defer er.store.mu.RUnlock()
+
+	var count int
+
+	for follow := range er.store.data.follows {
+		if follow.from == userID || follow.to == userID {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// GetFollowing returns users followed by the user.
+func (er ExportRepository) GetFollowing(ctx context.Context, userID uuid.UUID) ([]export.Follow, error) {
+	er.store.mu.RLock()
+	defer er.store.mu.RUnlock()
+
+	return er.store.data.exportFollows(func(follow pair) (uuid.UUID, bool) {
+		return follow.from, follow.to == userID
+	}), nil
+}
+
+// GetFollowers returns users following the user.
+func (er ExportRepository) GetFollowers(ctx context.Context, userID uuid.UUID) ([]export.Follow, error) {
+	er.store.mu.RLock()
+	defer er.store.mu.RUnlock()
+
+	return er.store.data.exportFollows(func(follow pair) (uuid.UUID, bool) {
+		return follow.to, follow.from == userID
+	}), nil
+}
+
+// exportFollows returns the follows accepted by match from the oldest to the newest.
+// match returns the id of the exported user.
+func (d data) exportFollows(match func(follow pair) (uuid.UUID, bool)) []export.Follow {
+	follows := make([]export.Follow, 0)
+
+	for follow, createdAt := range d.follows {
+		id, ok := match(follow)
+		if !ok {
+			continue
+		}
+
+		follows = append(follows, export.Follow{Username: d.users[id].Username, CreatedAt: createdAt})
+	}
+
+	sort.Slice(follows, func(i, j int) bool {
+		return follows[i].CreatedAt.Before(follows[j].CreatedAt)
+	})
+
+	return follows
+}
+
+// CreateJob creates a pending export job. It replaces the previous jobs of the user, so a user keeps
+// a single archive.
+func (er ExportRepository) CreateJob(ctx context.Context, userID uuid.UUID) (export.Job, error) {
+	defer er.store.lock(ctx)()
+
+	createdAt := now()
+	job := export.Job{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Status:    export.StatusPending,
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	}
+
+	exports := make([]export.Job, 0, len(er.store.data.exports)+1)
+	for _, previous := range er.store.data.exports {
+		if previous.UserID != userID {
+			exports = append(exports, previous)
+		}
+	}
+
+	er.store.data.exports = append(exports, job)
+
+	return job, nil
+}
+
+// GetLastJob returns the most recent export job of the user.
+func (er ExportRepository) GetLastJob(ctx context.Context, userID uuid.UUID) (export.Job, error) {
+	er.store.mu.RLock()
+	defer er.store.mu.RUnlock()
+
+	// Jobs are appended in the creation order.
+	exports := er.store.data.exports
+	for i := len(exports) - 1; i >= 0; i-- {
+		if exports[i].UserID == userID {
+			return exports[i], nil
+		}
+	}
+
+	return export.Job{}, fmt.Errorf("can not find last export job: %w", export.ErrNotFound)
+}
+
+// CompleteJob stores the archive of the export job.
+func (er ExportRepository) CompleteJob(ctx context.Context, id uuid.UUID, archive []byte) error {
+	er.updateJob(ctx, id, func(job *export.Job) {
+		job.Status = export.StatusReady
+		job.Archive = archive
+	})
+
+	return nil
+}
+
+// FailJob marks the export job as failed.
+func (er ExportRepository) FailJob(ctx context.Context, id uuid.UUID, reason string) error {
+	er.updateJob(ctx, id, func(job *export.Job) {
+		job.Status = export.StatusFailed
+	})
+
+	return nil
+}
+
+func (er ExportRepository) updateJob(ctx context.Context, id uuid.UUID, update func(job *export.Job)) {
+	defer er.store.lock(ctx)()
+
+	for i := range er.store.data.exports {
+		job := &er.store.data.exports[i]
+		if job.ID == id {
+			update(job)
+			job.UpdatedAt = now()
+
+			return
+		}
+	}
+}
@@ -0,0 +1,385 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maypok86/conduit/internal/domain/profile"
+)
+
+// ProfileRepository is a profile repository.
+type ProfileRepository struct {
+	store *Store
+}
+
+// NewProfileRepository creates a new ProfileRepository.
+func NewProfileRepository(store *Store) ProfileRepository {
+	return ProfileRepository{
+		store: store,
+	}
+}
+
+func newProfile(u userRecord) profile.Profile {
+	return profile.Profile{
+		ID:        u.ID,
+		Username:  u.Username,
+		Bio:       cloneString(u.Bio),
+		Image:     cloneString(u.Image),
+		Private:   u.Private,
+		CreatedAt: u.CreatedAt,
+		UpdatedAt: u.UpdatedAt,
+	}
+}
+
+// withCounts sets the numbers of followers and followed users, deleted users are not counted.
+func (d data) withCounts(p profile.Profile) profile.Profile {
+	for follow := range d.follows {
+		if follow.from == p.ID && d.isActive(follow.to) {
+			p.FollowersCount++
+		}
+
+		if follow.to == p.ID && d.isActive(follow.from) {
+			p.FollowingCount++
+		}
+	}
+
+	return p
+}
+
+// GetByUsername returns profile by username. Usernames are compared case-insensitively.
+func (pr ProfileRepository) GetByUsername(ctx context.Context, username string) (profile.Profile, error) {
+	pr.store.mu.RLock()
+	defer pr.store.mu.RUnlock()
+
+	u, ok := pr.store.data.findUser(func(u userRecord) bool {
+		return equalFold(u.Username, username) && u.DeletedAt == nil
+	})
+	if !ok {
+		return profile.Profile{}, fmt.Errorf("can not find profile by username: %w", profile.ErrNotFound)
+	}
+
+	return pr.store.data.withCounts(newProfile(u)), nil
+}
+
+// GetByPreviousUsername returns profile of the user who used the username most recently before renaming.
+func (pr ProfileRepository) GetByPreviousUsername(ctx context.Context, username string) (profile.Profile, error) {
+	pr.store.mu.RLock()
+	defer pr.store.mu.RUnlock()
+
+	d := pr.store.data
+
+	var (
+		latest rename
+		found  bool
+	)
+
+	for _, r := range d.renames {
+		if !equalFold(r.username, username) || !d.isActive(r.userID) {
+			continue
+		}
+
+		if !found || r.renamedAt.After(latest.renamedAt) {
+			latest, found = r, true
+		}
+	}
+
+	if !found {
+		return profile.Profile{}, fmt.Errorf("can not find profile by previous username: %w", profile.ErrNotFound)
+	}
+
+	return d.withCounts(newProfile(d.users[latest.userID])), nil
+}
+
+// GetByEmail returns profile by email.
+func (pr ProfileRepository) GetByEmail(ctx context.Context, email string) (profile.Profile, error) {
+	pr.store.mu.RLock()
+	defer pr.store.mu.RUnlock()
+
+	u, ok := pr.store.data.findUser(func(u userRecord) bool {
+		return equalFold(u.Email, email) && u.DeletedAt == nil
+	})
+	if !ok {
+		return profile.Profile{}, fmt.Errorf("can not find profile by email: %w", profile.ErrNotFound)
+	}
+
+	return newProfile(u), nil
+}
+
+// CheckFollowing checks if user is following another user.
+func (pr ProfileRepository) CheckFollowing(ctx context.Context, followeeID, followerID uuid.UUID) error {
+	pr.store.mu.RLock()
+	defer pr.store.mu.RUnlock()
+
+	if _, ok := pr.store.data.follows[pair{from: followeeID, to: followerID}]; !ok {
+		return fmt.Errorf("can not check following: %w", profile.ErrNotFound)
+	}
+
+	return nil
+}
+
+// Follow adds follow relationship. Following the same profile again is a no-op.
+// It returns profile.ErrFollowBlocked if one of the users blocks the other one.
+func (pr ProfileRepository) Follow(ctx context.Context, followeeID, followerID uuid.UUID) error {
+	if followeeID == followerID {
+		return fmt.Errorf("can not follow: %w", profile.ErrCannotFollowSelf)
+	}
+
+	defer pr.store.lock(ctx)()
+
+	if err := pr.store.data.insertUnlessBlocked(pr.store.data.follows, followeeID, followerID); err != nil {
+		return fmt.Errorf("can not follow: %w", err)
+	}
+
+	return nil
+}
+
+// insertUnlessBlocked adds the follow or the follow request unless one of the users blocks the other one.
+// It returns profile.ErrFollowBlocked then, an existing relationship is kept as is.
+func (d data) insertUnlessBlocked(relationships map[pair]time.Time, followeeID, followerID uuid.UUID) error {
+	key := pair{from: followeeID, to: followerID}
+	if _, ok := relationships[key]; ok {
+		return nil
+	}
+
+	_, blocking := d.blocks[pair{from: followeeID, to: followerID}]
+	_, blockedBy := d.blocks[pair{from: followerID, to: followeeID}]
+
+	if blocking || blockedBy {
+		return profile.ErrFollowBlocked
+	}
+
+	insert(relationships, key)
+
+	return nil
+}
+
+// insert adds the relationship unless it already exists, so its creation time is kept.
+func insert(relationships map[pair]time.Time, key pair) {
+	if _, ok := relationships[key]; !ok {
+		relationships[key] = now()
+	}
+}
+
+// Unfollow removes follow relationship. It returns profile.ErrNotFollowing if there is no such relationship.
+func (pr ProfileRepository) Unfollow(ctx context.Context, followeeID, followerID uuid.UUID) error {
+	defer pr.store.lock(ctx)()
+
+	if !remove(pr.store.data.follows, pair{from: followeeID, to: followerID}) {
+		return fmt.Errorf("can not unfollow: %w", profile.ErrNotFollowing)
+	}
+
+	return nil
+}
+
+// remove deletes the relationship and reports whether it existed.
+func remove(relationships map[pair]time.Time, key pair) bool {
+	if _, ok := relationships[key]; !ok {
+		return false
+	}
+
+	delete(relationships, key)
+
+	return true
+}
+
+// ListFollowers returns users following the profile, from the newest follow to the oldest.
+func (pr ProfileRepository) ListFollowers(ctx context.Context, dto profile.ListFollowsDTO) ([]profile.Follow, error) {
+	pr.store.mu.RLock()
+	defer pr.store.mu.RUnlock()
+
+	return pr.store.data.listFollows(pr.store.data.follows, dto), nil
+}
+
+// ListFollowing returns users followed by the profile, from the newest follow to the oldest.
+func (pr ProfileRepository) ListFollowing(ctx context.Context, dto profile.ListFollowsDTO) ([]profile.Follow, error) {
+	pr.store.mu.RLock()
+	defer pr.store.mu.RUnlock()
+
+	following := make(map[pair]time.Time)
+	for follow, createdAt := range pr.store.data.follows {
+		following[pair{from: follow.to, to: follow.from}] = createdAt
+	}
+
+	return pr.store.data.listFollows(following, dto), nil
+}
+
+// listFollows lists the users that relationships of the profile point to. The relationships are keyed by
+// the profile and the listed user. Users blocking the viewer are skipped.
+func (d data) listFollows(relationships map[pair]time.Time, dto profile.ListFollowsDTO) []profile.Follow {
+	follows := make([]profile.Follow, 0)
+
+	for key, createdAt := range relationships {
+		if key.from != dto.ProfileID || !d.isActive(key.to) {
+			continue
+		}
+
+		if _, ok := d.blocks[pair{from: key.to, to: dto.ViewerID}]; ok {
+			continue
+		}
+
+		if dto.After != nil && !isBefore(createdAt, key.to, dto.After.FollowedAt, dto.After.ID) {
+			continue
+		}
+
+		p := newProfile(d.users[key.to])
+		_, p.Following = d.follows[pair{from: p.ID, to: dto.ViewerID}]
+		_, p.Blocking = d.blocks[pair{from: dto.ViewerID, to: p.ID}]
+		_, p.Muting = d.mutes[pair{from: dto.ViewerID, to: p.ID}]
+		_, p.Requested = d.followRequests[pair{from: p.ID, to: dto.ViewerID}]
+
+		follows = append(follows, profile.Follow{Profile: p, FollowedAt: createdAt})
+	}
+
+	sort.Slice(follows, func(i, j int) bool {
+		return isBefore(follows[j].FollowedAt, follows[j].Profile.ID, follows[i].FollowedAt, follows[i].Profile.ID)
+	})
+
+	if dto.Limit >= 0 && len(follows) > dto.Limit {
+		follows = follows[:dto.Limit]
+	}
+
+	return follows
+}
+
+// isBefore compares (createdAt, id) rows like postgres does.
+func isBefore(createdAt time.Time, id uuid.UUID, otherCreatedAt time.Time, otherID uuid.UUID) bool {
+	if !createdAt.Equal(otherCreatedAt) {
+		return createdAt.Before(otherCreatedAt)
+	}
+
+	return bytes.Compare(id[:], otherID[:]) < 0
+}
+
+// GetRestrictions returns blocks, mutes and the pending follow request between the viewer and the profile.
+func (pr ProfileRepository) GetRestrictions(
+	ctx context.Context,
+	profileID uuid.UUID,
+	viewerID uuid.UUID,
+) (profile.Restrictions, error) {
+	pr.store.mu.RLock()
+	defer pr.store.mu.RUnlock()
+
+	d := pr.store.data
+
+	var restrictions profile.Restrictions
+
+	_, restrictions.Blocking = d.blocks[pair{from: viewerID, to: profileID}]
+	_, restrictions.BlockedBy = d.blocks[pair{from: profileID, to: viewerID}]
+	_, restrictions.Muting = d.mutes[pair{from: viewerID, to: profileID}]
+	_, restrictions.Requested = d.followRequests[pair{from: profileID, to: viewerID}]
+
+	return restrictions, nil
+}
+
+// Block blocks the profile and removes follows between the users. Blocking the same profile again is a no-op.
+func (pr ProfileRepository) Block(ctx context.Context, blockerID, blockedID uuid.UUID) error {
+	if blockerID == blockedID {
+		return fmt.Errorf("can not block: %w", profile.ErrCannotBlockSelf)
+	}
+
+	defer pr.store.lock(ctx)()
+
+	d := pr.store.data
+
+	for _, key := range []pair{{from: blockerID, to: blockedID}, {from: blockedID, to: blockerID}} {
+		delete(d.follows, key)
+		delete(d.followRequests, key)
+	}
+
+	insert(d.blocks, pair{from: blockerID, to: blockedID})
+
+	return nil
+}
+
+// Unblock removes the block. It returns profile.ErrNotBlocking if there is no such block.
+func (pr ProfileRepository) Unblock(ctx context.Context, blockerID, blockedID uuid.UUID) error {
+	defer pr.store.lock(ctx)()
+
+	if !remove(pr.store.data.blocks, pair{from: blockerID, to: blockedID}) {
+		return fmt.Errorf("can not unblock: %w", profile.ErrNotBlocking)
+	}
+
+	return nil
+}
+
+// Mute mutes the profile. Muting the same profile again is a no-op.
+func (pr ProfileRepository) Mute(ctx context.Context, muterID, mutedID uuid.UUID) error {
+	if muterID == mutedID {
+		return fmt.Errorf("can not mute: %w", profile.ErrCannotMuteSelf)
+	}
+
+	defer pr.store.lock(ctx)()
+
+	insert(pr.store.data.mutes, pair{from: muterID, to: mutedID})
+
+	return nil
+}
+
+// Unmute removes the mute. It returns profile.ErrNotMuting if there is no such mute.
+func (pr ProfileRepository) Unmute(ctx context.Context, muterID, mutedID uuid.UUID) error {
+	defer pr.store.lock(ctx)()
+
+	if !remove(pr.store.data.mutes, pair{from: muterID, to: mutedID}) {
+		return fmt.Errorf("can not unmute: %w", profile.ErrNotMuting)
+	}
+
+	return nil
+}
+
+// RequestFollow creates a pending follow request. Requesting the same profile again is a no-op.
+// It returns profile.ErrFollowBlocked if one of the users blocks the other one.
+func (pr ProfileRepository) RequestFollow(ctx context.Context, followeeID, followerID uuid.UUID) error {
+	if followeeID == followerID {
+		return fmt.Errorf("can not request follow: %w", profile.ErrCannotFollowSelf)
+	}
+
+	defer pr.store.lock(ctx)()
+
+	if err := pr.store.data.insertUnlessBlocked(pr.store.data.followRequests, followeeID, followerID); err != nil {
+		return fmt.Errorf("can not request follow: %w", err)
+	}
+
+	return nil
+}
+
+// ApproveFollowRequest turns the follow request into a follow.
+// It returns profile.ErrFollowRequestNotFound if there is no such request.
+func (pr ProfileRepository) ApproveFollowRequest(ctx context.Context, followeeID, followerID uuid.UUID) error {
+	defer pr.store.lock(ctx)()
+
+	key := pair{from: followeeID, to: followerID}
+	if !remove(pr.store.data.followRequests, key) {
+		return fmt.Errorf("can not approve follow request: %w", profile.ErrFollowRequestNotFound)
+	}
+
+	insert(pr.store.data.follows, key)
+
+	return nil
+}
+
+// DeleteFollowRequest removes the follow request.
+// It returns profile.ErrFollowRequestNotFound if there is no such request.
+func (pr ProfileRepository) DeleteFollowRequest(ctx context.Context, followeeID, followerID uuid.UUID) error {
+	defer pr.store.lock(ctx)()
+
+	if !remove(pr.store.data.followRequests, pair{from: followeeID, to: followerID}) {
+		return fmt.Errorf("can not delete follow request: %w", profile.ErrFollowRequestNotFound)
+	}
+
+	return nil
+}
+
+// ListFollowRequests returns users requesting to follow the profile, from the newest request to the oldest.
+func (pr ProfileRepository) ListFollowRequests(
+	ctx context.Context,
+	dto profile.ListFollowsDTO,
+) ([]profile.Follow, error) {
+	pr.store.mu.RLock()
+	defer pr.store.mu.RUnlock()
+
+	return pr.store.data.listFollows(pr.store.data.followRequests, dto), nil
+}
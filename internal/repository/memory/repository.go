@@ -0,0 +1,12 @@
+package memory
+
+import "github.com/maypok86/conduit/internal/domain"
+
+// NewRepositories returns the repositories of the services that share store.
+func NewRepositories(store *Store) domain.Repositories {
+	return domain.Repositories{
+		User:    NewUserRepository(store),
+		Profile: NewProfileRepository(store),
+		Export:  NewExportRepository(store),
+	}
+}
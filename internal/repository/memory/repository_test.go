@@ -0,0 +1,33 @@
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/maypok86/conduit/internal/domain"
+	"github.com/maypok86/conduit/internal/repository/contract"
+	"github.com/maypok86/conduit/internal/repository/memory"
+)
+
+func setup(t *testing.T) domain.Repositories {
+	t.Helper()
+
+	return memory.NewRepositories(memory.NewStore())
+}
+
+func TestUserRepository(t *testing.T) {
+	t.Parallel()
+
+	contract.RunUserRepository(t, setup)
+}
+
+func TestProfileRepository(t *testing.T) {
+	t.Parallel()
+
+	contract.RunProfileRepository(t, setup)
+}
+
+func TestExportRepository(t *testing.T) {
+	t.Parallel()
+
+	contract.RunExportRepository(t, setup)
+}
@@ -0,0 +1,227 @@
+// Package memory represents repositories that keep the data in memory. They are meant for development and tests,
+// the data is lost when the process exits.
+package memory
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maypok86/conduit/internal/domain/export"
+	"github.com/maypok86/conduit/internal/domain/user"
+)
+
+type userRecord struct {
+	user.User
+	PurgedAt *time.Time
+}
+
+// pair is a relationship between two users, like a follow or a block.
+type pair struct {
+	from uuid.UUID
+	to   uuid.UUID
+}
+
+type rename struct {
+	userID    uuid.UUID
+	username  string
+	renamedAt time.Time
+}
+
+type quarantine struct {
+	until  time.Time
+	userID *uuid.UUID
+}
+
+// data is the content of a Store. Follows and follow requests are keyed by followee and follower,
+// blocks by blocker and blocked, mutes by muter and muted.
+type data struct {
+	users          map[uuid.UUID]userRecord
+	follows        map[pair]time.Time
+	followRequests map[pair]time.Time
+	blocks         map[pair]time.Time
+	mutes          map[pair]time.Time
+	renames        []rename
+	quarantines    map[string]quarantine
+	exports        []export.Job
+}
+
+func newData() data {
+	return data{
+		users:          make(map[uuid.UUID]userRecord),
+		follows:        make(map[pair]time.Time),
+		followRequests: make(map[pair]time.Time),
+		blocks:         make(map[pair]time.Time),
+		mutes:          make(map[pair]time.Time),
+		quarantines:    make(map[string]quarantine),
+	}
+}
+
+func (d data) clone() data {
+	c := newData()
+
+	for id, u := range d.users {
+		c.users[id] = u
+	}
+
+	for _, m := range []struct{ src, dst map[pair]time.Time }{
+		{src: d.follows, dst: c.follows},
+		{src: d.followRequests, dst: c.followRequests},
+		{src: d.blocks, dst: c.blocks},
+		{src: d.mutes, dst: c.mutes},
+	} {
+		for k, v := range m.src {
+			m.dst[k] = v
+		}
+	}
+
+	for username, q := range d.quarantines {
+		c.quarantines[username] = q
+	}
+
+	c.renames = append(c.renames, d.renames...)
+	c.exports = append(c.exports, d.exports...)
+
+	return c
+}
+
+// findUser returns the user matching the filter. It is the only way to look up users,
+// so the lookups share the case-insensitive comparison of the psql repositories.
+func (d data) findUser(match func(u userRecord) bool) (userRecord, bool) {
+	for _, u := range d.users {
+		if match(u) {
+			return u, true
+		}
+	}
+
+	return userRecord{}, false
+}
+
+func (d data) isActive(id uuid.UUID) bool {
+	u, ok := d.users[id]
+
+	return ok && u.DeletedAt == nil
+}
+
+// approveFollowRequests turns the pending follow requests to the user into follows.
+func (d data) approveFollowRequests(id uuid.UUID) {
+	for k := range d.followRequests {
+		if k.from == id {
+			delete(d.followRequests, k)
+			insert(d.follows, k)
+		}
+	}
+}
+
+// deleteRelations removes everything that references the user except the user itself.
+func (d *data) deleteRelations(id uuid.UUID) {
+	for _, m := range []map[pair]time.Time{d.follows, d.followRequests, d.blocks, d.mutes} {
+		for k := range m {
+			if k.from == id || k.to == id {
+				delete(m, k)
+			}
+		}
+	}
+
+	renames := d.renames[:0]
+
+	for _, r := range d.renames {
+		if r.userID != id {
+			renames = append(renames, r)
+		}
+	}
+
+	d.renames = renames
+
+	exports := d.exports[:0]
+
+	for _, job := range d.exports {
+		if job.UserID != id {
+			exports = append(exports, job)
+		}
+	}
+
+	d.exports = exports
+}
+
+func equalFold(a, b string) bool {
+	return strings.ToLower(a) == strings.ToLower(b)
+}
+
+// now returns the current time as postgres timestamp columns return it.
+func now() time.Time {
+	return time.Now().UTC()
+}
+
+func cloneString(s *string) *string {
+	if s == nil {
+		return nil
+	}
+
+	c := *s
+
+	return &c
+}
+
+type txKey struct{}
+
+// Store keeps the data of all memory repositories.
+type Store struct {
+	mu   sync.RWMutex
+	txMu sync.Mutex
+	data data
+}
+
+// NewStore creates a new empty Store.
+func NewStore() *Store {
+	return &Store{
+		data: newData(),
+	}
+}
+
+// WithinTransaction runs fn and restores the data to the state before fn if fn returns an error.
+// Transactions are serialized with the writes made outside of them, so restoring the data never discards
+// changes that are not part of the transaction. A nested call works like a savepoint.
+func (s *Store) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if ctx.Value(txKey{}) == nil {
+		s.txMu.Lock()
+		defer s.txMu.Unlock()
+
+		ctx = context.WithValue(ctx, txKey{}, struct{}{})
+	}
+
+	s.mu.RLock()
+	snapshot := s.data.clone()
+	s.mu.RUnlock()
+
+	if err := fn(ctx); err != nil {
+		s.mu.Lock()
+		s.data = snapshot
+		s.mu.Unlock()
+
+		return err
+	}
+
+	return nil
+}
+
+// lock locks the data for a write and returns the function that unlocks it. A write outside of a transaction
+// waits for the running transaction to end, a write inside of it already holds the transaction lock.
+func (s *Store) lock(ctx context.Context) func() {
+	inTx := ctx.Value(txKey{}) != nil
+	if !inTx {
+		s.txMu.Lock()
+	}
+
+	s.mu.Lock()
+
+	return func() {
+		s.mu.Unlock()
+
+		if !inTx {
+			s.txMu.Unlock()
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package memory_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/maypok86/conduit/internal/domain/user"
+	"github.com/maypok86/conduit/internal/repository/memory"
+	"github.com/stretchr/testify/require"
+)
+
+var errTx = errors.New("tx error")
+
+func TestStore_WithinTransaction(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := memory.NewStore()
+	repositories := memory.NewRepositories(store)
+
+	create := func(ctx context.Context, username string) error {
+		_, err := repositories.User.Create(ctx, user.User{Username: username, Email: username + "@example.com"})
+
+		return err
+	}
+
+	err := store.WithinTransaction(ctx, func(ctx context.Context) error {
+		require.NoError(t, create(ctx, "alice"))
+
+		require.ErrorIs(t, store.WithinTransaction(ctx, func(ctx context.Context) error {
+			require.NoError(t, create(ctx, "bob"))
+
+			return errTx
+		}), errTx)
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	err = store.WithinTransaction(ctx, func(ctx context.Context) error {
+		require.NoError(t, create(ctx, "carol"))
+
+		return errTx
+	})
+	require.ErrorIs(t, err, errTx)
+
+	_, err = repositories.User.GetByEmail(ctx, "alice@example.com")
+	require.NoError(t, err)
+
+	for _, email := range []string{"bob@example.com", "carol@example.com"} {
+		_, err = repositories.User.GetByEmail(ctx, email)
+		require.ErrorIs(t, err, user.ErrNotFound)
+	}
+}
+
+func TestStore_WithinTransactionKeepsConcurrentWrites(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := memory.NewStore()
+	repositories := memory.NewRepositories(store)
+
+	created := make(chan error)
+
+	err := store.WithinTransaction(ctx, func(txCtx context.Context) error {
+		_, err := repositories.User.Create(txCtx, user.User{Username: "alice", Email: "alice@example.com"})
+		require.NoError(t, err)
+
+		go func() {
+			_, err := repositories.User.Create(ctx, user.User{Username: "bob", Email: "bob@example.com"})
+			created <- err
+		}()
+
+		return errTx
+	})
+	require.ErrorIs(t, err, errTx)
+	require.NoError(t, <-created)
+
+	_, err = repositories.User.GetByEmail(ctx, "alice@example.com")
+	require.ErrorIs(t, err, user.ErrNotFound)
+
+	_, err = repositories.User.GetByEmail(ctx, "bob@example.com")
+	require.NoError(t, err)
+}
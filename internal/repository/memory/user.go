@@ -0,0 +1,280 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maypok86/conduit/internal/domain/user"
+)
+
+// UserRepository is a user repository.
+type UserRepository struct {
+	store *Store
+}
+
+// NewUserRepository creates a new UserRepository.
+func NewUserRepository(store *Store) UserRepository {
+	return UserRepository{
+		store: store,
+	}
+}
+
+// Create creates a new user.
+func (ur UserRepository) Create(ctx context.Context, dto user.User) (user.User, error) {
+	defer ur.store.lock(ctx)()
+
+	if err := ur.store.data.checkConflict(uuid.Nil, dto.Email, dto.Username); err != nil {
+		return user.User{}, fmt.Errorf("can not insert user: %w", err)
+	}
+
+	createdAt := now()
+	dto.ID = uuid.New()
+
+	ur.store.data.users[dto.ID] = userRecord{
+		User: user.User{
+			ID:        dto.ID,
+			Username:  dto.Username,
+			Email:     dto.Email,
+			Password:  dto.Password,
+			CreatedAt: createdAt,
+			UpdatedAt: createdAt,
+		},
+	}
+
+	return dto, nil
+}
+
+// checkConflict checks the case-insensitive uniqueness of email and username among all users except id.
+// Deleted and purged users keep their email and username.
+func (d data) checkConflict(id uuid.UUID, email, username string) error {
+	if _, ok := d.findUser(func(u userRecord) bool {
+		return u.ID != id && equalFold(u.Email, email)
+	}); ok {
+		return user.ErrEmailAlreadyExist
+	}
+
+	if _, ok := d.findUser(func(u userRecord) bool {
+		return u.ID != id && equalFold(u.Username, username)
+	}); ok {
+		return user.ErrUsernameAlreadyExist
+	}
+
+	return nil
+}
+
+// GetByEmail returns user by email. Emails are compared case-insensitively.
+func (ur UserRepository) GetByEmail(ctx context.Context, email string) (user.User, error) {
+	ur.store.mu.RLock()
+	defer ur.store.mu.RUnlock()
+
+	u, ok := ur.store.data.findUser(func(u userRecord) bool {
+		return equalFold(u.Email, email)
+	})
+	if !ok {
+		return user.User{}, fmt.Errorf("can not find user by email: %w", user.ErrNotFound)
+	}
+
+	return u.User, nil
+}
+
+// LockByEmail returns the active user by email. The transactions of the store are serialized,
+// so there is nothing to lock.
+func (ur UserRepository) LockByEmail(ctx context.Context, email string) (user.User, error) {
+	ur.store.mu.RLock()
+	defer ur.store.mu.RUnlock()
+
+	u, ok := ur.store.data.findUser(func(u userRecord) bool {
+		return equalFold(u.Email, email) && u.DeletedAt == nil
+	})
+	if !ok {
+		return user.User{}, fmt.Errorf("can not lock user by email: %w", user.ErrNotFound)
+	}
+
+	return u.User, nil
+}
+
+// UpdateByEmail updates user by email.
+func (ur UserRepository) UpdateByEmail(ctx context.Context, email string, dto user.UpdateDTO) (user.User, error) {
+	defer ur.store.lock(ctx)()
+
+	d := &ur.store.data
+
+	record, ok := d.findUser(func(u userRecord) bool {
+		return equalFold(u.Email, email) && u.DeletedAt == nil
+	})
+	if !ok {
+		return user.User{}, fmt.Errorf("can not update user by email: %w", user.ErrNotFound)
+	}
+
+	updated := record.User
+	if dto.Username != nil {
+		updated.Username = *dto.Username
+	}
+
+	if dto.Email != nil {
+		updated.Email = *dto.Email
+	}
+
+	if dto.Bio != nil {
+		updated.Bio = cloneString(dto.Bio)
+	}
+
+	if dto.Image != nil {
+		updated.Image = cloneString(dto.Image)
+	}
+
+	if dto.Private != nil {
+		updated.Private = *dto.Private
+	}
+
+	updated.UpdatedAt = dto.UpdatedAt
+
+	if err := d.checkConflict(updated.ID, updated.Email, updated.Username); err != nil {
+		return user.User{}, fmt.Errorf("can not update user by email: %w", err)
+	}
+
+	// A change of the case only keeps the username, so it is not recorded.
+	if !equalFold(updated.Username, record.Username) {
+		d.renames = append(d.renames, rename{
+			userID:    updated.ID,
+			username:  record.Username,
+			renamedAt: dto.UpdatedAt,
+		})
+
+		userID := updated.ID
+		d.quarantines[strings.ToLower(record.Username)] = quarantine{
+			until:  dto.UsernameCooldownUntil,
+			userID: &userID,
+		}
+	}
+
+	if dto.Private != nil && !*dto.Private {
+		d.approveFollowRequests(updated.ID)
+	}
+
+	record.User = updated
+	d.users[updated.ID] = record
+
+	return updated, nil
+}
+
+// DeleteByEmail marks user as deleted.
+func (ur UserRepository) DeleteByEmail(ctx context.Context, email string, deletedAt time.Time) error {
+	defer ur.store.lock(ctx)()
+
+	record, ok := ur.store.data.findUser(func(u userRecord) bool {
+		return equalFold(u.Email, email) && u.DeletedAt == nil
+	})
+	if !ok {
+		return fmt.Errorf("can not delete user by email: %w", user.ErrNotFound)
+	}
+
+	record.DeletedAt = &deletedAt
+	ur.store.data.users[record.ID] = record
+
+	return nil
+}
+
+// Restore cancels user deletion if user is not purged yet.
+func (ur UserRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	defer ur.store.lock(ctx)()
+
+	record, ok := ur.store.data.users[id]
+	if !ok || record.PurgedAt != nil {
+		return fmt.Errorf("can not restore user: %w", user.ErrNotFound)
+	}
+
+	record.DeletedAt = nil
+	ur.store.data.users[id] = record
+
+	return nil
+}
+
+// Purge anonymizes or deletes users whose deletion grace period has ended.
+func (ur UserRepository) Purge(ctx context.Context, dto user.PurgeDTO) (int64, error) {
+	if dto.Mode != user.DeletionModeAnonymize && dto.Mode != user.DeletionModeCascade {
+		return 0, fmt.Errorf("can not purge users in %q mode: %w", dto.Mode, user.ErrUnknownDeletionMode)
+	}
+
+	defer ur.store.lock(ctx)()
+
+	d := &ur.store.data
+
+	var purged int64
+
+	for id, record := range d.users {
+		if record.DeletedAt == nil || !record.DeletedAt.Before(dto.DeletedBefore) || record.PurgedAt != nil {
+			continue
+		}
+
+		d.quarantines[strings.ToLower(record.Username)] = quarantine{until: dto.QuarantinedUntil}
+		d.deleteRelations(id)
+
+		if dto.Mode == user.DeletionModeCascade {
+			delete(d.users, id)
+		} else {
+			purgedAt := dto.PurgedAt
+			record.Username = "deleted-" + strings.ReplaceAll(id.String(), "-", "")
+			record.Email = id.String() + "@deleted.invalid"
+			record.Password = ""
+			record.Bio = nil
+			record.Image = nil
+			record.PurgedAt = &purgedAt
+			d.users[id] = record
+		}
+
+		purged++
+	}
+
+	if dto.Mode == user.DeletionModeCascade {
+		// The quarantined usernames outlive the users, like ON DELETE SET NULL does.
+		for username, q := range d.quarantines {
+			if q.userID != nil {
+				if _, ok := d.users[*q.userID]; !ok {
+					q.userID = nil
+					d.quarantines[username] = q
+				}
+			}
+		}
+	}
+
+	return purged, nil
+}
+
+// CheckUsernameQuarantine checks if username was released recently and can not be claimed yet.
+// Usernames released by the user itself can be claimed back.
+func (ur UserRepository) CheckUsernameQuarantine(
+	ctx context.Context,
+	username string,
+	userID uuid.UUID,
+	now time.Time,
+) error {
+	ur.store.mu.RLock()
+	defer ur.store.mu.RUnlock()
+
+	q, ok := ur.store.data.quarantines[strings.ToLower(username)]
+	if !ok || !q.until.After(now) || (q.userID != nil && *q.userID == userID) {
+		return nil
+	}
+
+	return fmt.Errorf("can not check username quarantine: %w", user.ErrUsernameQuarantined)
+}
+
+// CountRenames returns the number of username changes of the user since the given time.
+func (ur UserRepository) CountRenames(ctx context.Context, userID uuid.UUID, since time.Time) (int, error) {
+	ur.store.mu.RLock()
+	defer ur.store.mu.RUnlock()
+
+	var count int
+
+	for _, r := range ur.store.data.renames {
+		if r.userID == userID && !r.renamedAt.Before(since) {
+			count++
+		}
+	}
+
+	return count, nil
+}
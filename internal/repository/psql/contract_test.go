@@ -0,0 +1,32 @@
+package psql_test
+
+import (
+	"testing"
+
+	"github.com/maypok86/conduit/internal/domain"
+	"github.com/maypok86/conduit/internal/repository/contract"
+	"github.com/maypok86/conduit/internal/repository/psql"
+	"github.com/maypok86/conduit/internal/repository/psql/integration"
+)
+
+// setupContract gives every case a freshly migrated schema, see the integration package.
+func setupContract(t *testing.T) domain.Repositories {
+	t.Helper()
+
+	return psql.NewRepositories(integration.NewPostgres(t))
+}
+
+//nolint:paralleltest // the contract runs its cases sequentially.
+func TestUserRepository_Contract(t *testing.T) {
+	contract.RunUserRepository(t, setupContract)
+}
+
+//nolint:paralleltest // the contract runs its cases sequentially.
+func TestProfileRepository_Contract(t *testing.T) {
+	contract.RunProfileRepository(t, setupContract)
+}
+
+//nolint:paralleltest // the contract runs its cases sequentially.
+func TestExportRepository_Contract(t *testing.T) {
+	contract.RunExportRepository(t, setupContract)
+}
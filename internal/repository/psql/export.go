@@ -0,0 +1,212 @@
+package psql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/maypok86/conduit/internal/domain/export"
+	"github.com/maypok86/conduit/pkg/logger"
+	"github.com/maypok86/conduit/pkg/postgres"
+	"go.uber.org/zap"
+)
+
+// ExportRepository is a user data export repository.
+type ExportRepository struct {
+	db *postgres.Postgres
+}
+
+// NewExportRepository creates a new ExportRepository.
+func NewExportRepository(db *postgres.Postgres) ExportRepository {
+	return ExportRepository{
+		db: db,
+	}
+}
+
+// CountFollows returns the number of follow relationships in both directions.
+func (er ExportRepository) CountFollows(ctx context.Context, userID uuid.UUID) (int, error) {
+	sql, args, err := er.db.Builder.Select("count(*)").From("follows").
+		Where(sq.Or{sq.Eq{"followee_id": userID}, sq.Eq{"follower_id": userID}}).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("can not build count follows query: %w", err)
+	}
+
+	ctx = postgres.WithQueryName(ctx, "count_follows")
+	logger.FromContext(ctx).Debug("count follows query", zap.String("sql", sql), zap.Any("args", args))
+
+	var count int
+	if err := er.db.ReadQuerier(ctx).QueryRow(ctx, sql, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("can not count follows: %w", err)
+	}
+
+	return count, nil
+}
+
+// GetFollowing returns users followed by the user.
+func (er ExportRepository) GetFollowing(ctx context.Context, userID uuid.UUID) ([]export.Follow, error) {
+	sql, args, err := er.db.Builder.Select("users.username", "follows.created_at").From("follows").
+		Join("users ON users.id = follows.followee_id").
+		Where(sq.Eq{"follows.follower_id": userID}).
+		OrderBy("follows.created_at").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("can not build select following query: %w", err)
+	}
+
+	ctx = postgres.WithQueryName(ctx, "select_following")
+	logger.FromContext(ctx).Debug("select following query", zap.String("sql", sql), zap.Any("args", args))
+
+	return er.queryFollows(ctx, sql, args)
+}
+
+// GetFollowers returns users following the user.
+func (er ExportRepository) GetFollowers(ctx context.Context, userID uuid.UUID) ([]export.Follow, error) {
+	sql, args, err := er.db.Builder.Select("users.username", "follows.created_at").From("follows").
+		Join("users ON users.id = follows.follower_id").
+		Where(sq.Eq{"follows.followee_id": userID}).
+		OrderBy("follows.created_at").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("can not build select followers query: %w", err)
+	}
+
+	ctx = postgres.WithQueryName(ctx, "select_followers")
+	logger.FromContext(ctx).Debug("select followers query", zap.String("sql", sql), zap.Any("args", args))
+
+	return er.queryFollows(ctx, sql, args)
+}
+
+func (er ExportRepository) queryFollows(ctx context.Context, sql string, args []interface{}) ([]export.Follow, error) {
+	rows, err := er.db.ReadQuerier(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("can not query follows: %w", err)
+	}
+	defer rows.Close()
+
+	follows := make([]export.Follow, 0)
+
+	for rows.Next() {
+		var follow export.Follow
+		if err := rows.Scan(&follow.Username, &follow.CreatedAt); err != nil {
+			return nil, fmt.Errorf("can not scan follow: %w", err)
+		}
+
+		follows = append(follows, follow)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("can not read follows: %w", err)
+	}
+
+	return follows, nil
+}
+
+// CreateJob creates a pending export job. It replaces the previous jobs of the user, so a user keeps
+// a single archive.
+func (er ExportRepository) CreateJob(ctx context.Context, userID uuid.UUID) (export.Job, error) {
+	sql, args, err := er.db.Builder.Insert("user_exports").
+		Prefix("WITH replaced AS (DELETE FROM user_exports WHERE user_id = ?)", userID).
+		Columns("user_id", "status").
+		Values(userID, string(export.StatusPending)).
+		Suffix("RETURNING id, created_at, updated_at").
+		ToSql()
+	if err != nil {
+		return export.Job{}, fmt.Errorf("can not build insert export job query: %w", err)
+	}
+
+	ctx = postgres.WithQueryName(ctx, "create_export_job")
+	logger.FromContext(ctx).Debug("create export job query", zap.String("sql", sql), zap.Any("args", args))
+
+	job := export.Job{UserID: userID, Status: export.StatusPending}
+	if err := er.db.Querier(ctx).QueryRow(ctx, sql, args...).Scan(&job.ID, &job.CreatedAt, &job.UpdatedAt); err != nil {
+		return export.Job{}, fmt.Errorf("can not insert export job: %w", err)
+	}
+
+	return job, nil
+}
+
+// GetLastJob returns the most recent export job of the user.
+func (er ExportRepository) GetLastJob(ctx context.Context, userID uuid.UUID) (export.Job, error) {
+	sql, args, err := er.db.Builder.Select(
+		"id",
+		"status",
+		"archive",
+		"created_at",
+		"updated_at",
+	).From("user_exports").Where(sq.Eq{"user_id": userID}).OrderBy("created_at DESC").Limit(1).ToSql()
+	if err != nil {
+		return export.Job{}, fmt.Errorf("can not build select last export job query: %w", err)
+	}
+
+	ctx = postgres.WithQueryName(ctx, "select_last_export_job")
+	logger.FromContext(ctx).Debug("select last export job query", zap.String("sql", sql), zap.Any("args", args))
+
+	var status string
+
+	job := export.Job{UserID: userID}
+	if err := er.db.ReadQuerier(ctx).QueryRow(ctx, sql, args...).Scan(
+		&job.ID,
+		&status,
+		&job.Archive,
+		&job.CreatedAt,
+		&job.UpdatedAt,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return export.Job{}, fmt.Errorf("can not find last export job: %w", export.ErrNotFound)
+		}
+
+		return export.Job{}, fmt.Errorf("can not find last export job: %w", err)
+	}
+
+	job.Status = export.Status(status)
+
+	return job, nil
+}
+
+// CompleteJob stores the archive of the export job.
+func (er ExportRepository) CompleteJob(ctx context.Context, id uuid.UUID, archive []byte) error {
+	sql, args, err := er.db.Builder.Update("user_exports").
+		Set("status", string(export.StatusReady)).
+		Set("archive", archive).
+		Set("updated_at", sq.Expr("CURRENT_TIMESTAMP")).
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("can not build complete export job query: %w", err)
+	}
+
+	ctx = postgres.WithQueryName(ctx, "complete_export_job")
+	logger.FromContext(ctx).Debug("complete export job query", zap.String("sql", sql))
+
+	if _, err := er.db.Querier(ctx).Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("can not complete export job: %w", err)
+	}
+
+	return nil
+}
+
+// FailJob marks the export job as failed.
+func (er ExportRepository) FailJob(ctx context.Context, id uuid.UUID, reason string) error {
+	sql, args, err := er.db.Builder.Update("user_exports").
+		Set("status", string(export.StatusFailed)).
+		Set("error", reason).
+		Set("updated_at", sq.Expr("CURRENT_TIMESTAMP")).
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("can not build fail export job query: %w", err)
+	}
+
+	ctx = postgres.WithQueryName(ctx, "fail_export_job")
+	logger.FromContext(ctx).Debug("fail export job query", zap.String("sql", sql), zap.Any("args", args))
+
+	if _, err := er.db.Querier(ctx).Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("can not fail export job: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,368 @@
+package psql_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/maypok86/conduit/internal/domain/export"
+	"github.com/maypok86/conduit/internal/repository/psql"
+	mockPsql "github.com/maypok86/conduit/internal/repository/psql/mocks"
+	"github.com/maypok86/conduit/pkg/logger"
+	"github.com/maypok86/conduit/pkg/postgres"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+var errExportRepository = errors.New("export repository error")
+
+func mockExportRepository(
+	t *testing.T,
+) (psql.ExportRepository, *mockPsql.MockPgxPool, *mockPsql.MockRow, *mockPsql.MockRows) {
+	t.Helper()
+
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	mockPgxPool := mockPsql.NewMockPgxPool(mockCtl)
+	mockRow := mockPsql.NewMockRow(mockCtl)
+	mockRows := mockPsql.NewMockRows(mockCtl)
+
+	db := &postgres.Postgres{
+		Builder: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+		Pool:    mockPgxPool,
+	}
+
+	return psql.NewExportRepository(db), mockPgxPool, mockRow, mockRows
+}
+
+func TestExportRepository_CountFollows(t *testing.T) {
+	t.Parallel()
+
+	ctx := logger.ContextWithLogger(context.Background(), zap.L())
+	queryCtx := postgres.WithQueryName(ctx, "count_follows")
+	expectedSQL := "SELECT count(*) FROM follows WHERE (followee_id = $1 OR follower_id = $2)"
+	userID := uuid.New()
+
+	tests := []struct {
+		name    string
+		mock    func(*mockPsql.MockRow, *mockPsql.MockPgxPool)
+		wantErr bool
+	}{
+		{
+			name: "success count",
+			mock: func(row *mockPsql.MockRow, pool *mockPsql.MockPgxPool) {
+				row.EXPECT().Scan(gomock.Any()).Return(nil).Times(1)
+				pool.EXPECT().QueryRow(queryCtx, expectedSQL, userID.String(), userID.String()).Return(row).Times(1)
+			},
+		},
+		{
+			name: "scan error",
+			mock: func(row *mockPsql.MockRow, pool *mockPsql.MockPgxPool) {
+				row.EXPECT().Scan(gomock.Any()).Return(errExportRepository).Times(1)
+				pool.EXPECT().QueryRow(queryCtx, expectedSQL, userID.String(), userID.String()).Return(row).Times(1)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			exportRepository, mockPgxPool, mockRow, _ := mockExportRepository(t)
+
+			tt.mock(mockRow, mockPgxPool)
+
+			_, err := exportRepository.CountFollows(ctx, userID)
+			require.True(t, (err != nil) == tt.wantErr)
+		})
+	}
+}
+
+func TestExportRepository_GetFollowing(t *testing.T) {
+	t.Parallel()
+
+	ctx := logger.ContextWithLogger(context.Background(), zap.L())
+	queryCtx := postgres.WithQueryName(ctx, "select_following")
+	expectedSQL := "SELECT users.username, follows.created_at FROM follows " +
+		"JOIN users ON users.id = follows.followee_id WHERE follows.follower_id = $1 ORDER BY follows.created_at"
+	userID := uuid.New()
+
+	tests := []struct {
+		name    string
+		mock    func(*mockPsql.MockRows, *mockPsql.MockPgxPool)
+		want    []export.Follow
+		wantErr bool
+	}{
+		{
+			name: "success get following",
+			mock: func(rows *mockPsql.MockRows, pool *mockPsql.MockPgxPool) {
+				pool.EXPECT().Query(queryCtx, expectedSQL, userID.String()).Return(rows, nil).Times(1)
+				gomock.InOrder(
+					rows.EXPECT().Next().Return(true),
+					rows.EXPECT().Scan(gomock.Any(), gomock.Any()).Return(nil),
+					rows.EXPECT().Next().Return(false),
+				)
+				rows.EXPECT().Err().Return(nil).Times(1)
+				rows.EXPECT().Close().Times(1)
+			},
+			want: []export.Follow{{}},
+		},
+		{
+			name: "scan error",
+			mock: func(rows *mockPsql.MockRows, pool *mockPsql.MockPgxPool) {
+				pool.EXPECT().Query(queryCtx, expectedSQL, userID.String()).Return(rows, nil).Times(1)
+				rows.EXPECT().Next().Return(true).Times(1)
+				rows.EXPECT().Scan(gomock.Any(), gomock.Any()).Return(errExportRepository).Times(1)
+				rows.EXPECT().Close().Times(1)
+			},
+			wantErr: true,
+		},
+		{
+			name: "rows error",
+			mock: func(rows *mockPsql.MockRows, pool *mockPsql.MockPgxPool) {
+				pool.EXPECT().Query(queryCtx, expectedSQL, userID.String()).Return(rows, nil).Times(1)
+				rows.EXPECT().Next().Return(false).Times(1)
+				rows.EXPECT().Err().Return(errExportRepository).Times(1)
+				rows.EXPECT().Close().Times(1)
+			},
+			wantErr: true,
+		},
+		{
+			name: "query error",
+			mock: func(rows *mockPsql.MockRows, pool *mockPsql.MockPgxPool) {
+				pool.EXPECT().Query(queryCtx, expectedSQL, userID.String()).Return(nil, errExportRepository).Times(1)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			exportRepository, mockPgxPool, _, mockRows := mockExportRepository(t)
+
+			tt.mock(mockRows, mockPgxPool)
+
+			got, err := exportRepository.GetFollowing(ctx, userID)
+			require.True(t, (err != nil) == tt.wantErr)
+			require.True(t, reflect.DeepEqual(tt.want, got))
+		})
+	}
+}
+
+func TestExportRepository_GetLastJob(t *testing.T) {
+	t.Parallel()
+
+	ctx := logger.ContextWithLogger(context.Background(), zap.L())
+	queryCtx := postgres.WithQueryName(ctx, "select_last_export_job")
+	expectedSQL := "SELECT id, status, archive, created_at, updated_at FROM user_exports " +
+		"WHERE user_id = $1 ORDER BY created_at DESC LIMIT 1"
+	userID := uuid.New()
+
+	tests := []struct {
+		name    string
+		mock    func(*mockPsql.MockRow, *mockPsql.MockPgxPool)
+		wantErr error
+	}{
+		{
+			name: "success get last job",
+			mock: func(row *mockPsql.MockRow, pool *mockPsql.MockPgxPool) {
+				row.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				pool.EXPECT().QueryRow(queryCtx, expectedSQL, userID.String()).Return(row).Times(1)
+			},
+		},
+		{
+			name: "no rows error",
+			mock: func(row *mockPsql.MockRow, pool *mockPsql.MockPgxPool) {
+				row.EXPECT().
+					Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(pgx.ErrNoRows)
+				pool.EXPECT().QueryRow(queryCtx, expectedSQL, userID.String()).Return(row).Times(1)
+			},
+			wantErr: export.ErrNotFound,
+		},
+		{
+			name: "scan error",
+			mock: func(row *mockPsql.MockRow, pool *mockPsql.MockPgxPool) {
+				row.EXPECT().
+					Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(errExportRepository)
+				pool.EXPECT().QueryRow(queryCtx, expectedSQL, userID.String()).Return(row).Times(1)
+			},
+			wantErr: errExportRepository,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			exportRepository, mockPgxPool, mockRow, _ := mockExportRepository(t)
+
+			tt.mock(mockRow, mockPgxPool)
+
+			_, err := exportRepository.GetLastJob(ctx, userID)
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestExportRepository_CreateJob(t *testing.T) {
+	t.Parallel()
+
+	ctx := logger.ContextWithLogger(context.Background(), zap.L())
+	queryCtx := postgres.WithQueryName(ctx, "create_export_job")
+	expectedSQL := "WITH replaced AS (DELETE FROM user_exports WHERE user_id = $1) " +
+		"INSERT INTO user_exports (user_id,status) VALUES ($2,$3) RETURNING id, created_at, updated_at"
+	userID := uuid.New()
+
+	tests := []struct {
+		name    string
+		mock    func(*mockPsql.MockRow, *mockPsql.MockPgxPool)
+		want    export.Job
+		wantErr bool
+	}{
+		{
+			name: "success create job",
+			mock: func(row *mockPsql.MockRow, pool *mockPsql.MockPgxPool) {
+				row.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				pool.EXPECT().QueryRow(queryCtx, expectedSQL, userID, userID, "pending").Return(row).Times(1)
+			},
+			want: export.Job{UserID: userID, Status: export.StatusPending},
+		},
+		{
+			name: "scan error",
+			mock: func(row *mockPsql.MockRow, pool *mockPsql.MockPgxPool) {
+				row.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any()).Return(errExportRepository).Times(1)
+				pool.EXPECT().QueryRow(queryCtx, expectedSQL, userID, userID, "pending").Return(row).Times(1)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			exportRepository, mockPgxPool, mockRow, _ := mockExportRepository(t)
+
+			tt.mock(mockRow, mockPgxPool)
+
+			got, err := exportRepository.CreateJob(ctx, userID)
+			require.True(t, (err != nil) == tt.wantErr)
+			require.True(t, reflect.DeepEqual(tt.want, got))
+		})
+	}
+}
+
+func TestExportRepository_CompleteJob(t *testing.T) {
+	t.Parallel()
+
+	ctx := logger.ContextWithLogger(context.Background(), zap.L())
+	queryCtx := postgres.WithQueryName(ctx, "complete_export_job")
+	expectedSQL := "UPDATE user_exports SET status = $1, archive = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $3"
+	id := uuid.New()
+	archive := []byte("archive")
+
+	tests := []struct {
+		name    string
+		mock    func(*mockPsql.MockPgxPool)
+		wantErr bool
+	}{
+		{
+			name: "success complete job",
+			mock: func(pool *mockPsql.MockPgxPool) {
+				pool.EXPECT().Exec(queryCtx, expectedSQL, "ready", archive, id.String()).Return([]byte{}, nil).Times(1)
+			},
+		},
+		{
+			name: "exec error",
+			mock: func(pool *mockPsql.MockPgxPool) {
+				pool.EXPECT().
+					Exec(queryCtx, expectedSQL, "ready", archive, id.String()).
+					Return(nil, errExportRepository).
+					Times(1)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			exportRepository, mockPgxPool, _, _ := mockExportRepository(t)
+
+			tt.mock(mockPgxPool)
+
+			err := exportRepository.CompleteJob(ctx, id, archive)
+			require.True(t, (err != nil) == tt.wantErr)
+		})
+	}
+}
+
+func TestExportRepository_FailJob(t *testing.T) {
+	t.Parallel()
+
+	ctx := logger.ContextWithLogger(context.Background(), zap.L())
+	queryCtx := postgres.WithQueryName(ctx, "fail_export_job")
+	expectedSQL := "UPDATE user_exports SET status = $1, error = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $3"
+	id := uuid.New()
+	reason := "reason"
+
+	tests := []struct {
+		name    string
+		mock    func(*mockPsql.MockPgxPool)
+		wantErr bool
+	}{
+		{
+			name: "success fail job",
+			mock: func(pool *mockPsql.MockPgxPool) {
+				pool.EXPECT().Exec(queryCtx, expectedSQL, "failed", reason, id.String()).Return([]byte{}, nil).Times(1)
+			},
+		},
+		{
+			name: "exec error",
+			mock: func(pool *mockPsql.MockPgxPool) {
+				pool.EXPECT().
+					Exec(queryCtx, expectedSQL, "failed", reason, id.String()).
+					Return(nil, errExportRepository).
+					Times(1)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			exportRepository, mockPgxPool, _, _ := mockExportRepository(t)
+
+			tt.mock(mockPgxPool)
+
+			err := exportRepository.FailJob(ctx, id, reason)
+			require.True(t, (err != nil) == tt.wantErr)
+		})
+	}
+}
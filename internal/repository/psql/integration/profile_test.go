@@ -0,0 +1,538 @@
+package integration_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bxcodec/faker/v3"
+	"github.com/google/uuid"
+	"github.com/maypok86/conduit/internal/domain/profile"
+	"github.com/maypok86/conduit/internal/domain/user"
+	"github.com/maypok86/conduit/internal/repository/psql"
+	"github.com/maypok86/conduit/internal/repository/psql/integration"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfileRepository_GetByUsername(t *testing.T) {
+	t.Parallel()
+
+	db := integration.NewPostgres(t)
+	profileRepository := psql.NewProfileRepository(db)
+	userRepository := psql.NewUserRepository(db)
+
+	t.Run("success get by username", func(t *testing.T) {
+		t.Parallel()
+
+		integration.WithinRollback(t, db, func(ctx context.Context) {
+			created := createUser(ctx, t, db)
+			follower := createUser(ctx, t, db)
+			deletedFollower := createUser(ctx, t, db)
+			followee := createUser(ctx, t, db)
+			bio := faker.Sentence()
+
+			_, err := userRepository.UpdateByEmail(ctx, created.Email, user.UpdateDTO{Bio: &bio, UpdatedAt: time.Now()})
+			require.NoError(t, err)
+			require.NoError(t, profileRepository.Follow(ctx, created.ID, follower.ID))
+			require.NoError(t, profileRepository.Follow(ctx, created.ID, deletedFollower.ID))
+			require.NoError(t, profileRepository.Follow(ctx, followee.ID, created.ID))
+			require.NoError(t, userRepository.DeleteByEmail(ctx, deletedFollower.Email, time.Now()))
+
+			got, err := profileRepository.GetByUsername(ctx, strings.ToUpper(created.Username))
+			require.NoError(t, err)
+			require.Equal(t, created.ID, got.ID)
+			require.Equal(t, created.Username, got.Username)
+			require.Equal(t, bio, got.GetBio())
+			require.Nil(t, got.Image)
+			require.False(t, got.Private)
+			require.Equal(t, 1, got.FollowersCount)
+			require.Equal(t, 1, got.FollowingCount)
+			require.False(t, got.CreatedAt.IsZero())
+		})
+	})
+
+	t.Run("no rows error", func(t *testing.T) {
+		t.Parallel()
+
+		integration.WithinRollback(t, db, func(ctx context.Context) {
+			deleted := createUser(ctx, t, db)
+
+			require.NoError(t, userRepository.DeleteByEmail(ctx, deleted.Email, time.Now()))
+
+			for _, username := range []string{deleted.Username, faker.Username()} {
+				_, err := profileRepository.GetByUsername(ctx, username)
+				require.ErrorIs(t, err, profile.ErrNotFound)
+			}
+		})
+	})
+}
+
+func TestProfileRepository_GetByPreviousUsername(t *testing.T) {
+	t.Parallel()
+
+	db := integration.NewPostgres(t)
+	profileRepository := psql.NewProfileRepository(db)
+	userRepository := psql.NewUserRepository(db)
+
+	t.Run("success get by previous username", func(t *testing.T) {
+		t.Parallel()
+
+		integration.WithinRollback(t, db, func(ctx context.Context) {
+			created := createUser(ctx, t, db)
+			username := faker.Username()
+
+			_, err := userRepository.UpdateByEmail(ctx, created.Email, user.UpdateDTO{
+				Username:  &username,
+				UpdatedAt: time.Now(),
+			})
+			require.NoError(t, err)
+
+			got, err := profileRepository.GetByPreviousUsername(ctx, strings.ToUpper(created.Username))
+			require.NoError(t, err)
+			require.Equal(t, created.ID, got.ID)
+			require.Equal(t, username, got.Username)
+		})
+	})
+
+	t.Run("no rows error", func(t *testing.T) {
+		t.Parallel()
+
+		integration.WithinRollback(t, db, func(ctx context.Context) {
+			_, err := profileRepository.GetByPreviousUsername(ctx, faker.Username())
+			require.ErrorIs(t, err, profile.ErrNotFound)
+		})
+	})
+}
+
+func TestProfileRepository_GetByEmail(t *testing.T) {
+	t.Parallel()
+
+	db := integration.NewPostgres(t)
+	profileRepository := psql.NewProfileRepository(db)
+
+	t.Run("success get by email", func(t *testing.T) {
+		t.Parallel()
+
+		integration.WithinRollback(t, db, func(ctx context.Context) {
+			created := createUser(ctx, t, db)
+
+			got, err := profileRepository.GetByEmail(ctx, strings.ToUpper(created.Email))
+			require.NoError(t, err)
+			require.Equal(t, created.ID, got.ID)
+			require.Equal(t, created.Username, got.Username)
+		})
+	})
+
+	t.Run("no rows error", func(t *testing.T) {
+		t.Parallel()
+
+		integration.WithinRollback(t, db, func(ctx context.Context) {
+			_, err := profileRepository.GetByEmail(ctx, faker.Email())
+			require.ErrorIs(t, err, profile.ErrNotFound)
+		})
+	})
+}
+
+func TestProfileRepository_CheckFollowing(t *testing.T) {
+	t.Parallel()
+
+	db := integration.NewPostgres(t)
+	profileRepository := psql.NewProfileRepository(db)
+
+	t.Run("exist follow", func(t *testing.T) {
+		t.Parallel()
+
+		integration.WithinRollback(t, db, func(ctx context.Context) {
+			followee := createUser(ctx, t, db)
+			follower := createUser(ctx, t, db)
+
+			require.NoError(t, profileRepository.Follow(ctx, followee.ID, follower.ID))
+			require.NoError(t, profileRepository.CheckFollowing(ctx, followee.ID, follower.ID))
+		})
+	})
+
+	t.Run("no rows error", func(t *testing.T) {
+		t.Parallel()
+
+		integration.WithinRollback(t, db, func(ctx context.Context) {
+			followee := createUser(ctx, t, db)
+			follower := createUser(ctx, t, db)
+
+			require.NoError(t, profileRepository.Follow(ctx, followee.ID, follower.ID))
+
+			err := profileRepository.CheckFollowing(ctx, follower.ID, followee.ID)
+			require.ErrorIs(t, err, profile.ErrNotFound)
+		})
+	})
+}
+
+func TestProfileRepository_Follow(t *testing.T) {
+	t.Parallel()
+
+	db := integration.NewPostgres(t)
+	profileRepository := psql.NewProfileRepository(db)
+
+	t.Run("creation follow", func(t *testing.T) {
+		t.Parallel()
+
+		integration.WithinRollback(t, db, func(ctx context.Context) {
+			followee := createUser(ctx, t, db)
+			follower := createUser(ctx, t, db)
+
+			require.NoError(t, profileRepository.Follow(ctx, followee.ID, follower.ID))
+			require.NoError(t, profileRepository.Follow(ctx, followee.ID, follower.ID))
+			require.NoError(t, profileRepository.CheckFollowing(ctx, followee.ID, follower.ID))
+		})
+	})
+
+	t.Run("follow self", func(t *testing.T) {
+		t.Parallel()
+
+		integration.WithinRollback(t, db, func(ctx context.Context) {
+			created := createUser(ctx, t, db)
+
+			err := profileRepository.Follow(ctx, created.ID, created.ID)
+			require.ErrorIs(t, err, profile.ErrCannotFollowSelf)
+		})
+	})
+
+	t.Run("follow blocked", func(t *testing.T) {
+		t.Parallel()
+
+		integration.WithinRollback(t, db, func(ctx context.Context) {
+			followee := createUser(ctx, t, db)
+			follower := createUser(ctx, t, db)
+
+			require.NoError(t, profileRepository.Block(ctx, followee.ID, follower.ID))
+
+			err := profileRepository.Follow(ctx, followee.ID, follower.ID)
+			require.ErrorIs(t, err, profile.ErrFollowBlocked)
+
+			err = profileRepository.RequestFollow(ctx, followee.ID, follower.ID)
+			require.ErrorIs(t, err, profile.ErrFollowBlocked)
+		})
+	})
+}
+
+func TestProfileRepository_Unfollow(t *testing.T) {
+	t.Parallel()
+
+	db := integration.NewPostgres(t)
+	profileRepository := psql.NewProfileRepository(db)
+
+	t.Run("delete follow success", func(t *testing.T) {
+		t.Parallel()
+
+		integration.WithinRollback(t, db, func(ctx context.Context) {
+			followee := createUser(ctx, t, db)
+			follower := createUser(ctx, t, db)
+
+			require.NoError(t, profileRepository.Follow(ctx, followee.ID, follower.ID))
+			require.NoError(t, profileRepository.Unfollow(ctx, followee.ID, follower.ID))
+
+			err := profileRepository.CheckFollowing(ctx, followee.ID, follower.ID)
+			require.ErrorIs(t, err, profile.ErrNotFound)
+		})
+	})
+
+	t.Run("not following", func(t *testing.T) {
+		t.Parallel()
+
+		integration.WithinRollback(t, db, func(ctx context.Context) {
+			followee := createUser(ctx, t, db)
+			follower := createUser(ctx, t, db)
+
+			err := profileRepository.Unfollow(ctx, followee.ID, follower.ID)
+			require.ErrorIs(t, err, profile.ErrNotFollowing)
+		})
+	})
+}
+
+// requireOrdered checks that follows go from the newest to the oldest, follows made at once go by id.
+func requireOrdered(t *testing.T, follows []profile.Follow) {
+	t.Helper()
+
+	for i := 1; i < len(follows); i++ {
+		prev, next := follows[i-1], follows[i]
+		if prev.FollowedAt.Equal(next.FollowedAt) {
+			require.Positive(t, bytes.Compare(prev.Profile.ID[:], next.Profile.ID[:]))
+		} else {
+			require.True(t, prev.FollowedAt.After(next.FollowedAt))
+		}
+	}
+}
+
+func TestProfileRepository_ListFollowers(t *testing.T) {
+	t.Parallel()
+
+	db := integration.NewPostgres(t)
+	profileRepository := psql.NewProfileRepository(db)
+
+	t.Run("success list followers", func(t *testing.T) {
+		t.Parallel()
+
+		integration.WithinRollback(t, db, func(ctx context.Context) {
+			followee := createUser(ctx, t, db)
+			viewer := createUser(ctx, t, db)
+			followed := createUser(ctx, t, db)
+			blocked := createUser(ctx, t, db)
+			muted := createUser(ctx, t, db)
+			blocking := createUser(ctx, t, db)
+
+			for _, follower := range []uuid.UUID{followed.ID, blocked.ID, muted.ID, blocking.ID} {
+				require.NoError(t, profileRepository.Follow(ctx, followee.ID, follower))
+			}
+
+			require.NoError(t, profileRepository.Follow(ctx, followed.ID, viewer.ID))
+			require.NoError(t, profileRepository.Block(ctx, viewer.ID, blocked.ID))
+			require.NoError(t, profileRepository.Mute(ctx, viewer.ID, muted.ID))
+			require.NoError(t, profileRepository.Block(ctx, blocking.ID, viewer.ID))
+
+			dto := profile.ListFollowsDTO{ProfileID: followee.ID, ViewerID: viewer.ID, Limit: 2}
+
+			firstPage, err := profileRepository.ListFollowers(ctx, dto)
+			require.NoError(t, err)
+			require.Len(t, firstPage, 2)
+
+			last := firstPage[len(firstPage)-1]
+			dto.After = &profile.Cursor{FollowedAt: last.FollowedAt, ID: last.Profile.ID}
+
+			secondPage, err := profileRepository.ListFollowers(ctx, dto)
+			require.NoError(t, err)
+			require.Len(t, secondPage, 1)
+
+			follows := append(firstPage, secondPage...)
+			requireOrdered(t, follows)
+
+			got := make(map[uuid.UUID]profile.Profile, len(follows))
+			for _, follow := range follows {
+				require.False(t, follow.FollowedAt.IsZero())
+				got[follow.Profile.ID] = follow.Profile
+			}
+
+			require.Len(t, got, 3)
+			require.True(t, got[followed.ID].Following)
+			require.True(t, got[blocked.ID].Blocking)
+			require.True(t, got[muted.ID].Muting)
+			require.Equal(t, muted.Username, got[muted.ID].Username)
+		})
+	})
+}
+
+func TestProfileRepository_ListFollowing(t *testing.T) {
+	t.Parallel()
+
+	db := integration.NewPostgres(t)
+	profileRepository := psql.NewProfileRepository(db)
+	userRepository := psql.NewUserRepository(db)
+
+	t.Run("success list following", func(t *testing.T) {
+		t.Parallel()
+
+		integration.WithinRollback(t, db, func(ctx context.Context) {
+			follower := createUser(ctx, t, db)
+			followee := createUser(ctx, t, db)
+			deleted := createUser(ctx, t, db)
+
+			require.NoError(t, profileRepository.Follow(ctx, followee.ID, follower.ID))
+			require.NoError(t, profileRepository.Follow(ctx, deleted.ID, follower.ID))
+			require.NoError(t, userRepository.DeleteByEmail(ctx, deleted.Email, time.Now()))
+
+			following, err := profileRepository.ListFollowing(ctx, profile.ListFollowsDTO{
+				ProfileID: follower.ID,
+				Limit:     10,
+			})
+			require.NoError(t, err)
+			require.Len(t, following, 1)
+			require.Equal(t, followee.ID, following[0].Profile.ID)
+			require.False(t, following[0].Profile.Following)
+		})
+	})
+}
+
+func TestProfileRepository_GetRestrictions(t *testing.T) {
+	t.Parallel()
+
+	db := integration.NewPostgres(t)
+	profileRepository := psql.NewProfileRepository(db)
+
+	t.Run("success get restrictions", func(t *testing.T) {
+		t.Parallel()
+
+		integration.WithinRollback(t, db, func(ctx context.Context) {
+			viewer := createUser(ctx, t, db)
+			created := createUser(ctx, t, db)
+
+			restrictions, err := profileRepository.GetRestrictions(ctx, created.ID, viewer.ID)
+			require.NoError(t, err)
+			require.Equal(t, profile.Restrictions{}, restrictions)
+
+			require.NoError(t, profileRepository.Mute(ctx, viewer.ID, created.ID))
+			require.NoError(t, profileRepository.RequestFollow(ctx, created.ID, viewer.ID))
+
+			restrictions, err = profileRepository.GetRestrictions(ctx, created.ID, viewer.ID)
+			require.NoError(t, err)
+			require.Equal(t, profile.Restrictions{Muting: true, Requested: true}, restrictions)
+
+			require.NoError(t, profileRepository.Block(ctx, created.ID, viewer.ID))
+
+			restrictions, err = profileRepository.GetRestrictions(ctx, created.ID, viewer.ID)
+			require.NoError(t, err)
+			require.Equal(t, profile.Restrictions{BlockedBy: true, Muting: true}, restrictions)
+		})
+	})
+}
+
+func TestProfileRepository_Block(t *testing.T) {
+	t.Parallel()
+
+	db := integration.NewPostgres(t)
+	profileRepository := psql.NewProfileRepository(db)
+
+	t.Run("success block", func(t *testing.T) {
+		t.Parallel()
+
+		integration.WithinRollback(t, db, func(ctx context.Context) {
+			blocker := createUser(ctx, t, db)
+			blocked := createUser(ctx, t, db)
+
+			require.NoError(t, profileRepository.Follow(ctx, blocker.ID, blocked.ID))
+			require.NoError(t, profileRepository.Follow(ctx, blocked.ID, blocker.ID))
+			require.NoError(t, profileRepository.RequestFollow(ctx, blocker.ID, blocked.ID))
+			require.NoError(t, profileRepository.Block(ctx, blocker.ID, blocked.ID))
+			require.NoError(t, profileRepository.Block(ctx, blocker.ID, blocked.ID))
+
+			for _, pair := range [][2]uuid.UUID{{blocker.ID, blocked.ID}, {blocked.ID, blocker.ID}} {
+				err := profileRepository.CheckFollowing(ctx, pair[0], pair[1])
+				require.ErrorIs(t, err, profile.ErrNotFound)
+			}
+
+			restrictions, err := profileRepository.GetRestrictions(ctx, blocked.ID, blocker.ID)
+			require.NoError(t, err)
+			require.Equal(t, profile.Restrictions{Blocking: true}, restrictions)
+
+			require.NoError(t, profileRepository.Unblock(ctx, blocker.ID, blocked.ID))
+
+			err = profileRepository.Unblock(ctx, blocker.ID, blocked.ID)
+			require.ErrorIs(t, err, profile.ErrNotBlocking)
+		})
+	})
+
+	t.Run("block self", func(t *testing.T) {
+		t.Parallel()
+
+		integration.WithinRollback(t, db, func(ctx context.Context) {
+			created := createUser(ctx, t, db)
+
+			err := profileRepository.Block(ctx, created.ID, created.ID)
+			require.ErrorIs(t, err, profile.ErrCannotBlockSelf)
+		})
+	})
+}
+
+func TestProfileRepository_Unmute(t *testing.T) {
+	t.Parallel()
+
+	db := integration.NewPostgres(t)
+	profileRepository := psql.NewProfileRepository(db)
+
+	t.Run("success unmute", func(t *testing.T) {
+		t.Parallel()
+
+		integration.WithinRollback(t, db, func(ctx context.Context) {
+			muter := createUser(ctx, t, db)
+			muted := createUser(ctx, t, db)
+
+			require.NoError(t, profileRepository.Mute(ctx, muter.ID, muted.ID))
+			require.NoError(t, profileRepository.Unmute(ctx, muter.ID, muted.ID))
+
+			restrictions, err := profileRepository.GetRestrictions(ctx, muted.ID, muter.ID)
+			require.NoError(t, err)
+			require.False(t, restrictions.Muting)
+		})
+	})
+
+	t.Run("not muting", func(t *testing.T) {
+		t.Parallel()
+
+		integration.WithinRollback(t, db, func(ctx context.Context) {
+			muter := createUser(ctx, t, db)
+			muted := createUser(ctx, t, db)
+
+			err := profileRepository.Unmute(ctx, muter.ID, muted.ID)
+			require.ErrorIs(t, err, profile.ErrNotMuting)
+		})
+	})
+}
+
+func TestProfileRepository_ApproveFollowRequest(t *testing.T) {
+	t.Parallel()
+
+	db := integration.NewPostgres(t)
+	profileRepository := psql.NewProfileRepository(db)
+
+	t.Run("success approve", func(t *testing.T) {
+		t.Parallel()
+
+		integration.WithinRollback(t, db, func(ctx context.Context) {
+			followee := createUser(ctx, t, db)
+			follower := createUser(ctx, t, db)
+
+			require.NoError(t, profileRepository.RequestFollow(ctx, followee.ID, follower.ID))
+			require.NoError(t, profileRepository.ApproveFollowRequest(ctx, followee.ID, follower.ID))
+			require.NoError(t, profileRepository.CheckFollowing(ctx, followee.ID, follower.ID))
+
+			restrictions, err := profileRepository.GetRestrictions(ctx, followee.ID, follower.ID)
+			require.NoError(t, err)
+			require.False(t, restrictions.Requested)
+		})
+	})
+
+	t.Run("no follow request", func(t *testing.T) {
+		t.Parallel()
+
+		integration.WithinRollback(t, db, func(ctx context.Context) {
+			followee := createUser(ctx, t, db)
+			follower := createUser(ctx, t, db)
+
+			err := profileRepository.ApproveFollowRequest(ctx, followee.ID, follower.ID)
+			require.ErrorIs(t, err, profile.ErrFollowRequestNotFound)
+		})
+	})
+}
+
+func TestProfileRepository_ListFollowRequests(t *testing.T) {
+	t.Parallel()
+
+	db := integration.NewPostgres(t)
+	profileRepository := psql.NewProfileRepository(db)
+
+	t.Run("success list follow requests", func(t *testing.T) {
+		t.Parallel()
+
+		integration.WithinRollback(t, db, func(ctx context.Context) {
+			followee := createUser(ctx, t, db)
+			requested := createUser(ctx, t, db)
+			rejected := createUser(ctx, t, db)
+
+			require.NoError(t, profileRepository.RequestFollow(ctx, followee.ID, requested.ID))
+			require.NoError(t, profileRepository.RequestFollow(ctx, followee.ID, rejected.ID))
+			require.NoError(t, profileRepository.DeleteFollowRequest(ctx, followee.ID, rejected.ID))
+
+			requests, err := profileRepository.ListFollowRequests(ctx, profile.ListFollowsDTO{
+				ProfileID: followee.ID,
+				ViewerID:  followee.ID,
+				Limit:     10,
+			})
+			require.NoError(t, err)
+			require.Len(t, requests, 1)
+			require.Equal(t, requested.ID, requests[0].Profile.ID)
+			require.Equal(t, requested.Username, requests[0].Profile.Username)
+
+			err = profileRepository.DeleteFollowRequest(ctx, followee.ID, rejected.ID)
+			require.ErrorIs(t, err, profile.ErrFollowRequestNotFound)
+		})
+	})
+}
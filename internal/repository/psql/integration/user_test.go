@@ -0,0 +1,466 @@
+package integration_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bxcodec/faker/v3"
+	"github.com/google/uuid"
+	"github.com/maypok86/conduit/internal/domain/profile"
+	"github.com/maypok86/conduit/internal/domain/user"
+	"github.com/maypok86/conduit/internal/repository/psql"
+	"github.com/maypok86/conduit/internal/repository/psql/integration"
+	"github.com/maypok86/conduit/pkg/postgres"
+	"github.com/stretchr/testify/require"
+)
+
+func createUser(ctx context.Context, t *testing.T, db *postgres.Postgres) user.User {
+	t.Helper()
+
+	u, err := psql.NewUserRepository(db).Create(ctx, user.User{
+		Username: faker.Username(),
+		Email:    faker.Email(),
+		Password: faker.Password(),
+	})
+	require.NoError(t, err)
+
+	return u
+}
+
+func TestUserRepository_Create(t *testing.T) {
+	t.Parallel()
+
+	db := integration.NewPostgres(t)
+	userRepository := psql.NewUserRepository(db)
+
+	t.Run("creation user", func(t *testing.T) {
+		t.Parallel()
+
+		integration.WithinRollback(t, db, func(ctx context.Context) {
+			dto := user.User{
+				Username: faker.Username(),
+				Email:    faker.Email(),
+				Password: faker.Password(),
+			}
+
+			created, err := userRepository.Create(ctx, dto)
+			require.NoError(t, err)
+			require.NotEqual(t, uuid.Nil, created.ID)
+
+			got, err := userRepository.GetByEmail(ctx, dto.Email)
+			require.NoError(t, err)
+			require.Equal(t, created.ID, got.ID)
+			require.Equal(t, dto.Username, got.Username)
+			require.Equal(t, dto.Password, got.Password)
+		})
+	})
+
+	t.Run("username is taken", func(t *testing.T) {
+		t.Parallel()
+
+		integration.WithinRollback(t, db, func(ctx context.Context) {
+			taken := createUser(ctx, t, db)
+
+			_, err := userRepository.Create(ctx, user.User{
+				Username: strings.ToUpper(taken.Username),
+				Email:    faker.Email(),
+				Password: faker.Password(),
+			})
+			require.ErrorIs(t, err, user.ErrUsernameAlreadyExist)
+		})
+	})
+
+	t.Run("email is taken", func(t *testing.T) {
+		t.Parallel()
+
+		integration.WithinRollback(t, db, func(ctx context.Context) {
+			taken := createUser(ctx, t, db)
+
+			_, err := userRepository.Create(ctx, user.User{
+				Username: faker.Username(),
+				Email:    strings.ToUpper(taken.Email),
+				Password: faker.Password(),
+			})
+			require.ErrorIs(t, err, user.ErrEmailAlreadyExist)
+		})
+	})
+}
+
+func TestUserRepository_GetByEmail(t *testing.T) {
+	t.Parallel()
+
+	db := integration.NewPostgres(t)
+	userRepository := psql.NewUserRepository(db)
+
+	t.Run("success get by email", func(t *testing.T) {
+		t.Parallel()
+
+		integration.WithinRollback(t, db, func(ctx context.Context) {
+			created := createUser(ctx, t, db)
+
+			got, err := userRepository.GetByEmail(ctx, strings.ToUpper(created.Email))
+			require.NoError(t, err)
+			require.Equal(t, created.ID, got.ID)
+			require.Equal(t, created.Username, got.Username)
+			require.Equal(t, created.Email, got.Email)
+			require.Equal(t, created.Password, got.Password)
+			require.Nil(t, got.Bio)
+			require.Nil(t, got.Image)
+			require.False(t, got.Private)
+			require.False(t, got.CreatedAt.IsZero())
+			require.False(t, got.UpdatedAt.IsZero())
+			require.Nil(t, got.DeletedAt)
+		})
+	})
+
+	t.Run("no rows error", func(t *testing.T) {
+		t.Parallel()
+
+		integration.WithinRollback(t, db, func(ctx context.Context) {
+			_, err := userRepository.GetByEmail(ctx, faker.Email())
+			require.ErrorIs(t, err, user.ErrNotFound)
+		})
+	})
+}
+
+func TestUserRepository_UpdateByEmail(t *testing.T) {
+	t.Parallel()
+
+	db := integration.NewPostgres(t)
+	userRepository := psql.NewUserRepository(db)
+
+	t.Run("success update by email", func(t *testing.T) {
+		t.Parallel()
+
+		integration.WithinRollback(t, db, func(ctx context.Context) {
+			created := createUser(ctx, t, db)
+			bio := faker.Sentence()
+			image := faker.URL()
+			private := true
+			email := faker.Email()
+			updatedAt := time.Now().UTC().Truncate(time.Microsecond)
+
+			updated, err := userRepository.UpdateByEmail(ctx, strings.ToUpper(created.Email), user.UpdateDTO{
+				Email:     &email,
+				Bio:       &bio,
+				Image:     &image,
+				Private:   &private,
+				UpdatedAt: updatedAt,
+			})
+			require.NoError(t, err)
+			require.Equal(t, created.ID, updated.ID)
+			require.Equal(t, created.Username, updated.Username)
+			require.Equal(t, email, updated.Email)
+			require.Equal(t, created.Password, updated.Password)
+			require.Equal(t, bio, updated.GetBio())
+			require.Equal(t, image, updated.GetImage())
+			require.True(t, updated.Private)
+
+			got, err := userRepository.GetByEmail(ctx, email)
+			require.NoError(t, err)
+			require.Equal(t, updated.ID, got.ID)
+			require.Equal(t, bio, got.GetBio())
+			require.Equal(t, image, got.GetImage())
+			require.True(t, got.Private)
+			require.True(t, updatedAt.Equal(got.UpdatedAt))
+		})
+	})
+
+	t.Run("no rows error", func(t *testing.T) {
+		t.Parallel()
+
+		integration.WithinRollback(t, db, func(ctx context.Context) {
+			_, err := userRepository.UpdateByEmail(ctx, faker.Email(), user.UpdateDTO{UpdatedAt: time.Now()})
+			require.ErrorIs(t, err, user.ErrNotFound)
+		})
+	})
+}
+
+func TestUserRepository_UpdateByEmailRename(t *testing.T) {
+	t.Parallel()
+
+	db := integration.NewPostgres(t)
+	userRepository := psql.NewUserRepository(db)
+
+	t.Run("success rename", func(t *testing.T) {
+		t.Parallel()
+
+		integration.WithinRollback(t, db, func(ctx context.Context) {
+			created := createUser(ctx, t, db)
+			other := createUser(ctx, t, db)
+			username := faker.Username()
+			renamedAt := time.Now().UTC().Truncate(time.Second)
+
+			updated, err := userRepository.UpdateByEmail(ctx, created.Email, user.UpdateDTO{
+				Username:              &username,
+				UpdatedAt:             renamedAt,
+				UsernameCooldownUntil: renamedAt.Add(time.Hour),
+			})
+			require.NoError(t, err)
+			require.Equal(t, username, updated.Username)
+
+			renames, err := userRepository.CountRenames(ctx, created.ID, renamedAt)
+			require.NoError(t, err)
+			require.Equal(t, 1, renames)
+
+			err = userRepository.CheckUsernameQuarantine(ctx, created.Username, other.ID, renamedAt)
+			require.ErrorIs(t, err, user.ErrUsernameQuarantined)
+			require.NoError(t, userRepository.CheckUsernameQuarantine(ctx, created.Username, created.ID, renamedAt))
+		})
+	})
+
+	t.Run("username is taken", func(t *testing.T) {
+		t.Parallel()
+
+		integration.WithinRollback(t, db, func(ctx context.Context) {
+			created := createUser(ctx, t, db)
+			taken := strings.ToUpper(createUser(ctx, t, db).Username)
+
+			_, err := userRepository.UpdateByEmail(ctx, created.Email, user.UpdateDTO{
+				Username:  &taken,
+				UpdatedAt: time.Now(),
+			})
+			require.ErrorIs(t, err, user.ErrUsernameAlreadyExist)
+		})
+	})
+
+	t.Run("email is taken", func(t *testing.T) {
+		t.Parallel()
+
+		integration.WithinRollback(t, db, func(ctx context.Context) {
+			created := createUser(ctx, t, db)
+			taken := strings.ToUpper(createUser(ctx, t, db).Email)
+
+			_, err := userRepository.UpdateByEmail(ctx, created.Email, user.UpdateDTO{
+				Email:     &taken,
+				UpdatedAt: time.Now(),
+			})
+			require.ErrorIs(t, err, user.ErrEmailAlreadyExist)
+		})
+	})
+}
+
+func TestUserRepository_DeleteByEmail(t *testing.T) {
+	t.Parallel()
+
+	db := integration.NewPostgres(t)
+	userRepository := psql.NewUserRepository(db)
+
+	t.Run("success delete by email", func(t *testing.T) {
+		t.Parallel()
+
+		integration.WithinRollback(t, db, func(ctx context.Context) {
+			created := createUser(ctx, t, db)
+			deletedAt := time.Now().UTC().Truncate(time.Microsecond)
+
+			require.NoError(t, userRepository.DeleteByEmail(ctx, strings.ToUpper(created.Email), deletedAt))
+
+			got, err := userRepository.GetByEmail(ctx, created.Email)
+			require.NoError(t, err)
+			require.NotNil(t, got.DeletedAt)
+			require.True(t, deletedAt.Equal(*got.DeletedAt))
+		})
+	})
+
+	t.Run("not found error", func(t *testing.T) {
+		t.Parallel()
+
+		integration.WithinRollback(t, db, func(ctx context.Context) {
+			created := createUser(ctx, t, db)
+
+			require.NoError(t, userRepository.DeleteByEmail(ctx, created.Email, time.Now()))
+
+			err := userRepository.DeleteByEmail(ctx, created.Email, time.Now())
+			require.ErrorIs(t, err, user.ErrNotFound)
+		})
+	})
+}
+
+func TestUserRepository_Restore(t *testing.T) {
+	t.Parallel()
+
+	db := integration.NewPostgres(t)
+	userRepository := psql.NewUserRepository(db)
+
+	t.Run("success restore", func(t *testing.T) {
+		t.Parallel()
+
+		integration.WithinRollback(t, db, func(ctx context.Context) {
+			created := createUser(ctx, t, db)
+
+			require.NoError(t, userRepository.DeleteByEmail(ctx, created.Email, time.Now()))
+			require.NoError(t, userRepository.Restore(ctx, created.ID))
+
+			got, err := userRepository.GetByEmail(ctx, created.Email)
+			require.NoError(t, err)
+			require.False(t, got.IsDeleted())
+		})
+	})
+
+	t.Run("already purged", func(t *testing.T) {
+		t.Parallel()
+
+		integration.WithinRollback(t, db, func(ctx context.Context) {
+			created := createUser(ctx, t, db)
+			now := time.Now().UTC()
+
+			require.NoError(t, userRepository.DeleteByEmail(ctx, created.Email, now.Add(-time.Hour)))
+
+			_, err := userRepository.Purge(ctx, user.PurgeDTO{
+				Mode:             user.DeletionModeAnonymize,
+				DeletedBefore:    now,
+				QuarantinedUntil: now.Add(time.Hour),
+				PurgedAt:         now,
+			})
+			require.NoError(t, err)
+
+			err = userRepository.Restore(ctx, created.ID)
+			require.ErrorIs(t, err, user.ErrNotFound)
+		})
+	})
+
+	t.Run("not found error", func(t *testing.T) {
+		t.Parallel()
+
+		integration.WithinRollback(t, db, func(ctx context.Context) {
+			err := userRepository.Restore(ctx, uuid.New())
+			require.ErrorIs(t, err, user.ErrNotFound)
+		})
+	})
+}
+
+func TestUserRepository_Purge(t *testing.T) {
+	t.Parallel()
+
+	db := integration.NewPostgres(t)
+	userRepository := psql.NewUserRepository(db)
+	profileRepository := psql.NewProfileRepository(db)
+
+	for _, mode := range []user.DeletionMode{user.DeletionModeAnonymize, user.DeletionModeCascade} {
+		mode := mode
+
+		t.Run(string(mode), func(t *testing.T) {
+			t.Parallel()
+
+			integration.WithinRollback(t, db, func(ctx context.Context) {
+				expired := createUser(ctx, t, db)
+				recent := createUser(ctx, t, db)
+				follower := createUser(ctx, t, db)
+				now := time.Now().UTC()
+
+				require.NoError(t, profileRepository.Follow(ctx, expired.ID, follower.ID))
+				require.NoError(t, userRepository.DeleteByEmail(ctx, expired.Email, now.Add(-time.Hour)))
+				require.NoError(t, userRepository.DeleteByEmail(ctx, recent.Email, now))
+
+				purged, err := userRepository.Purge(ctx, user.PurgeDTO{
+					Mode:             mode,
+					DeletedBefore:    now.Add(-time.Minute),
+					QuarantinedUntil: now.Add(time.Hour),
+					PurgedAt:         now,
+				})
+				require.NoError(t, err)
+				require.Equal(t, int64(1), purged)
+
+				_, err = userRepository.GetByEmail(ctx, expired.Email)
+				require.ErrorIs(t, err, user.ErrNotFound)
+
+				_, err = userRepository.GetByEmail(ctx, recent.Email)
+				require.NoError(t, err)
+
+				err = profileRepository.CheckFollowing(ctx, expired.ID, follower.ID)
+				require.ErrorIs(t, err, profile.ErrNotFound)
+
+				err = userRepository.CheckUsernameQuarantine(ctx, expired.Username, expired.ID, now)
+				require.ErrorIs(t, err, user.ErrUsernameQuarantined)
+			})
+		})
+	}
+
+	t.Run("unknown mode", func(t *testing.T) {
+		t.Parallel()
+
+		integration.WithinRollback(t, db, func(ctx context.Context) {
+			_, err := userRepository.Purge(ctx, user.PurgeDTO{Mode: "unknown"})
+			require.ErrorIs(t, err, user.ErrUnknownDeletionMode)
+		})
+	})
+}
+
+func TestUserRepository_CheckUsernameQuarantine(t *testing.T) {
+	t.Parallel()
+
+	db := integration.NewPostgres(t)
+	userRepository := psql.NewUserRepository(db)
+
+	t.Run("username is available", func(t *testing.T) {
+		t.Parallel()
+
+		integration.WithinRollback(t, db, func(ctx context.Context) {
+			require.NoError(t, userRepository.CheckUsernameQuarantine(ctx, faker.Username(), uuid.New(), time.Now()))
+		})
+	})
+
+	t.Run("username is quarantined", func(t *testing.T) {
+		t.Parallel()
+
+		integration.WithinRollback(t, db, func(ctx context.Context) {
+			created := createUser(ctx, t, db)
+			username := faker.Username()
+			now := time.Now().UTC()
+
+			_, err := userRepository.UpdateByEmail(ctx, created.Email, user.UpdateDTO{
+				Username:              &username,
+				UpdatedAt:             now,
+				UsernameCooldownUntil: now.Add(time.Hour),
+			})
+			require.NoError(t, err)
+
+			err = userRepository.CheckUsernameQuarantine(ctx, strings.ToUpper(created.Username), uuid.New(), now)
+			require.ErrorIs(t, err, user.ErrUsernameQuarantined)
+
+			require.NoError(t, userRepository.CheckUsernameQuarantine(ctx, created.Username, created.ID, now))
+			require.NoError(t, userRepository.CheckUsernameQuarantine(
+				ctx,
+				created.Username,
+				uuid.New(),
+				now.Add(time.Hour),
+			))
+		})
+	})
+}
+
+func TestUserRepository_CountRenames(t *testing.T) {
+	t.Parallel()
+
+	db := integration.NewPostgres(t)
+	userRepository := psql.NewUserRepository(db)
+
+	t.Run("success count renames", func(t *testing.T) {
+		t.Parallel()
+
+		integration.WithinRollback(t, db, func(ctx context.Context) {
+			created := createUser(ctx, t, db)
+			now := time.Now().UTC().Truncate(time.Second)
+
+			for i, username := range []string{faker.Username(), faker.Username()} {
+				username := username
+
+				_, err := userRepository.UpdateByEmail(ctx, created.Email, user.UpdateDTO{
+					Username:  &username,
+					UpdatedAt: now.Add(time.Duration(i) * time.Hour),
+				})
+				require.NoError(t, err)
+			}
+
+			renames, err := userRepository.CountRenames(ctx, created.ID, now)
+			require.NoError(t, err)
+			require.Equal(t, 2, renames)
+
+			renames, err = userRepository.CountRenames(ctx, created.ID, now.Add(time.Minute))
+			require.NoError(t, err)
+			require.Equal(t, 1, renames)
+		})
+	})
+}
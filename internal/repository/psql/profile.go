@@ -4,9 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/google/uuid"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgerrcode"
 	"github.com/jackc/pgx/v4"
 	"github.com/maypok86/conduit/internal/domain/profile"
 	"github.com/maypok86/conduit/pkg/logger"
@@ -14,6 +17,15 @@ import (
 	"go.uber.org/zap"
 )
 
+const (
+	// followersCountColumn counts followers of users row, follows of deleted users are not counted.
+	followersCountColumn = `(SELECT count(*) FROM follows JOIN users AS follower ON follower.id = follows.follower_id
+	WHERE follows.followee_id = users.id AND follower.deleted_at IS NULL) AS followers_count`
+	// followingCountColumn counts users followed by users row, deleted users are not counted.
+	followingCountColumn = `(SELECT count(*) FROM follows JOIN users AS followee ON followee.id = follows.followee_id
+	WHERE follows.follower_id = users.id AND followee.deleted_at IS NULL) AS following_count`
+)
+
 // ProfileRepository is a profile repository.
 type ProfileRepository struct {
 	db *postgres.Postgres
@@ -26,26 +38,37 @@ func NewProfileRepository(db *postgres.Postgres) ProfileRepository {
 	}
 }
 
-// GetByUsername returns profile by username.
+// GetByUsername returns profile by username. Usernames are compared case-insensitively.
 func (pr ProfileRepository) GetByUsername(ctx context.Context, username string) (profile.Profile, error) {
 	sql, args, err := pr.db.Builder.Select(
 		"id",
+		"username",
 		"bio",
 		"image",
+		"private",
+		followersCountColumn,
+		followingCountColumn,
 		"created_at",
 		"updated_at",
-	).From("users").Where(sq.Eq{"username": username}).Limit(1).ToSql()
+	).From("users").
+		Where(sq.And{sq.Eq{"lower(username)": strings.ToLower(username)}, sq.Eq{"deleted_at": nil}}).
+		Limit(1).ToSql()
 	if err != nil {
 		return profile.Profile{}, fmt.Errorf("can not build select profile by username query: %w", err)
 	}
 
+	ctx = postgres.WithQueryName(ctx, "select_profile_by_username")
 	logger.FromContext(ctx).Debug("select profile by username query", zap.String("sql", sql), zap.Any("args", args))
 
-	p := profile.Profile{Username: username}
-	if err := pr.db.Pool.QueryRow(ctx, sql, args...).Scan(
+	var p profile.Profile
+	if err := pr.db.ReadQuerier(ctx).QueryRow(ctx, sql, args...).Scan(
 		&p.ID,
+		&p.Username,
 		&p.Bio,
 		&p.Image,
+		&p.Private,
+		&p.FollowersCount,
+		&p.FollowingCount,
 		&p.CreatedAt,
 		&p.UpdatedAt,
 	); err != nil {
@@ -59,28 +82,83 @@ func (pr ProfileRepository) GetByUsername(ctx context.Context, username string)
 	return p, nil
 }
 
-// GetByEmail returns profile by username.
+// GetByPreviousUsername returns profile of the user who used the username most recently before renaming.
+func (pr ProfileRepository) GetByPreviousUsername(ctx context.Context, username string) (profile.Profile, error) {
+	sql, args, err := pr.db.Builder.Select(
+		"users.id",
+		"users.username",
+		"users.bio",
+		"users.image",
+		"users.private",
+		followersCountColumn,
+		followingCountColumn,
+		"users.created_at",
+		"users.updated_at",
+	).From("username_history").
+		Join("users ON users.id = username_history.user_id").
+		Where(sq.And{sq.Eq{"lower(username_history.username)": strings.ToLower(username)}, sq.Eq{"users.deleted_at": nil}}).
+		OrderBy("username_history.renamed_at DESC").
+		Limit(1).ToSql()
+	if err != nil {
+		return profile.Profile{}, fmt.Errorf("can not build select profile by previous username query: %w", err)
+	}
+
+	ctx = postgres.WithQueryName(ctx, "select_profile_by_previous_username")
+	logger.FromContext(ctx).Debug(
+		"select profile by previous username query",
+		zap.String("sql", sql),
+		zap.Any("args", args),
+	)
+
+	var p profile.Profile
+	if err := pr.db.ReadQuerier(ctx).QueryRow(ctx, sql, args...).Scan(
+		&p.ID,
+		&p.Username,
+		&p.Bio,
+		&p.Image,
+		&p.Private,
+		&p.FollowersCount,
+		&p.FollowingCount,
+		&p.CreatedAt,
+		&p.UpdatedAt,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return profile.Profile{}, fmt.Errorf("can not find profile by previous username: %w", profile.ErrNotFound)
+		}
+
+		return profile.Profile{}, fmt.Errorf("can not find profile by previous username: %w", err)
+	}
+
+	return p, nil
+}
+
+// GetByEmail returns profile by email.
 func (pr ProfileRepository) GetByEmail(ctx context.Context, email string) (profile.Profile, error) {
 	sql, args, err := pr.db.Builder.Select(
 		"id",
 		"username",
 		"bio",
 		"image",
+		"private",
 		"created_at",
 		"updated_at",
-	).From("users").Where(sq.Eq{"email": email}).Limit(1).ToSql()
+	).From("users").
+		Where(sq.And{sq.Eq{"lower(email)": strings.ToLower(email)}, sq.Eq{"deleted_at": nil}}).
+		Limit(1).ToSql()
 	if err != nil {
 		return profile.Profile{}, fmt.Errorf("can not build select profile by email query: %w", err)
 	}
 
-	logger.FromContext(ctx).Debug("select profile by username query", zap.String("sql", sql), zap.Any("args", args))
+	ctx = postgres.WithQueryName(ctx, "select_profile_by_email")
+	logger.FromContext(ctx).Debug("select profile by email query", zap.String("sql", sql), zap.Any("args", args))
 
 	var p profile.Profile
-	if err := pr.db.Pool.QueryRow(ctx, sql, args...).Scan(
+	if err := pr.db.ReadQuerier(ctx).QueryRow(ctx, sql, args...).Scan(
 		&p.ID,
 		&p.Username,
 		&p.Bio,
 		&p.Image,
+		&p.Private,
 		&p.CreatedAt,
 		&p.UpdatedAt,
 	); err != nil {
@@ -103,9 +181,10 @@ func (pr ProfileRepository) CheckFollowing(ctx context.Context, followeeID, foll
 		return fmt.Errorf("can not build check following query: %w", err)
 	}
 
+	ctx = postgres.WithQueryName(ctx, "check_following")
 	logger.FromContext(ctx).Debug("check following query", zap.String("sql", sql), zap.Any("args", args))
 
-	if err := pr.db.Pool.QueryRow(ctx, sql, args...).Scan(&followeeID, &followerID); err != nil {
+	if err := pr.db.ReadQuerier(ctx).QueryRow(ctx, sql, args...).Scan(&followeeID, &followerID); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return fmt.Errorf("can not check following: %w", profile.ErrNotFound)
 		}
@@ -116,26 +195,61 @@ func (pr ProfileRepository) CheckFollowing(ctx context.Context, followeeID, foll
 	return nil
 }
 
-// Follow adds follow relationship.
+// notBlockedSQL selects the followee $1 and the follower $2 unless one of them blocks the other one. The check
+// is part of the insert, so a block committed after the restrictions are read can not be followed anyway.
+const notBlockedSQL = `SELECT $1::uuid, $2::uuid WHERE NOT EXISTS (
+	SELECT 1 FROM blocks WHERE (blocker_id = $1 AND blocked_id = $2) OR (blocker_id = $2 AND blocked_id = $1)
+)`
+
+// followSQL follows the profile unless a block exists and reports whether the user follows the profile.
+const followSQL = `WITH followed AS (
+	INSERT INTO follows (followee_id, follower_id) ` + notBlockedSQL + `
+	ON CONFLICT (followee_id, follower_id) DO NOTHING RETURNING followee_id
+)
+SELECT EXISTS (SELECT 1 FROM followed) OR EXISTS (SELECT 1 FROM follows WHERE followee_id = $1 AND follower_id = $2)`
+
+// Follow adds follow relationship. Following the same profile again is a no-op.
+// It returns profile.ErrFollowBlocked if one of the users blocks the other one.
 func (pr ProfileRepository) Follow(ctx context.Context, followeeID, followerID uuid.UUID) error {
-	sql, args, err := pr.db.Builder.Insert("follows").
-		Columns("followee_id", "follower_id").
-		Values(followeeID, followerID).
-		ToSql()
-	if err != nil {
-		return fmt.Errorf("can not build follow query: %w", err)
+	if err := pr.insertUnlessBlocked(ctx, "follow", followSQL, followeeID, followerID); err != nil {
+		return fmt.Errorf("can not follow: %w", err)
 	}
 
-	logger.FromContext(ctx).Debug("follow query", zap.String("sql", sql), zap.Any("args", args))
+	return nil
+}
+
+// insertUnlessBlocked runs the insert of a follow or a follow request, the query reports whether
+// the relationship exists afterwards.
+func (pr ProfileRepository) insertUnlessBlocked(
+	ctx context.Context,
+	queryName string,
+	sql string,
+	followeeID uuid.UUID,
+	followerID uuid.UUID,
+) error {
+	args := []interface{}{followeeID, followerID}
 
-	if _, err := pr.db.Pool.Exec(ctx, sql, args...); err != nil {
-		return fmt.Errorf("can not follow: %w", err)
+	ctx = postgres.WithQueryName(ctx, queryName)
+	logger.FromContext(ctx).Debug(queryName+" query", zap.String("sql", sql), zap.Any("args", args))
+
+	var exists bool
+	if err := pr.db.Querier(ctx).QueryRow(ctx, sql, args...).Scan(&exists); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.CheckViolation {
+			return profile.ErrCannotFollowSelf
+		}
+
+		return err
+	}
+
+	if !exists {
+		return profile.ErrFollowBlocked
 	}
 
 	return nil
 }
 
-// Unfollow removes follow relationship.
+// Unfollow removes follow relationship. It returns profile.ErrNotFollowing if there is no such relationship.
 func (pr ProfileRepository) Unfollow(ctx context.Context, followeeID, followerID uuid.UUID) error {
 	sql, args, err := pr.db.Builder.Delete("follows").
 		Where(sq.And{sq.Eq{"followee_id": followeeID}, sq.Eq{"follower_id": followerID}}).
@@ -144,11 +258,378 @@ func (pr ProfileRepository) Unfollow(ctx context.Context, followeeID, followerID
 		return fmt.Errorf("can not build unfollow query: %w", err)
 	}
 
+	ctx = postgres.WithQueryName(ctx, "unfollow")
 	logger.FromContext(ctx).Debug("unfollow query", zap.String("sql", sql), zap.Any("args", args))
 
-	if _, err := pr.db.Pool.Exec(ctx, sql, args...); err != nil {
+	commandTag, err := pr.db.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
 		return fmt.Errorf("can not unfollow: %w", err)
 	}
 
+	if commandTag.RowsAffected() == 0 {
+		return fmt.Errorf("can not unfollow: %w", profile.ErrNotFollowing)
+	}
+
 	return nil
 }
+
+// ListFollowers returns users following the profile, from the newest follow to the oldest.
+func (pr ProfileRepository) ListFollowers(ctx context.Context, dto profile.ListFollowsDTO) ([]profile.Follow, error) {
+	sql, args, err := pr.buildListFollowsQuery("follows", "follower_id", "followee_id", dto).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("can not build list followers query: %w", err)
+	}
+
+	ctx = postgres.WithQueryName(ctx, "list_followers")
+	logger.FromContext(ctx).Debug("list followers query", zap.String("sql", sql), zap.Any("args", args))
+
+	follows, err := pr.queryFollows(ctx, sql, args)
+	if err != nil {
+		return nil, fmt.Errorf("can not list followers: %w", err)
+	}
+
+	return follows, nil
+}
+
+// ListFollowing returns users followed by the profile, from the newest follow to the oldest.
+func (pr ProfileRepository) ListFollowing(ctx context.Context, dto profile.ListFollowsDTO) ([]profile.Follow, error) {
+	sql, args, err := pr.buildListFollowsQuery("follows", "followee_id", "follower_id", dto).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("can not build list following query: %w", err)
+	}
+
+	ctx = postgres.WithQueryName(ctx, "list_following")
+	logger.FromContext(ctx).Debug("list following query", zap.String("sql", sql), zap.Any("args", args))
+
+	follows, err := pr.queryFollows(ctx, sql, args)
+	if err != nil {
+		return nil, fmt.Errorf("can not list following: %w", err)
+	}
+
+	return follows, nil
+}
+
+// buildListFollowsQuery selects users joined by listedColumn of table where ownerColumn is the profile.
+// The table is follows or follow_requests. The relationship flags of the viewer are computed in the same query,
+// users blocking the viewer are skipped.
+func (pr ProfileRepository) buildListFollowsQuery(
+	table string,
+	listedColumn string,
+	ownerColumn string,
+	dto profile.ListFollowsDTO,
+) sq.SelectBuilder {
+	conditions := sq.And{
+		sq.Eq{table + "." + ownerColumn: dto.ProfileID},
+		sq.Eq{"users.deleted_at": nil},
+		sq.Expr(
+			"NOT EXISTS (SELECT 1 FROM blocks WHERE blocks.blocker_id = users.id AND blocks.blocked_id = ?)",
+			dto.ViewerID,
+		),
+	}
+	if dto.After != nil {
+		conditions = append(
+			conditions,
+			sq.Expr(fmt.Sprintf("(%s.created_at, users.id) < (?, ?)", table), dto.After.FollowedAt, dto.After.ID),
+		)
+	}
+
+	return pr.db.Builder.Select(
+		"users.id",
+		"users.username",
+		"users.bio",
+		"users.image",
+		"users.private",
+		"users.created_at",
+		"users.updated_at",
+		table+".created_at",
+	).Column(
+		"EXISTS (SELECT 1 FROM follows AS viewer WHERE viewer.followee_id = users.id AND viewer.follower_id = ?)",
+		dto.ViewerID,
+	).Column(
+		"EXISTS (SELECT 1 FROM blocks WHERE blocks.blocked_id = users.id AND blocks.blocker_id = ?)",
+		dto.ViewerID,
+	).Column(
+		"EXISTS (SELECT 1 FROM mutes WHERE mutes.muted_id = users.id AND mutes.muter_id = ?)",
+		dto.ViewerID,
+	).Column(
+		"EXISTS (SELECT 1 FROM follow_requests AS request "+
+			"WHERE request.followee_id = users.id AND request.follower_id = ?)",
+		dto.ViewerID,
+	).From(table).
+		Join(fmt.Sprintf("users ON users.id = %s.%s", table, listedColumn)).
+		Where(conditions).
+		OrderBy(table+".created_at DESC", "users.id DESC").
+		Limit(uint64(dto.Limit))
+}
+
+func (pr ProfileRepository) queryFollows(
+	ctx context.Context,
+	sql string,
+	args []interface{},
+) ([]profile.Follow, error) {
+	rows, err := pr.db.ReadQuerier(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("can not query follows: %w", err)
+	}
+	defer rows.Close()
+
+	follows := make([]profile.Follow, 0)
+
+	for rows.Next() {
+		var follow profile.Follow
+		if err := rows.Scan(
+			&follow.Profile.ID,
+			&follow.Profile.Username,
+			&follow.Profile.Bio,
+			&follow.Profile.Image,
+			&follow.Profile.Private,
+			&follow.Profile.CreatedAt,
+			&follow.Profile.UpdatedAt,
+			&follow.FollowedAt,
+			&follow.Profile.Following,
+			&follow.Profile.Blocking,
+			&follow.Profile.Muting,
+			&follow.Profile.Requested,
+		); err != nil {
+			return nil, fmt.Errorf("can not scan follow: %w", err)
+		}
+
+		follows = append(follows, follow)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("can not read follows: %w", err)
+	}
+
+	return follows, nil
+}
+
+// GetRestrictions returns blocks, mutes and the pending follow request between the viewer and the profile.
+func (pr ProfileRepository) GetRestrictions(
+	ctx context.Context,
+	profileID uuid.UUID,
+	viewerID uuid.UUID,
+) (profile.Restrictions, error) {
+	sql, args, err := pr.db.Builder.Select().
+		Column("EXISTS (SELECT 1 FROM blocks WHERE blocker_id = ? AND blocked_id = ?)", viewerID, profileID).
+		Column("EXISTS (SELECT 1 FROM blocks WHERE blocker_id = ? AND blocked_id = ?)", profileID, viewerID).
+		Column("EXISTS (SELECT 1 FROM mutes WHERE muter_id = ? AND muted_id = ?)", viewerID, profileID).
+		Column("EXISTS (SELECT 1 FROM follow_requests WHERE followee_id = ? AND follower_id = ?)", profileID, viewerID).
+		ToSql()
+	if err != nil {
+		return profile.Restrictions{}, fmt.Errorf("can not build get restrictions query: %w", err)
+	}
+
+	ctx = postgres.WithQueryName(ctx, "get_restrictions")
+	logger.FromContext(ctx).Debug("get restrictions query", zap.String("sql", sql), zap.Any("args", args))
+
+	var restrictions profile.Restrictions
+	if err := pr.db.ReadQuerier(ctx).QueryRow(ctx, sql, args...).Scan(
+		&restrictions.Blocking,
+		&restrictions.BlockedBy,
+		&restrictions.Muting,
+		&restrictions.Requested,
+	); err != nil {
+		return profile.Restrictions{}, fmt.Errorf("can not get restrictions: %w", err)
+	}
+
+	return restrictions, nil
+}
+
+// blockSQL removes follows and follow requests between the users in both directions and blocks the profile.
+const blockSQL = `WITH unfollowed AS (
+	DELETE FROM follows WHERE (followee_id = $1 AND follower_id = $2) OR (followee_id = $2 AND follower_id = $1)
+), unrequested AS (
+	DELETE FROM follow_requests WHERE (followee_id = $1 AND follower_id = $2) OR (followee_id = $2 AND follower_id = $1)
+)
+INSERT INTO blocks (blocker_id, blocked_id) VALUES ($1, $2) ON CONFLICT (blocker_id, blocked_id) DO NOTHING`
+
+// Block blocks the profile and removes follows between the users. Blocking the same profile again is a no-op.
+func (pr ProfileRepository) Block(ctx context.Context, blockerID, blockedID uuid.UUID) error {
+	args := []interface{}{blockerID, blockedID}
+
+	ctx = postgres.WithQueryName(ctx, "block")
+	logger.FromContext(ctx).Debug("block query", zap.String("sql", blockSQL), zap.Any("args", args))
+
+	if _, err := pr.db.Querier(ctx).Exec(ctx, blockSQL, args...); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.CheckViolation {
+			return fmt.Errorf("can not block: %w", profile.ErrCannotBlockSelf)
+		}
+
+		return fmt.Errorf("can not block: %w", err)
+	}
+
+	return nil
+}
+
+// Unblock removes the block. It returns profile.ErrNotBlocking if there is no such block.
+func (pr ProfileRepository) Unblock(ctx context.Context, blockerID, blockedID uuid.UUID) error {
+	sql, args, err := pr.db.Builder.Delete("blocks").
+		Where(sq.And{sq.Eq{"blocker_id": blockerID}, sq.Eq{"blocked_id": blockedID}}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("can not build unblock query: %w", err)
+	}
+
+	ctx = postgres.WithQueryName(ctx, "unblock")
+	logger.FromContext(ctx).Debug("unblock query", zap.String("sql", sql), zap.Any("args", args))
+
+	commandTag, err := pr.db.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("can not unblock: %w", err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return fmt.Errorf("can not unblock: %w", profile.ErrNotBlocking)
+	}
+
+	return nil
+}
+
+// Mute mutes the profile. Muting the same profile again is a no-op.
+func (pr ProfileRepository) Mute(ctx context.Context, muterID, mutedID uuid.UUID) error {
+	sql, args, err := pr.db.Builder.Insert("mutes").
+		Columns("muter_id", "muted_id").
+		Values(muterID, mutedID).
+		Suffix("ON CONFLICT (muter_id, muted_id) DO NOTHING").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("can not build mute query: %w", err)
+	}
+
+	ctx = postgres.WithQueryName(ctx, "mute")
+	logger.FromContext(ctx).Debug("mute query", zap.String("sql", sql), zap.Any("args", args))
+
+	if _, err := pr.db.Querier(ctx).Exec(ctx, sql, args...); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.CheckViolation {
+			return fmt.Errorf("can not mute: %w", profile.ErrCannotMuteSelf)
+		}
+
+		return fmt.Errorf("can not mute: %w", err)
+	}
+
+	return nil
+}
+
+// Unmute removes the mute. It returns profile.ErrNotMuting if there is no such mute.
+func (pr ProfileRepository) Unmute(ctx context.Context, muterID, mutedID uuid.UUID) error {
+	sql, args, err := pr.db.Builder.Delete("mutes").
+		Where(sq.And{sq.Eq{"muter_id": muterID}, sq.Eq{"muted_id": mutedID}}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("can not build unmute query: %w", err)
+	}
+
+	ctx = postgres.WithQueryName(ctx, "unmute")
+	logger.FromContext(ctx).Debug("unmute query", zap.String("sql", sql), zap.Any("args", args))
+
+	commandTag, err := pr.db.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("can not unmute: %w", err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return fmt.Errorf("can not unmute: %w", profile.ErrNotMuting)
+	}
+
+	return nil
+}
+
+// requestFollowSQL requests to follow the profile unless a block exists and reports whether the request exists.
+const requestFollowSQL = `WITH requested AS (
+	INSERT INTO follow_requests (followee_id, follower_id) ` + notBlockedSQL + `
+	ON CONFLICT (followee_id, follower_id) DO NOTHING RETURNING followee_id
+)
+SELECT EXISTS (SELECT 1 FROM requested) OR
+	EXISTS (SELECT 1 FROM follow_requests WHERE followee_id = $1 AND follower_id = $2)`
+
+// RequestFollow creates a pending follow request. Requesting the same profile again is a no-op.
+// It returns profile.ErrFollowBlocked if one of the users blocks the other one.
+func (pr ProfileRepository) RequestFollow(ctx context.Context, followeeID, followerID uuid.UUID) error {
+	if err := pr.insertUnlessBlocked(ctx, "request_follow", requestFollowSQL, followeeID, followerID); err != nil {
+		return fmt.Errorf("can not request follow: %w", err)
+	}
+
+	return nil
+}
+
+// approveFollowRequestSQL turns the follow request into a follow and returns the number of approved requests.
+const approveFollowRequestSQL = `WITH approved AS (
+	DELETE FROM follow_requests WHERE followee_id = $1 AND follower_id = $2 RETURNING followee_id, follower_id
+), followed AS (
+	INSERT INTO follows (followee_id, follower_id) SELECT followee_id, follower_id FROM approved
+	ON CONFLICT (followee_id, follower_id) DO NOTHING
+)
+SELECT count(*) FROM approved`
+
+// ApproveFollowRequest turns the follow request into a follow.
+// It returns profile.ErrFollowRequestNotFound if there is no such request.
+func (pr ProfileRepository) ApproveFollowRequest(ctx context.Context, followeeID, followerID uuid.UUID) error {
+	args := []interface{}{followeeID, followerID}
+
+	ctx = postgres.WithQueryName(ctx, "approve_follow_request")
+	logger.FromContext(ctx).Debug(
+		"approve follow request query",
+		zap.String("sql", approveFollowRequestSQL),
+		zap.Any("args", args),
+	)
+
+	var approved int
+	if err := pr.db.Querier(ctx).QueryRow(ctx, approveFollowRequestSQL, args...).Scan(&approved); err != nil {
+		return fmt.Errorf("can not approve follow request: %w", err)
+	}
+
+	if approved == 0 {
+		return fmt.Errorf("can not approve follow request: %w", profile.ErrFollowRequestNotFound)
+	}
+
+	return nil
+}
+
+// DeleteFollowRequest removes the follow request.
+// It returns profile.ErrFollowRequestNotFound if there is no such request.
+func (pr ProfileRepository) DeleteFollowRequest(ctx context.Context, followeeID, followerID uuid.UUID) error {
+	sql, args, err := pr.db.Builder.Delete("follow_requests").
+		Where(sq.And{sq.Eq{"followee_id": followeeID}, sq.Eq{"follower_id": followerID}}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("can not build delete follow request query: %w", err)
+	}
+
+	ctx = postgres.WithQueryName(ctx, "delete_follow_request")
+	logger.FromContext(ctx).Debug("delete follow request query", zap.String("sql", sql), zap.Any("args", args))
+
+	commandTag, err := pr.db.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("can not delete follow request: %w", err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return fmt.Errorf("can not delete follow request: %w", profile.ErrFollowRequestNotFound)
+	}
+
+	return nil
+}
+
+// ListFollowRequests returns users requesting to follow the profile, from the newest request to the oldest.
+func (pr ProfileRepository) ListFollowRequests(
+	ctx context.Context,
+	dto profile.ListFollowsDTO,
+) ([]profile.Follow, error) {
+	sql, args, err := pr.buildListFollowsQuery("follow_requests", "follower_id", "followee_id", dto).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("can not build list follow requests query: %w", err)
+	}
+
+	ctx = postgres.WithQueryName(ctx, "list_follow_requests")
+	logger.FromContext(ctx).Debug("list follow requests query", zap.String("sql", sql), zap.Any("args", args))
+
+	requests, err := pr.queryFollows(ctx, sql, args)
+	if err != nil {
+		return nil, fmt.Errorf("can not list follow requests: %w", err)
+	}
+
+	return requests, nil
+}
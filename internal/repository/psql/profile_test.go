@@ -4,12 +4,16 @@ import (
 	"context"
 	"errors"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/bxcodec/faker/v3"
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgerrcode"
 	"github.com/jackc/pgx/v4"
 	"github.com/maypok86/conduit/internal/domain/profile"
 	"github.com/maypok86/conduit/internal/repository/psql"
@@ -22,9 +26,16 @@ import (
 
 var errProfileRepository = errors.New("profile repository error")
 
+const (
+	followersCountSQL = "(SELECT count(*) FROM follows JOIN users AS follower ON follower.id = follows.follower_id\n\t" +
+		"WHERE follows.followee_id = users.id AND follower.deleted_at IS NULL) AS followers_count"
+	followingCountSQL = "(SELECT count(*) FROM follows JOIN users AS followee ON followee.id = follows.followee_id\n\t" +
+		"WHERE follows.follower_id = users.id AND followee.deleted_at IS NULL) AS following_count"
+)
+
 func mockProfileRepository(
 	t *testing.T,
-) (psql.ProfileRepository, *mockPsql.MockPgxPool, *mockPsql.MockRow) {
+) (psql.ProfileRepository, *mockPsql.MockPgxPool, *mockPsql.MockRow, *mockPsql.MockRows) {
 	t.Helper()
 
 	mockCtl := gomock.NewController(t)
@@ -32,6 +43,7 @@ func mockProfileRepository(
 
 	mockPgxPool := mockPsql.NewMockPgxPool(mockCtl)
 	mockRow := mockPsql.NewMockRow(mockCtl)
+	mockRows := mockPsql.NewMockRows(mockCtl)
 
 	queryBuilder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 
@@ -42,15 +54,17 @@ func mockProfileRepository(
 
 	profileRepository := psql.NewProfileRepository(db)
 
-	return profileRepository, mockPgxPool, mockRow
+	return profileRepository, mockPgxPool, mockRow, mockRows
 }
 
 func TestProfileRepository_GetByUsername(t *testing.T) {
 	t.Parallel()
 
 	ctx := logger.ContextWithLogger(context.Background(), zap.L())
-	expectedSQL := "SELECT id, bio, image, created_at, updated_at FROM users WHERE username = $1 LIMIT 1"
-	username := faker.Username()
+	queryCtx := postgres.WithQueryName(ctx, "select_profile_by_username")
+	expectedSQL := "SELECT id, username, bio, image, private, " + followersCountSQL + ", " + followingCountSQL +
+		", created_at, updated_at FROM users WHERE (lower(username) = $1 AND deleted_at IS NULL) LIMIT 1"
+	username := strings.ToLower(faker.Username())
 	profileEntity := profile.Profile{
 		Username: username,
 	}
@@ -75,11 +89,19 @@ func TestProfileRepository_GetByUsername(t *testing.T) {
 					gomock.Any(),
 					gomock.Any(),
 					gomock.Any(),
-				).Return(nil).Times(1)
-				pool.EXPECT().QueryRow(ctx, expectedSQL, username).Return(row).Times(1)
+					gomock.Any(),
+					gomock.Any(),
+					gomock.Any(),
+					gomock.Any(),
+				).DoAndReturn(func(dest ...interface{}) error {
+					*dest[1].(*string) = username
+
+					return nil
+				}).Times(1)
+				pool.EXPECT().QueryRow(queryCtx, expectedSQL, username).Return(row).Times(1)
 			},
 			args: args{
-				username: username,
+				username: strings.ToUpper(username),
 			},
 			want: profileEntity,
 		},
@@ -92,8 +114,12 @@ func TestProfileRepository_GetByUsername(t *testing.T) {
 					gomock.Any(),
 					gomock.Any(),
 					gomock.Any(),
+					gomock.Any(),
+					gomock.Any(),
+					gomock.Any(),
+					gomock.Any(),
 				).Return(errProfileRepository).Times(1)
-				pool.EXPECT().QueryRow(ctx, expectedSQL, username).Return(row).Times(1)
+				pool.EXPECT().QueryRow(queryCtx, expectedSQL, username).Return(row).Times(1)
 			},
 			args: args{
 				username: username,
@@ -110,8 +136,12 @@ func TestProfileRepository_GetByUsername(t *testing.T) {
 					gomock.Any(),
 					gomock.Any(),
 					gomock.Any(),
+					gomock.Any(),
+					gomock.Any(),
+					gomock.Any(),
+					gomock.Any(),
 				).Return(pgx.ErrNoRows).Times(1)
-				pool.EXPECT().QueryRow(ctx, expectedSQL, username).Return(row).Times(1)
+				pool.EXPECT().QueryRow(queryCtx, expectedSQL, username).Return(row).Times(1)
 			},
 			args: args{
 				username: username,
@@ -127,7 +157,7 @@ func TestProfileRepository_GetByUsername(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			profileRepository, mockPgxPool, mockRow := mockProfileRepository(t)
+			profileRepository, mockPgxPool, mockRow, _ := mockProfileRepository(t)
 
 			tt.mock(mockRow, mockPgxPool)
 
@@ -138,12 +168,120 @@ func TestProfileRepository_GetByUsername(t *testing.T) {
 	}
 }
 
+func TestProfileRepository_GetByPreviousUsername(t *testing.T) {
+	t.Parallel()
+
+	ctx := logger.ContextWithLogger(context.Background(), zap.L())
+	queryCtx := postgres.WithQueryName(ctx, "select_profile_by_previous_username")
+	expectedSQL := "SELECT users.id, users.username, users.bio, users.image, users.private, " + followersCountSQL + ", " +
+		followingCountSQL + ", users.created_at, users.updated_at FROM username_history " +
+		"JOIN users ON users.id = username_history.user_id " +
+		"WHERE (lower(username_history.username) = $1 AND users.deleted_at IS NULL) " +
+		"ORDER BY username_history.renamed_at DESC LIMIT 1"
+	username := strings.ToLower(faker.Username())
+
+	type args struct {
+		username string
+	}
+
+	tests := []struct {
+		name    string
+		mock    func(*mockPsql.MockRow, *mockPsql.MockPgxPool)
+		args    args
+		want    profile.Profile
+		wantErr bool
+	}{
+		{
+			name: "success get by previous username",
+			mock: func(row *mockPsql.MockRow, pool *mockPsql.MockPgxPool) {
+				row.EXPECT().Scan(
+					gomock.Any(),
+					gomock.Any(),
+					gomock.Any(),
+					gomock.Any(),
+					gomock.Any(),
+					gomock.Any(),
+					gomock.Any(),
+					gomock.Any(),
+					gomock.Any(),
+				).Return(nil).Times(1)
+				pool.EXPECT().QueryRow(queryCtx, expectedSQL, username).Return(row).Times(1)
+			},
+			args: args{
+				username: username,
+			},
+			want: profile.Profile{},
+		},
+		{
+			name: "scan error",
+			mock: func(row *mockPsql.MockRow, pool *mockPsql.MockPgxPool) {
+				row.EXPECT().Scan(
+					gomock.Any(),
+					gomock.Any(),
+					gomock.Any(),
+					gomock.Any(),
+					gomock.Any(),
+					gomock.Any(),
+					gomock.Any(),
+					gomock.Any(),
+					gomock.Any(),
+				).Return(errProfileRepository).Times(1)
+				pool.EXPECT().QueryRow(queryCtx, expectedSQL, username).Return(row).Times(1)
+			},
+			args: args{
+				username: username,
+			},
+			want:    profile.Profile{},
+			wantErr: true,
+		},
+		{
+			name: "no rows error",
+			mock: func(row *mockPsql.MockRow, pool *mockPsql.MockPgxPool) {
+				row.EXPECT().Scan(
+					gomock.Any(),
+					gomock.Any(),
+					gomock.Any(),
+					gomock.Any(),
+					gomock.Any(),
+					gomock.Any(),
+					gomock.Any(),
+					gomock.Any(),
+					gomock.Any(),
+				).Return(pgx.ErrNoRows).Times(1)
+				pool.EXPECT().QueryRow(queryCtx, expectedSQL, username).Return(row).Times(1)
+			},
+			args: args{
+				username: username,
+			},
+			want:    profile.Profile{},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			profileRepository, mockPgxPool, mockRow, _ := mockProfileRepository(t)
+
+			tt.mock(mockRow, mockPgxPool)
+
+			got, err := profileRepository.GetByPreviousUsername(ctx, tt.args.username)
+			require.True(t, (err != nil) == tt.wantErr)
+			require.True(t, reflect.DeepEqual(tt.want, got))
+		})
+	}
+}
+
 func TestProfileRepository_GetByEmail(t *testing.T) {
 	t.Parallel()
 
 	ctx := logger.ContextWithLogger(context.Background(), zap.L())
-	expectedSQL := "SELECT id, username, bio, image, created_at, updated_at FROM users WHERE email = $1 LIMIT 1"
-	email := faker.Email()
+	queryCtx := postgres.WithQueryName(ctx, "select_profile_by_email")
+	expectedSQL := "SELECT id, username, bio, image, private, created_at, updated_at FROM users WHERE (lower(email) = $1 AND deleted_at IS NULL) LIMIT 1" //nolint:lll
+	email := strings.ToLower(faker.Email())
 
 	type args struct {
 		email string
@@ -166,8 +304,9 @@ func TestProfileRepository_GetByEmail(t *testing.T) {
 					gomock.Any(),
 					gomock.Any(),
 					gomock.Any(),
+					gomock.Any(),
 				).Return(nil).Times(1)
-				pool.EXPECT().QueryRow(ctx, expectedSQL, email).Return(row).Times(1)
+				pool.EXPECT().QueryRow(queryCtx, expectedSQL, email).Return(row).Times(1)
 			},
 			args: args{
 				email: email,
@@ -184,8 +323,9 @@ func TestProfileRepository_GetByEmail(t *testing.T) {
 					gomock.Any(),
 					gomock.Any(),
 					gomock.Any(),
+					gomock.Any(),
 				).Return(errProfileRepository).Times(1)
-				pool.EXPECT().QueryRow(ctx, expectedSQL, email).Return(row).Times(1)
+				pool.EXPECT().QueryRow(queryCtx, expectedSQL, email).Return(row).Times(1)
 			},
 			args: args{
 				email: email,
@@ -203,8 +343,9 @@ func TestProfileRepository_GetByEmail(t *testing.T) {
 					gomock.Any(),
 					gomock.Any(),
 					gomock.Any(),
+					gomock.Any(),
 				).Return(pgx.ErrNoRows).Times(1)
-				pool.EXPECT().QueryRow(ctx, expectedSQL, email).Return(row).Times(1)
+				pool.EXPECT().QueryRow(queryCtx, expectedSQL, email).Return(row).Times(1)
 			},
 			args: args{
 				email: email,
@@ -220,7 +361,7 @@ func TestProfileRepository_GetByEmail(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			profileRepository, mockPgxPool, mockRow := mockProfileRepository(t)
+			profileRepository, mockPgxPool, mockRow, _ := mockProfileRepository(t)
 
 			tt.mock(mockRow, mockPgxPool)
 
@@ -235,6 +376,7 @@ func TestProfileRepository_GetWithFollow(t *testing.T) {
 	t.Parallel()
 
 	ctx := logger.ContextWithLogger(context.Background(), zap.L())
+	queryCtx := postgres.WithQueryName(ctx, "check_following")
 	expectedSQL := "SELECT followee_id, follower_id FROM follows WHERE (followee_id = $1 AND follower_id = $2) LIMIT 1"
 	followeeID := uuid.New()
 	followerID := uuid.New()
@@ -259,7 +401,7 @@ func TestProfileRepository_GetWithFollow(t *testing.T) {
 				).Return(nil).Times(1)
 
 				pool.EXPECT().
-					QueryRow(ctx, expectedSQL, followeeID.String(), followerID.String()).
+					QueryRow(queryCtx, expectedSQL, followeeID.String(), followerID.String()).
 					Return(row).
 					Times(1)
 			},
@@ -277,7 +419,7 @@ func TestProfileRepository_GetWithFollow(t *testing.T) {
 				).Return(errProfileRepository).Times(1)
 
 				pool.EXPECT().
-					QueryRow(ctx, expectedSQL, followeeID.String(), followerID.String()).
+					QueryRow(queryCtx, expectedSQL, followeeID.String(), followerID.String()).
 					Return(row).
 					Times(1)
 			},
@@ -296,7 +438,7 @@ func TestProfileRepository_GetWithFollow(t *testing.T) {
 				).Return(pgx.ErrNoRows).Times(1)
 
 				pool.EXPECT().
-					QueryRow(ctx, expectedSQL, followeeID.String(), followerID.String()).
+					QueryRow(queryCtx, expectedSQL, followeeID.String(), followerID.String()).
 					Return(row).
 					Times(1)
 			},
@@ -314,7 +456,7 @@ func TestProfileRepository_GetWithFollow(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			profileRepository, mockPgxPool, mockRow := mockProfileRepository(t)
+			profileRepository, mockPgxPool, mockRow, _ := mockProfileRepository(t)
 
 			tt.mock(mockRow, mockPgxPool)
 
@@ -328,7 +470,68 @@ func TestProfileRepository_Follow(t *testing.T) {
 	t.Parallel()
 
 	ctx := logger.ContextWithLogger(context.Background(), zap.L())
-	expectedSQL := "INSERT INTO follows (followee_id,follower_id) VALUES ($1,$2)"
+	queryCtx := postgres.WithQueryName(ctx, "follow")
+	followeeID := uuid.New()
+	followerID := uuid.New()
+
+	tests := []struct {
+		name       string
+		followerID uuid.UUID
+		exists     bool
+		scanErr    error
+		wantErr    error
+	}{
+		{
+			name:       "creation follow",
+			followerID: followerID,
+			exists:     true,
+		},
+		{
+			name:       "blocked",
+			followerID: followerID,
+			wantErr:    profile.ErrFollowBlocked,
+		},
+		{
+			name:       "scan error",
+			followerID: followerID,
+			scanErr:    errProfileRepository,
+			wantErr:    errProfileRepository,
+		},
+		{
+			name:       "follow self",
+			followerID: followeeID,
+			scanErr:    &pgconn.PgError{Code: pgerrcode.CheckViolation},
+			wantErr:    profile.ErrCannotFollowSelf,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			profileRepository, mockPgxPool, mockRow, _ := mockProfileRepository(t)
+
+			mockPgxPool.EXPECT().QueryRow(queryCtx, gomock.Any(), followeeID, tt.followerID).Return(mockRow).Times(1)
+			mockRow.EXPECT().Scan(gomock.Any()).DoAndReturn(func(dest ...interface{}) error {
+				*dest[0].(*bool) = tt.exists
+
+				return tt.scanErr
+			}).Times(1)
+
+			err := profileRepository.Follow(ctx, followeeID, tt.followerID)
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestProfileRepository_Unfollow(t *testing.T) {
+	t.Parallel()
+
+	ctx := logger.ContextWithLogger(context.Background(), zap.L())
+	queryCtx := postgres.WithQueryName(ctx, "unfollow")
+	expectedSQL := "DELETE FROM follows WHERE (followee_id = $1 AND follower_id = $2)"
 	followeeID := uuid.New()
 	followerID := uuid.New()
 
@@ -344,19 +547,39 @@ func TestProfileRepository_Follow(t *testing.T) {
 		wantErr bool
 	}{
 		{
-			name: "creation follow",
+			name: "delete follow success",
+			mock: func(pool *mockPsql.MockPgxPool) {
+				pool.EXPECT().
+					Exec(queryCtx, expectedSQL, followeeID.String(), followerID.String()).
+					Return(pgconn.CommandTag("DELETE 1"), nil).
+					Times(1)
+			},
+			args: args{
+				followeeID: followeeID,
+				followerID: followerID,
+			},
+		},
+		{
+			name: "not following",
 			mock: func(pool *mockPsql.MockPgxPool) {
-				pool.EXPECT().Exec(ctx, expectedSQL, followeeID, followerID).Return([]byte{}, nil).Times(1)
+				pool.EXPECT().
+					Exec(queryCtx, expectedSQL, followeeID.String(), followerID.String()).
+					Return(pgconn.CommandTag("DELETE 0"), nil).
+					Times(1)
 			},
 			args: args{
 				followeeID: followeeID,
 				followerID: followerID,
 			},
+			wantErr: true,
 		},
 		{
 			name: "exec error",
 			mock: func(pool *mockPsql.MockPgxPool) {
-				pool.EXPECT().Exec(ctx, expectedSQL, followeeID, followerID).Return(nil, errProfileRepository).Times(1)
+				pool.EXPECT().
+					Exec(queryCtx, expectedSQL, followeeID.String(), followerID.String()).
+					Return(nil, errProfileRepository).
+					Times(1)
 			},
 			args: args{
 				followeeID: followeeID,
@@ -372,61 +595,350 @@ func TestProfileRepository_Follow(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			profileRepository, mockPgxPool, _ := mockProfileRepository(t)
+			profileRepository, mockPgxPool, _, _ := mockProfileRepository(t)
 
 			tt.mock(mockPgxPool)
 
-			err := profileRepository.Follow(ctx, tt.args.followeeID, tt.args.followerID)
+			err := profileRepository.Unfollow(ctx, tt.args.followeeID, tt.args.followerID)
 			require.True(t, (err != nil) == tt.wantErr)
 		})
 	}
 }
 
-func TestProfileRepository_Unfollow(t *testing.T) {
+func TestProfileRepository_ListFollowers(t *testing.T) {
 	t.Parallel()
 
 	ctx := logger.ContextWithLogger(context.Background(), zap.L())
-	expectedSQL := "DELETE FROM follows WHERE (followee_id = $1 AND follower_id = $2)"
-	followeeID := uuid.New()
-	followerID := uuid.New()
+	queryCtx := postgres.WithQueryName(ctx, "list_followers")
+	expectedSQL := "SELECT users.id, users.username, users.bio, users.image, users.private, users.created_at, " +
+		"users.updated_at, follows.created_at, EXISTS (SELECT 1 FROM follows AS viewer " +
+		"WHERE viewer.followee_id = users.id AND viewer.follower_id = $1), " +
+		"EXISTS (SELECT 1 FROM blocks WHERE blocks.blocked_id = users.id AND blocks.blocker_id = $2), " +
+		"EXISTS (SELECT 1 FROM mutes WHERE mutes.muted_id = users.id AND mutes.muter_id = $3), " +
+		"EXISTS (SELECT 1 FROM follow_requests AS request " +
+		"WHERE request.followee_id = users.id AND request.follower_id = $4) FROM follows " +
+		"JOIN users ON users.id = follows.follower_id " +
+		"WHERE (follows.followee_id = $5 AND users.deleted_at IS NULL " +
+		"AND NOT EXISTS (SELECT 1 FROM blocks WHERE blocks.blocker_id = users.id AND blocks.blocked_id = $6) " +
+		"AND (follows.created_at, users.id) < ($7, $8)) " +
+		"ORDER BY follows.created_at DESC, users.id DESC LIMIT 11"
+	dto := profile.ListFollowsDTO{
+		ProfileID: uuid.New(),
+		ViewerID:  uuid.New(),
+		After:     &profile.Cursor{FollowedAt: time.Now(), ID: uuid.New()},
+		Limit:     11,
+	}
 
-	type args struct {
-		followeeID uuid.UUID
-		followerID uuid.UUID
+	tests := []struct {
+		name    string
+		mock    func(*mockPsql.MockRows, *mockPsql.MockPgxPool)
+		want    []profile.Follow
+		wantErr bool
+	}{
+		{
+			name: "success list followers",
+			mock: func(rows *mockPsql.MockRows, pool *mockPsql.MockPgxPool) {
+				pool.EXPECT().
+					Query(
+						queryCtx,
+						expectedSQL,
+						dto.ViewerID,
+						dto.ViewerID,
+						dto.ViewerID,
+						dto.ViewerID,
+						dto.ProfileID.String(),
+						dto.ViewerID,
+						dto.After.FollowedAt,
+						dto.After.ID,
+					).
+					Return(rows, nil).
+					Times(1)
+				gomock.InOrder(
+					rows.EXPECT().Next().Return(true),
+					rows.EXPECT().Scan(
+						gomock.Any(),
+						gomock.Any(),
+						gomock.Any(),
+						gomock.Any(),
+						gomock.Any(),
+						gomock.Any(),
+						gomock.Any(),
+						gomock.Any(),
+						gomock.Any(),
+						gomock.Any(),
+						gomock.Any(),
+						gomock.Any(),
+					).Return(nil),
+					rows.EXPECT().Next().Return(false),
+				)
+				rows.EXPECT().Err().Return(nil).Times(1)
+				rows.EXPECT().Close().Times(1)
+			},
+			want: []profile.Follow{{}},
+		},
+		{
+			name: "rows error",
+			mock: func(rows *mockPsql.MockRows, pool *mockPsql.MockPgxPool) {
+				pool.EXPECT().
+					Query(
+						queryCtx,
+						expectedSQL,
+						dto.ViewerID,
+						dto.ViewerID,
+						dto.ViewerID,
+						dto.ViewerID,
+						dto.ProfileID.String(),
+						dto.ViewerID,
+						dto.After.FollowedAt,
+						dto.After.ID,
+					).
+					Return(rows, nil).
+					Times(1)
+				rows.EXPECT().Next().Return(false).Times(1)
+				rows.EXPECT().Err().Return(errProfileRepository).Times(1)
+				rows.EXPECT().Close().Times(1)
+			},
+			wantErr: true,
+		},
+		{
+			name: "query error",
+			mock: func(rows *mockPsql.MockRows, pool *mockPsql.MockPgxPool) {
+				pool.EXPECT().
+					Query(
+						queryCtx,
+						expectedSQL,
+						dto.ViewerID,
+						dto.ViewerID,
+						dto.ViewerID,
+						dto.ViewerID,
+						dto.ProfileID.String(),
+						dto.ViewerID,
+						dto.After.FollowedAt,
+						dto.After.ID,
+					).
+					Return(nil, errProfileRepository).
+					Times(1)
+			},
+			wantErr: true,
+		},
 	}
 
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			profileRepository, mockPgxPool, _, mockRows := mockProfileRepository(t)
+
+			tt.mock(mockRows, mockPgxPool)
+
+			got, err := profileRepository.ListFollowers(ctx, dto)
+			require.True(t, (err != nil) == tt.wantErr)
+			require.True(t, reflect.DeepEqual(tt.want, got))
+		})
+	}
+}
+
+func TestProfileRepository_ListFollowing(t *testing.T) {
+	t.Parallel()
+
+	ctx := logger.ContextWithLogger(context.Background(), zap.L())
+	queryCtx := postgres.WithQueryName(ctx, "list_following")
+	expectedSQL := "SELECT users.id, users.username, users.bio, users.image, users.private, users.created_at, " +
+		"users.updated_at, follows.created_at, EXISTS (SELECT 1 FROM follows AS viewer " +
+		"WHERE viewer.followee_id = users.id AND viewer.follower_id = $1), " +
+		"EXISTS (SELECT 1 FROM blocks WHERE blocks.blocked_id = users.id AND blocks.blocker_id = $2), " +
+		"EXISTS (SELECT 1 FROM mutes WHERE mutes.muted_id = users.id AND mutes.muter_id = $3), " +
+		"EXISTS (SELECT 1 FROM follow_requests AS request " +
+		"WHERE request.followee_id = users.id AND request.follower_id = $4) FROM follows " +
+		"JOIN users ON users.id = follows.followee_id " +
+		"WHERE (follows.follower_id = $5 AND users.deleted_at IS NULL " +
+		"AND NOT EXISTS (SELECT 1 FROM blocks WHERE blocks.blocker_id = users.id AND blocks.blocked_id = $6)) " +
+		"ORDER BY follows.created_at DESC, users.id DESC LIMIT 21"
+	dto := profile.ListFollowsDTO{
+		ProfileID: uuid.New(),
+		Limit:     21,
+	}
+
+	profileRepository, mockPgxPool, _, mockRows := mockProfileRepository(t)
+
+	mockPgxPool.EXPECT().
+		Query(queryCtx, expectedSQL, uuid.Nil, uuid.Nil, uuid.Nil, uuid.Nil, dto.ProfileID.String(), uuid.Nil).
+		Return(mockRows, nil).
+		Times(1)
+	mockRows.EXPECT().Next().Return(false).Times(1)
+	mockRows.EXPECT().Err().Return(nil).Times(1)
+	mockRows.EXPECT().Close().Times(1)
+
+	got, err := profileRepository.ListFollowing(ctx, dto)
+	require.NoError(t, err)
+	require.Empty(t, got)
+}
+
+func TestProfileRepository_GetRestrictions(t *testing.T) {
+	t.Parallel()
+
+	ctx := logger.ContextWithLogger(context.Background(), zap.L())
+	queryCtx := postgres.WithQueryName(ctx, "get_restrictions")
+	expectedSQL := "SELECT EXISTS (SELECT 1 FROM blocks WHERE blocker_id = $1 AND blocked_id = $2), " +
+		"EXISTS (SELECT 1 FROM blocks WHERE blocker_id = $3 AND blocked_id = $4), " +
+		"EXISTS (SELECT 1 FROM mutes WHERE muter_id = $5 AND muted_id = $6), " +
+		"EXISTS (SELECT 1 FROM follow_requests WHERE followee_id = $7 AND follower_id = $8)"
+	profileID := uuid.New()
+	viewerID := uuid.New()
+
 	tests := []struct {
 		name    string
-		mock    func(*mockPsql.MockPgxPool)
-		args    args
+		mock    func(*mockPsql.MockRow, *mockPsql.MockPgxPool)
+		want    profile.Restrictions
 		wantErr bool
 	}{
 		{
-			name: "delete follow success",
-			mock: func(pool *mockPsql.MockPgxPool) {
+			name: "success get restrictions",
+			mock: func(row *mockPsql.MockRow, pool *mockPsql.MockPgxPool) {
 				pool.EXPECT().
-					Exec(ctx, expectedSQL, followeeID.String(), followerID.String()).
-					Return([]byte{}, nil).
+					QueryRow(
+						queryCtx,
+						expectedSQL,
+						viewerID, profileID, profileID, viewerID, viewerID, profileID, profileID, viewerID,
+					).
+					Return(row).
+					Times(1)
+				row.EXPECT().
+					Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					DoAndReturn(func(dest ...interface{}) error {
+						*dest[0].(*bool) = true
+						*dest[2].(*bool) = true
+						*dest[3].(*bool) = true
+
+						return nil
+					}).
 					Times(1)
 			},
-			args: args{
-				followeeID: followeeID,
-				followerID: followerID,
+			want: profile.Restrictions{Blocking: true, Muting: true, Requested: true},
+		},
+		{
+			name: "scan error",
+			mock: func(row *mockPsql.MockRow, pool *mockPsql.MockPgxPool) {
+				pool.EXPECT().
+					QueryRow(
+						queryCtx,
+						expectedSQL,
+						viewerID, profileID, profileID, viewerID, viewerID, profileID, profileID, viewerID,
+					).
+					Return(row).
+					Times(1)
+				row.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(errProfileRepository).Times(1)
 			},
+			want:    profile.Restrictions{},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			profileRepository, mockPgxPool, mockRow, _ := mockProfileRepository(t)
+
+			tt.mock(mockRow, mockPgxPool)
+
+			got, err := profileRepository.GetRestrictions(ctx, profileID, viewerID)
+			require.True(t, (err != nil) == tt.wantErr)
+			require.True(t, reflect.DeepEqual(tt.want, got))
+		})
+	}
+}
+
+func TestProfileRepository_Block(t *testing.T) {
+	t.Parallel()
+
+	ctx := logger.ContextWithLogger(context.Background(), zap.L())
+	queryCtx := postgres.WithQueryName(ctx, "block")
+	blockerID := uuid.New()
+	blockedID := uuid.New()
+
+	tests := []struct {
+		name    string
+		mock    func(*mockPsql.MockPgxPool)
+		wantErr error
+	}{
+		{
+			name: "success block",
+			mock: func(pool *mockPsql.MockPgxPool) {
+				pool.EXPECT().Exec(queryCtx, gomock.Any(), blockerID, blockedID).Return([]byte{}, nil).Times(1)
+			},
+		},
+		{
+			name: "block self",
+			mock: func(pool *mockPsql.MockPgxPool) {
+				pool.EXPECT().
+					Exec(queryCtx, gomock.Any(), blockerID, blockedID).
+					Return(nil, &pgconn.PgError{Code: pgerrcode.CheckViolation}).
+					Times(1)
+			},
+			wantErr: profile.ErrCannotBlockSelf,
 		},
 		{
 			name: "exec error",
+			mock: func(pool *mockPsql.MockPgxPool) {
+				pool.EXPECT().Exec(queryCtx, gomock.Any(), blockerID, blockedID).Return(nil, errProfileRepository).Times(1)
+			},
+			wantErr: errProfileRepository,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			profileRepository, mockPgxPool, _, _ := mockProfileRepository(t)
+
+			tt.mock(mockPgxPool)
+
+			err := profileRepository.Block(ctx, blockerID, blockedID)
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestProfileRepository_Unmute(t *testing.T) {
+	t.Parallel()
+
+	ctx := logger.ContextWithLogger(context.Background(), zap.L())
+	queryCtx := postgres.WithQueryName(ctx, "unmute")
+	expectedSQL := "DELETE FROM mutes WHERE (muter_id = $1 AND muted_id = $2)"
+	muterID := uuid.New()
+	mutedID := uuid.New()
+
+	tests := []struct {
+		name    string
+		mock    func(*mockPsql.MockPgxPool)
+		wantErr error
+	}{
+		{
+			name: "success unmute",
 			mock: func(pool *mockPsql.MockPgxPool) {
 				pool.EXPECT().
-					Exec(ctx, expectedSQL, followeeID.String(), followerID.String()).
-					Return(nil, errProfileRepository).
+					Exec(queryCtx, expectedSQL, muterID.String(), mutedID.String()).
+					Return(pgconn.CommandTag("DELETE 1"), nil).
 					Times(1)
 			},
-			args: args{
-				followeeID: followeeID,
-				followerID: followerID,
+		},
+		{
+			name: "not muting",
+			mock: func(pool *mockPsql.MockPgxPool) {
+				pool.EXPECT().
+					Exec(queryCtx, expectedSQL, muterID.String(), mutedID.String()).
+					Return(pgconn.CommandTag("DELETE 0"), nil).
+					Times(1)
 			},
-			wantErr: true,
+			wantErr: profile.ErrNotMuting,
 		},
 	}
 
@@ -436,12 +948,113 @@ func TestProfileRepository_Unfollow(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			profileRepository, mockPgxPool, _ := mockProfileRepository(t)
+			profileRepository, mockPgxPool, _, _ := mockProfileRepository(t)
 
 			tt.mock(mockPgxPool)
 
-			err := profileRepository.Unfollow(ctx, tt.args.followeeID, tt.args.followerID)
-			require.True(t, (err != nil) == tt.wantErr)
+			err := profileRepository.Unmute(ctx, muterID, mutedID)
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestProfileRepository_ApproveFollowRequest(t *testing.T) {
+	t.Parallel()
+
+	ctx := logger.ContextWithLogger(context.Background(), zap.L())
+	queryCtx := postgres.WithQueryName(ctx, "approve_follow_request")
+	followeeID := uuid.New()
+	followerID := uuid.New()
+
+	tests := []struct {
+		name     string
+		approved int
+		scanErr  error
+		wantErr  error
+	}{
+		{
+			name:     "success approve",
+			approved: 1,
+		},
+		{
+			name:    "no follow request",
+			wantErr: profile.ErrFollowRequestNotFound,
+		},
+		{
+			name:    "scan error",
+			scanErr: errProfileRepository,
+			wantErr: errProfileRepository,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			profileRepository, mockPgxPool, mockRow, _ := mockProfileRepository(t)
+
+			mockPgxPool.EXPECT().QueryRow(queryCtx, gomock.Any(), followeeID, followerID).Return(mockRow).Times(1)
+			mockRow.EXPECT().Scan(gomock.Any()).DoAndReturn(func(dest ...interface{}) error {
+				*dest[0].(*int) = tt.approved
+
+				return tt.scanErr
+			}).Times(1)
+
+			err := profileRepository.ApproveFollowRequest(ctx, followeeID, followerID)
+			require.ErrorIs(t, err, tt.wantErr)
 		})
 	}
 }
+
+func TestProfileRepository_ListFollowRequests(t *testing.T) {
+	t.Parallel()
+
+	ctx := logger.ContextWithLogger(context.Background(), zap.L())
+	queryCtx := postgres.WithQueryName(ctx, "list_follow_requests")
+	expectedSQL := "SELECT users.id, users.username, users.bio, users.image, users.private, users.created_at, " +
+		"users.updated_at, follow_requests.created_at, EXISTS (SELECT 1 FROM follows AS viewer " +
+		"WHERE viewer.followee_id = users.id AND viewer.follower_id = $1), " +
+		"EXISTS (SELECT 1 FROM blocks WHERE blocks.blocked_id = users.id AND blocks.blocker_id = $2), " +
+		"EXISTS (SELECT 1 FROM mutes WHERE mutes.muted_id = users.id AND mutes.muter_id = $3), " +
+		"EXISTS (SELECT 1 FROM follow_requests AS request " +
+		"WHERE request.followee_id = users.id AND request.follower_id = $4) FROM follow_requests " +
+		"JOIN users ON users.id = follow_requests.follower_id " +
+		"WHERE (follow_requests.followee_id = $5 AND users.deleted_at IS NULL " +
+		"AND NOT EXISTS (SELECT 1 FROM blocks WHERE blocks.blocker_id = users.id AND blocks.blocked_id = $6) " +
+		"AND (follow_requests.created_at, users.id) < ($7, $8)) " +
+		"ORDER BY follow_requests.created_at DESC, users.id DESC LIMIT 6"
+	ownerID := uuid.New()
+	dto := profile.ListFollowsDTO{
+		ProfileID: ownerID,
+		ViewerID:  ownerID,
+		After:     &profile.Cursor{FollowedAt: time.Now(), ID: uuid.New()},
+		Limit:     6,
+	}
+
+	profileRepository, mockPgxPool, _, mockRows := mockProfileRepository(t)
+
+	mockPgxPool.EXPECT().
+		Query(
+			queryCtx,
+			expectedSQL,
+			ownerID,
+			ownerID,
+			ownerID,
+			ownerID,
+			ownerID.String(),
+			ownerID,
+			dto.After.FollowedAt,
+			dto.After.ID,
+		).
+		Return(mockRows, nil).
+		Times(1)
+	mockRows.EXPECT().Next().Return(false).Times(1)
+	mockRows.EXPECT().Err().Return(nil).Times(1)
+	mockRows.EXPECT().Close().Times(1)
+
+	got, err := profileRepository.ListFollowRequests(ctx, dto)
+	require.NoError(t, err)
+	require.Empty(t, got)
+}
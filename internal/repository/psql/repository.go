@@ -1,17 +1,15 @@
 package psql
 
-import "github.com/maypok86/conduit/pkg/postgres"
+import (
+	"github.com/maypok86/conduit/internal/domain"
+	"github.com/maypok86/conduit/pkg/postgres"
+)
 
-// Repositories is a collection of all repositories in the system.
-type Repositories struct {
-	User    UserRepository
-	Profile ProfileRepository
-}
-
-// NewRepositories returns a new instance of Repositories.
-func NewRepositories(db *postgres.Postgres) Repositories {
-	return Repositories{
+// NewRepositories returns the repositories of the services.
+func NewRepositories(db *postgres.Postgres) domain.Repositories {
+	return domain.Repositories{
 		User:    NewUserRepository(db),
 		Profile: NewProfileRepository(db),
+		Export:  NewExportRepository(db),
 	}
 }
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	sq "github.com/Masterminds/squirrel"
+	"github.com/maypok86/conduit/internal/domain"
 	"github.com/maypok86/conduit/internal/repository/psql"
 	"github.com/maypok86/conduit/pkg/postgres"
 	"github.com/stretchr/testify/require"
@@ -17,9 +18,10 @@ func TestNewRepositories(t *testing.T) {
 		Builder: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
 	}
 
-	want := psql.Repositories{
+	want := domain.Repositories{
 		User:    psql.NewUserRepository(db),
 		Profile: psql.NewProfileRepository(db),
+		Export:  psql.NewExportRepository(db),
 	}
 
 	got := psql.NewRepositories(db)
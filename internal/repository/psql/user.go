@@ -5,8 +5,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
 	"github.com/jackc/pgconn"
 	"github.com/jackc/pgerrcode"
 	"github.com/jackc/pgx/v4"
@@ -16,6 +19,11 @@ import (
 	"go.uber.org/zap"
 )
 
+const (
+	usersEmailUniqueIndex    = "users_email_lower_key"
+	usersUsernameUniqueIndex = "users_username_lower_key"
+)
+
 // UserRepository is a user repository.
 type UserRepository struct {
 	db *postgres.Postgres
@@ -43,14 +51,12 @@ func (ur UserRepository) Create(ctx context.Context, dto user.User) (user.User,
 		return user.User{}, fmt.Errorf("can not build insert user query: %w", err)
 	}
 
+	ctx = postgres.WithQueryName(ctx, "create_user")
 	logger.FromContext(ctx).Debug("create user query", zap.String("sql", sql), zap.Any("args", args))
 
-	if err := ur.db.Pool.QueryRow(ctx, sql, args...).Scan(&dto.ID); err != nil {
-		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr) {
-			if pgErr.Code == pgerrcode.UniqueViolation {
-				return user.User{}, fmt.Errorf("can not insert user: %w", user.ErrAlreadyExist)
-			}
+	if err := ur.db.Querier(ctx).QueryRow(ctx, sql, args...).Scan(&dto.ID); err != nil {
+		if conflictErr := userConflictError(err); conflictErr != nil {
+			return user.User{}, fmt.Errorf("can not insert user: %w", conflictErr)
 		}
 
 		return user.User{}, fmt.Errorf("can not insert user: %w", err)
@@ -59,38 +65,102 @@ func (ur UserRepository) Create(ctx context.Context, dto user.User) (user.User,
 	return dto, nil
 }
 
-// GetByEmail returns user by email.
+// userConflictError converts unique violation of users table into the error of the conflicted field.
+func userConflictError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != pgerrcode.UniqueViolation {
+		return nil
+	}
+
+	switch pgErr.ConstraintName {
+	case usersEmailUniqueIndex:
+		return user.ErrEmailAlreadyExist
+	case usersUsernameUniqueIndex:
+		return user.ErrUsernameAlreadyExist
+	default:
+		return user.ErrAlreadyExist
+	}
+}
+
+// GetByEmail returns user by email. Emails are compared case-insensitively.
 func (ur UserRepository) GetByEmail(ctx context.Context, email string) (user.User, error) {
-	sql, args, err := ur.db.Builder.Select(
+	selectBuilder := ur.selectUserQuery().Where(sq.Eq{"lower(email)": strings.ToLower(email)}).Limit(1)
+
+	ctx = postgres.WithQueryName(ctx, "select_user_by_email")
+
+	u, err := ur.selectUser(ctx, ur.db.ReadQuerier(ctx), selectBuilder)
+	if err != nil {
+		return user.User{}, fmt.Errorf("can not find user by email: %w", err)
+	}
+
+	return u, nil
+}
+
+// LockByEmail returns the active user by email and locks it until the transaction ends, so the changes
+// that depend on the current state of the user are serialized.
+func (ur UserRepository) LockByEmail(ctx context.Context, email string) (user.User, error) {
+	selectBuilder := ur.selectUserQuery().
+		Where(sq.And{sq.Eq{"lower(email)": strings.ToLower(email)}, sq.Eq{"deleted_at": nil}}).
+		Suffix("FOR UPDATE")
+
+	ctx = postgres.WithQueryName(ctx, "lock_user_by_email")
+
+	// The lock is taken on the primary, replicas can not lock rows.
+	u, err := ur.selectUser(ctx, ur.db.Querier(ctx), selectBuilder)
+	if err != nil {
+		return user.User{}, fmt.Errorf("can not lock user by email: %w", err)
+	}
+
+	return u, nil
+}
+
+func (ur UserRepository) selectUserQuery() sq.SelectBuilder {
+	return ur.db.Builder.Select(
 		"id",
 		"username",
+		"email",
 		"password",
 		"bio",
 		"image",
+		"private",
 		"created_at",
 		"updated_at",
-	).From("users").Where(sq.Eq{"email": email}).Limit(1).ToSql()
+		"deleted_at",
+	).From("users")
+}
+
+// selectUser runs the query built by selectUserQuery with querier. It returns user.ErrNotFound if there is
+// no such user.
+func (ur UserRepository) selectUser(
+	ctx context.Context,
+	querier postgres.Querier,
+	selectBuilder sq.SelectBuilder,
+) (user.User, error) {
+	sql, args, err := selectBuilder.ToSql()
 	if err != nil {
-		return user.User{}, fmt.Errorf("can not build select user by email query: %w", err)
+		return user.User{}, fmt.Errorf("can not build select user query: %w", err)
 	}
 
-	logger.FromContext(ctx).Debug("select user by email query", zap.String("sql", sql), zap.Any("args", args))
+	logger.FromContext(ctx).Debug("select user query", zap.String("sql", sql), zap.Any("args", args))
 
-	u := user.User{Email: email}
-	if err := ur.db.Pool.QueryRow(ctx, sql, args...).Scan(
+	var u user.User
+	if err := querier.QueryRow(ctx, sql, args...).Scan(
 		&u.ID,
 		&u.Username,
+		&u.Email,
 		&u.Password,
 		&u.Bio,
 		&u.Image,
+		&u.Private,
 		&u.CreatedAt,
 		&u.UpdatedAt,
+		&u.DeletedAt,
 	); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return user.User{}, fmt.Errorf("can not find user by email: %w", user.ErrNotFound)
+			return user.User{}, user.ErrNotFound
 		}
 
-		return user.User{}, fmt.Errorf("can not find user by email: %w", err)
+		return user.User{}, err
 	}
 
 	return u, nil
@@ -113,38 +183,266 @@ func (ur UserRepository) buildUpdateUserQuery(updateBuilder sq.UpdateBuilder, dt
 		updateBuilder = updateBuilder.Set("image", *dto.Image)
 	}
 
+	if dto.Private != nil {
+		updateBuilder = updateBuilder.Set("private", *dto.Private)
+	}
+
 	return updateBuilder.Set("updated_at", dto.UpdatedAt)
 }
 
+// renameUserCTE records the current username in the history and puts it on cooldown for everyone except
+// its owner when the update changes it. Both happen in the same statement as the update itself. A change
+// of the case only keeps the username, so it is not recorded.
+const renameUserCTE = `renamed AS (
+	SELECT id, username FROM users WHERE lower(email) = ? AND deleted_at IS NULL AND lower(username) <> lower(?)
+), history AS (
+	INSERT INTO username_history (user_id, username, renamed_at) SELECT id, username, ? FROM renamed
+), cooldown AS (
+	INSERT INTO quarantined_usernames (username, quarantined_until, user_id)
+	SELECT lower(username), ?, id FROM renamed
+	ON CONFLICT (username) DO UPDATE SET quarantined_until = EXCLUDED.quarantined_until, user_id = EXCLUDED.user_id
+)`
+
+// publishUserCTE approves the pending follow requests when the update makes the account public,
+// in the same statement as the update itself.
+const publishUserCTE = `published AS (
+	DELETE FROM follow_requests
+	WHERE followee_id IN (SELECT id FROM users WHERE lower(email) = ? AND deleted_at IS NULL)
+	RETURNING followee_id, follower_id
+), approved AS (
+	INSERT INTO follows (followee_id, follower_id) SELECT followee_id, follower_id FROM published
+	ON CONFLICT (followee_id, follower_id) DO NOTHING
+)`
+
 // UpdateByEmail updates user by email.
 func (ur UserRepository) UpdateByEmail(ctx context.Context, email string, dto user.UpdateDTO) (user.User, error) {
+	var (
+		ctes    []string
+		cteArgs []interface{}
+	)
+
+	if dto.Username != nil {
+		ctes = append(ctes, renameUserCTE)
+		cteArgs = append(
+			cteArgs,
+			strings.ToLower(email),
+			*dto.Username,
+			dto.UpdatedAt,
+			dto.UsernameCooldownUntil,
+		)
+	}
+
+	if dto.Private != nil && !*dto.Private {
+		ctes = append(ctes, publishUserCTE)
+		cteArgs = append(cteArgs, strings.ToLower(email))
+	}
+
 	updateBuilder := ur.buildUpdateUserQuery(ur.db.Builder.Update("users"), dto)
+	if len(ctes) > 0 {
+		updateBuilder = updateBuilder.Prefix("WITH "+strings.Join(ctes, ", "), cteArgs...)
+	}
 
 	sql, args, err := updateBuilder.Suffix(
-		"RETURNING id, username, email, password, bio, image, created_at",
-	).Where(sq.Eq{"email": email}).ToSql()
+		"RETURNING id, username, email, password, bio, image, private, created_at",
+	).Where(sq.And{sq.Eq{"lower(email)": strings.ToLower(email)}, sq.Eq{"deleted_at": nil}}).ToSql()
 	if err != nil {
 		return user.User{}, fmt.Errorf("can not build update user by email query: %w", err)
 	}
 
+	ctx = postgres.WithQueryName(ctx, "update_user_by_email")
 	logger.FromContext(ctx).Debug("update user by email query", zap.String("sql", sql), zap.Any("args", args))
 
 	u := user.User{UpdatedAt: dto.UpdatedAt}
-	if err := ur.db.Pool.QueryRow(ctx, sql, args...).Scan(
+	if err := ur.db.Querier(ctx).QueryRow(ctx, sql, args...).Scan(
 		&u.ID,
 		&u.Username,
 		&u.Email,
 		&u.Password,
 		&u.Bio,
 		&u.Image,
+		&u.Private,
 		&u.CreatedAt,
 	); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return user.User{}, fmt.Errorf("can not update user by email: %w", user.ErrNotFound)
 		}
 
+		if conflictErr := userConflictError(err); conflictErr != nil {
+			return user.User{}, fmt.Errorf("can not update user by email: %w", conflictErr)
+		}
+
 		return user.User{}, fmt.Errorf("can not update user by email: %w", err)
 	}
 
 	return u, nil
 }
+
+// DeleteByEmail marks user as deleted.
+func (ur UserRepository) DeleteByEmail(ctx context.Context, email string, deletedAt time.Time) error {
+	sql, args, err := ur.db.Builder.Update("users").
+		Set("deleted_at", deletedAt).
+		Where(sq.And{sq.Eq{"lower(email)": strings.ToLower(email)}, sq.Eq{"deleted_at": nil}}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("can not build delete user by email query: %w", err)
+	}
+
+	ctx = postgres.WithQueryName(ctx, "delete_user_by_email")
+	logger.FromContext(ctx).Debug("delete user by email query", zap.String("sql", sql), zap.Any("args", args))
+
+	commandTag, err := ur.db.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("can not delete user by email: %w", err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return fmt.Errorf("can not delete user by email: %w", user.ErrNotFound)
+	}
+
+	return nil
+}
+
+// Restore cancels user deletion if user is not purged yet.
+func (ur UserRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	sql, args, err := ur.db.Builder.Update("users").
+		Set("deleted_at", nil).
+		Where(sq.And{sq.Eq{"id": id}, sq.Eq{"purged_at": nil}}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("can not build restore user query: %w", err)
+	}
+
+	ctx = postgres.WithQueryName(ctx, "restore_user")
+	logger.FromContext(ctx).Debug("restore user query", zap.String("sql", sql), zap.Any("args", args))
+
+	commandTag, err := ur.db.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("can not restore user: %w", err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return fmt.Errorf("can not restore user: %w", user.ErrNotFound)
+	}
+
+	return nil
+}
+
+const (
+	// purgeAnonymizeSQL quarantines usernames of expired users, drops their follows, follow requests, blocks,
+	// mutes, exports and username history and replaces their personal data with placeholders.
+	purgeAnonymizeSQL = `WITH expired AS (
+	SELECT id, username FROM users WHERE deleted_at < $1 AND purged_at IS NULL
+), quarantined AS (
+	INSERT INTO quarantined_usernames (username, quarantined_until) SELECT lower(username), $2 FROM expired
+	ON CONFLICT (username) DO UPDATE SET quarantined_until = EXCLUDED.quarantined_until, user_id = NULL
+), renames AS (
+	DELETE FROM username_history WHERE user_id IN (SELECT id FROM expired)
+), unfollowed AS (
+	DELETE FROM follows WHERE followee_id IN (SELECT id FROM expired) OR follower_id IN (SELECT id FROM expired)
+), unblocked AS (
+	DELETE FROM blocks WHERE blocker_id IN (SELECT id FROM expired) OR blocked_id IN (SELECT id FROM expired)
+), unmuted AS (
+	DELETE FROM mutes WHERE muter_id IN (SELECT id FROM expired) OR muted_id IN (SELECT id FROM expired)
+), unrequested AS (
+	DELETE FROM follow_requests WHERE followee_id IN (SELECT id FROM expired) OR follower_id IN (SELECT id FROM expired)
+), exports AS (
+	DELETE FROM user_exports WHERE user_id IN (SELECT id FROM expired)
+)
+UPDATE users SET
+	username = 'deleted-' || replace(users.id::text, '-', ''),
+	email = users.id::text || '@deleted.invalid',
+	password = '',
+	bio = NULL,
+	image = NULL,
+	purged_at = $3
+FROM expired WHERE users.id = expired.id`
+
+	// purgeCascadeSQL quarantines usernames of expired users and deletes them with everything that references them.
+	purgeCascadeSQL = `WITH expired AS (
+	SELECT id, username FROM users WHERE deleted_at < $1 AND purged_at IS NULL
+), quarantined AS (
+	INSERT INTO quarantined_usernames (username, quarantined_until) SELECT lower(username), $2 FROM expired
+	ON CONFLICT (username) DO UPDATE SET quarantined_until = EXCLUDED.quarantined_until, user_id = NULL
+)
+DELETE FROM users WHERE id IN (SELECT id FROM expired)`
+)
+
+// Purge anonymizes or deletes users whose deletion grace period has ended.
+func (ur UserRepository) Purge(ctx context.Context, dto user.PurgeDTO) (int64, error) {
+	var (
+		sql  string
+		args []interface{}
+	)
+
+	switch dto.Mode {
+	case user.DeletionModeAnonymize:
+		sql, args = purgeAnonymizeSQL, []interface{}{dto.DeletedBefore, dto.QuarantinedUntil, dto.PurgedAt}
+	case user.DeletionModeCascade:
+		sql, args = purgeCascadeSQL, []interface{}{dto.DeletedBefore, dto.QuarantinedUntil}
+	default:
+		return 0, fmt.Errorf("can not purge users in %q mode: %w", dto.Mode, user.ErrUnknownDeletionMode)
+	}
+
+	ctx = postgres.WithQueryName(ctx, "purge_users")
+	logger.FromContext(ctx).Debug("purge users query", zap.String("sql", sql), zap.Any("args", args))
+
+	commandTag, err := ur.db.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return 0, fmt.Errorf("can not purge users: %w", err)
+	}
+
+	return commandTag.RowsAffected(), nil
+}
+
+// CheckUsernameQuarantine checks if username was released recently and can not be claimed yet.
+// Usernames released by the user itself can be claimed back.
+func (ur UserRepository) CheckUsernameQuarantine(
+	ctx context.Context,
+	username string,
+	userID uuid.UUID,
+	now time.Time,
+) error {
+	sql, args, err := ur.db.Builder.Select("username").From("quarantined_usernames").
+		Where(sq.And{
+			sq.Eq{"username": strings.ToLower(username)},
+			sq.Gt{"quarantined_until": now},
+			sq.Or{sq.Eq{"user_id": nil}, sq.NotEq{"user_id": userID}},
+		}).
+		Limit(1).ToSql()
+	if err != nil {
+		return fmt.Errorf("can not build check username quarantine query: %w", err)
+	}
+
+	ctx = postgres.WithQueryName(ctx, "check_username_quarantine")
+	logger.FromContext(ctx).Debug("check username quarantine query", zap.String("sql", sql), zap.Any("args", args))
+
+	if err := ur.db.ReadQuerier(ctx).QueryRow(ctx, sql, args...).Scan(&username); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+
+		return fmt.Errorf("can not check username quarantine: %w", err)
+	}
+
+	return fmt.Errorf("can not check username quarantine: %w", user.ErrUsernameQuarantined)
+}
+
+// CountRenames returns the number of username changes of the user since the given time.
+func (ur UserRepository) CountRenames(ctx context.Context, userID uuid.UUID, since time.Time) (int, error) {
+	sql, args, err := ur.db.Builder.Select("count(*)").From("username_history").
+		Where(sq.And{sq.Eq{"user_id": userID}, sq.GtOrEq{"renamed_at": since}}).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("can not build count renames query: %w", err)
+	}
+
+	ctx = postgres.WithQueryName(ctx, "count_renames")
+	logger.FromContext(ctx).Debug("count renames query", zap.String("sql", sql), zap.Any("args", args))
+
+	var count int
+	if err := ur.db.ReadQuerier(ctx).QueryRow(ctx, sql, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("can not count renames: %w", err)
+	}
+
+	return count, nil
+}
@@ -4,12 +4,14 @@ import (
 	"context"
 	"errors"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/bxcodec/faker/v3"
 	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
 	"github.com/jackc/pgconn"
 	"github.com/jackc/pgerrcode"
 	"github.com/jackc/pgx/v4"
@@ -51,6 +53,7 @@ func TestUserRepository_Create(t *testing.T) {
 	t.Parallel()
 
 	ctx := logger.ContextWithLogger(context.Background(), zap.L())
+	queryCtx := postgres.WithQueryName(ctx, "create_user")
 	expectedSQL := "INSERT INTO users (email,username,password) VALUES ($1,$2,$3) RETURNING id"
 	dto := user.User{
 		Username: faker.Username(),
@@ -73,7 +76,7 @@ func TestUserRepository_Create(t *testing.T) {
 			name: "creation user",
 			mock: func(row *mockPsql.MockRow, pool *mockPsql.MockPgxPool) {
 				row.EXPECT().Scan(gomock.Any()).Return(nil).Times(1)
-				pool.EXPECT().QueryRow(ctx, expectedSQL, dto.Email, dto.Username, dto.Password).Return(row).Times(1)
+				pool.EXPECT().QueryRow(queryCtx, expectedSQL, dto.Email, dto.Username, dto.Password).Return(row).Times(1)
 			},
 			args: args{
 				dto: dto,
@@ -84,7 +87,7 @@ func TestUserRepository_Create(t *testing.T) {
 			name: "scan error",
 			mock: func(row *mockPsql.MockRow, pool *mockPsql.MockPgxPool) {
 				row.EXPECT().Scan(gomock.Any()).Return(errUserRepository).Times(1)
-				pool.EXPECT().QueryRow(ctx, expectedSQL, dto.Email, dto.Username, dto.Password).Return(row).Times(1)
+				pool.EXPECT().QueryRow(queryCtx, expectedSQL, dto.Email, dto.Username, dto.Password).Return(row).Times(1)
 			},
 			args: args{
 				dto: dto,
@@ -96,7 +99,7 @@ func TestUserRepository_Create(t *testing.T) {
 			name: "unique violation error",
 			mock: func(row *mockPsql.MockRow, pool *mockPsql.MockPgxPool) {
 				row.EXPECT().Scan(gomock.Any()).Return(&pgconn.PgError{Code: pgerrcode.UniqueViolation}).Times(1)
-				pool.EXPECT().QueryRow(ctx, expectedSQL, dto.Email, dto.Username, dto.Password).Return(row).Times(1)
+				pool.EXPECT().QueryRow(queryCtx, expectedSQL, dto.Email, dto.Username, dto.Password).Return(row).Times(1)
 			},
 			args: args{
 				dto: dto,
@@ -123,12 +126,64 @@ func TestUserRepository_Create(t *testing.T) {
 	}
 }
 
+func TestUserRepository_CreateConflict(t *testing.T) {
+	t.Parallel()
+
+	ctx := logger.ContextWithLogger(context.Background(), zap.L())
+	queryCtx := postgres.WithQueryName(ctx, "create_user")
+	dto := user.User{
+		Username: faker.Username(),
+		Email:    faker.Email(),
+		Password: faker.Password(),
+	}
+
+	tests := []struct {
+		name       string
+		constraint string
+		wantErr    error
+	}{
+		{
+			name:       "username is taken",
+			constraint: "users_username_lower_key",
+			wantErr:    user.ErrUsernameAlreadyExist,
+		},
+		{
+			name:       "email is taken",
+			constraint: "users_email_lower_key",
+			wantErr:    user.ErrEmailAlreadyExist,
+		},
+		{
+			name:       "unknown constraint",
+			constraint: "users_pkey",
+			wantErr:    user.ErrAlreadyExist,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			userRepository, mockPgxPool, mockRow := mockUserRepository(t)
+
+			mockRow.EXPECT().Scan(gomock.Any()).
+				Return(&pgconn.PgError{Code: pgerrcode.UniqueViolation, ConstraintName: tt.constraint}).Times(1)
+			mockPgxPool.EXPECT().QueryRow(queryCtx, gomock.Any(), dto.Email, dto.Username, dto.Password).Return(mockRow).Times(1)
+
+			_, err := userRepository.Create(ctx, dto)
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
 func TestUserRepository_GetByEmail(t *testing.T) {
 	t.Parallel()
 
 	ctx := logger.ContextWithLogger(context.Background(), zap.L())
-	expectedSQL := "SELECT id, username, password, bio, image, created_at, updated_at FROM users WHERE email = $1 LIMIT 1"
-	email := faker.Email()
+	queryCtx := postgres.WithQueryName(ctx, "select_user_by_email")
+	expectedSQL := "SELECT id, username, email, password, bio, image, private, created_at, updated_at, deleted_at FROM users WHERE lower(email) = $1 LIMIT 1" //nolint:lll
+	email := strings.ToLower(faker.Email())
 	userEntity := user.User{
 		Email: email,
 	}
@@ -155,11 +210,18 @@ func TestUserRepository_GetByEmail(t *testing.T) {
 					gomock.Any(),
 					gomock.Any(),
 					gomock.Any(),
-				).Return(nil).Times(1)
-				pool.EXPECT().QueryRow(ctx, expectedSQL, email).Return(row).Times(1)
+					gomock.Any(),
+					gomock.Any(),
+					gomock.Any(),
+				).DoAndReturn(func(dest ...interface{}) error {
+					*dest[2].(*string) = email
+
+					return nil
+				}).Times(1)
+				pool.EXPECT().QueryRow(queryCtx, expectedSQL, email).Return(row).Times(1)
 			},
 			args: args{
-				email: email,
+				email: strings.ToUpper(email),
 			},
 			want: userEntity,
 		},
@@ -174,8 +236,11 @@ func TestUserRepository_GetByEmail(t *testing.T) {
 					gomock.Any(),
 					gomock.Any(),
 					gomock.Any(),
+					gomock.Any(),
+					gomock.Any(),
+					gomock.Any(),
 				).Return(errUserRepository).Times(1)
-				pool.EXPECT().QueryRow(ctx, expectedSQL, email).Return(row).Times(1)
+				pool.EXPECT().QueryRow(queryCtx, expectedSQL, email).Return(row).Times(1)
 			},
 			args: args{
 				email: email,
@@ -194,8 +259,11 @@ func TestUserRepository_GetByEmail(t *testing.T) {
 					gomock.Any(),
 					gomock.Any(),
 					gomock.Any(),
+					gomock.Any(),
+					gomock.Any(),
+					gomock.Any(),
 				).Return(pgx.ErrNoRows).Times(1)
-				pool.EXPECT().QueryRow(ctx, expectedSQL, email).Return(row).Times(1)
+				pool.EXPECT().QueryRow(queryCtx, expectedSQL, email).Return(row).Times(1)
 			},
 			args: args{
 				email: email,
@@ -222,20 +290,75 @@ func TestUserRepository_GetByEmail(t *testing.T) {
 	}
 }
 
+func TestUserRepository_LockByEmail(t *testing.T) {
+	t.Parallel()
+
+	ctx := logger.ContextWithLogger(context.Background(), zap.L())
+	queryCtx := postgres.WithQueryName(ctx, "lock_user_by_email")
+	expectedSQL := "SELECT id, username, email, password, bio, image, private, created_at, updated_at, deleted_at FROM users WHERE (lower(email) = $1 AND deleted_at IS NULL) FOR UPDATE" //nolint:lll
+	email := strings.ToLower(faker.Email())
+
+	tests := []struct {
+		name    string
+		scanErr error
+		wantErr error
+	}{
+		{
+			name: "success lock",
+		},
+		{
+			name:    "no rows error",
+			scanErr: pgx.ErrNoRows,
+			wantErr: user.ErrNotFound,
+		},
+		{
+			name:    "scan error",
+			scanErr: errUserRepository,
+			wantErr: errUserRepository,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			userRepository, mockPgxPool, mockRow := mockUserRepository(t)
+
+			mockRow.EXPECT().Scan(
+				gomock.Any(),
+				gomock.Any(),
+				gomock.Any(),
+				gomock.Any(),
+				gomock.Any(),
+				gomock.Any(),
+				gomock.Any(),
+				gomock.Any(),
+				gomock.Any(),
+				gomock.Any(),
+			).Return(tt.scanErr).Times(1)
+			mockPgxPool.EXPECT().QueryRow(queryCtx, expectedSQL, email).Return(mockRow).Times(1)
+
+			_, err := userRepository.LockByEmail(ctx, strings.ToUpper(email))
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
 func TestUserRepository_UpdateByEmail(t *testing.T) {
 	t.Parallel()
 
 	ctx := logger.ContextWithLogger(context.Background(), zap.L())
-	expectedSQL := "UPDATE users SET username = $1, email = $2, bio = $3, image = $4, updated_at = $5 WHERE email = $6 RETURNING id, username, email, password, bio, image, created_at" //nolint:lll
-	email := faker.Email()
+	queryCtx := postgres.WithQueryName(ctx, "update_user_by_email")
+	expectedSQL := "UPDATE users SET email = $1, bio = $2, image = $3, updated_at = $4 WHERE (lower(email) = $5 AND deleted_at IS NULL) RETURNING id, username, email, password, bio, image, private, created_at" //nolint:lll
+	email := strings.ToLower(faker.Email())
 	dtoEmail := faker.Email()
-	dtoUsername := faker.Username()
 	dtoBio := faker.Sentence()
 	dtoImage := faker.URL()
 	now := time.Now()
 	dto := user.UpdateDTO{
 		Email:     &dtoEmail,
-		Username:  &dtoUsername,
 		Bio:       &dtoBio,
 		Image:     &dtoImage,
 		UpdatedAt: now,
@@ -267,9 +390,10 @@ func TestUserRepository_UpdateByEmail(t *testing.T) {
 					gomock.Any(),
 					gomock.Any(),
 					gomock.Any(),
+					gomock.Any(),
 				).Return(nil).Times(1)
 				pool.EXPECT().
-					QueryRow(ctx, expectedSQL, *dto.Username, *dto.Email, *dto.Bio, *dto.Image, dto.UpdatedAt, email).
+					QueryRow(queryCtx, expectedSQL, *dto.Email, *dto.Bio, *dto.Image, dto.UpdatedAt, email).
 					Return(row).
 					Times(1)
 			},
@@ -290,9 +414,10 @@ func TestUserRepository_UpdateByEmail(t *testing.T) {
 					gomock.Any(),
 					gomock.Any(),
 					gomock.Any(),
+					gomock.Any(),
 				).Return(errUserRepository).Times(1)
 				pool.EXPECT().
-					QueryRow(ctx, expectedSQL, *dto.Username, *dto.Email, *dto.Bio, *dto.Image, dto.UpdatedAt, email).
+					QueryRow(queryCtx, expectedSQL, *dto.Email, *dto.Bio, *dto.Image, dto.UpdatedAt, email).
 					Return(row).
 					Times(1)
 			},
@@ -314,9 +439,10 @@ func TestUserRepository_UpdateByEmail(t *testing.T) {
 					gomock.Any(),
 					gomock.Any(),
 					gomock.Any(),
+					gomock.Any(),
 				).Return(pgx.ErrNoRows).Times(1)
 				pool.EXPECT().
-					QueryRow(ctx, expectedSQL, *dto.Username, *dto.Email, *dto.Bio, *dto.Image, dto.UpdatedAt, email).
+					QueryRow(queryCtx, expectedSQL, *dto.Email, *dto.Bio, *dto.Image, dto.UpdatedAt, email).
 					Return(row).
 					Times(1)
 			},
@@ -345,3 +471,517 @@ func TestUserRepository_UpdateByEmail(t *testing.T) {
 		})
 	}
 }
+
+func TestUserRepository_UpdateByEmailRename(t *testing.T) {
+	t.Parallel()
+
+	ctx := logger.ContextWithLogger(context.Background(), zap.L())
+	queryCtx := postgres.WithQueryName(ctx, "update_user_by_email")
+	expectedSQL := `WITH renamed AS (
+	SELECT id, username FROM users WHERE lower(email) = $1 AND deleted_at IS NULL AND lower(username) <> lower($2)
+), history AS (
+	INSERT INTO username_history (user_id, username, renamed_at) SELECT id, username, $3 FROM renamed
+), cooldown AS (
+	INSERT INTO quarantined_usernames (username, quarantined_until, user_id)
+	SELECT lower(username), $4, id FROM renamed
+	ON CONFLICT (username) DO UPDATE SET quarantined_until = EXCLUDED.quarantined_until, user_id = EXCLUDED.user_id
+) UPDATE users SET username = $5, updated_at = $6 WHERE (lower(email) = $7 AND deleted_at IS NULL) RETURNING id, username, email, password, bio, image, private, created_at` //nolint:lll
+	email := strings.ToLower(faker.Email())
+	dtoUsername := faker.Username()
+	now := time.Now()
+	dto := user.UpdateDTO{
+		Username:              &dtoUsername,
+		UpdatedAt:             now,
+		UsernameCooldownUntil: now.Add(time.Hour),
+	}
+
+	tests := []struct {
+		name    string
+		scanErr error
+		wantErr error
+	}{
+		{
+			name: "success rename",
+		},
+		{
+			name:    "username is taken",
+			scanErr: &pgconn.PgError{Code: pgerrcode.UniqueViolation, ConstraintName: "users_username_lower_key"},
+			wantErr: user.ErrUsernameAlreadyExist,
+		},
+		{
+			name:    "email is taken",
+			scanErr: &pgconn.PgError{Code: pgerrcode.UniqueViolation, ConstraintName: "users_email_lower_key"},
+			wantErr: user.ErrEmailAlreadyExist,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			userRepository, mockPgxPool, mockRow := mockUserRepository(t)
+
+			mockRow.EXPECT().Scan(
+				gomock.Any(),
+				gomock.Any(),
+				gomock.Any(),
+				gomock.Any(),
+				gomock.Any(),
+				gomock.Any(),
+				gomock.Any(),
+				gomock.Any(),
+			).Return(tt.scanErr).Times(1)
+			mockPgxPool.EXPECT().QueryRow(
+				queryCtx,
+				expectedSQL,
+				email,
+				dtoUsername,
+				now,
+				dto.UsernameCooldownUntil,
+				dtoUsername,
+				now,
+				email,
+			).Return(mockRow).Times(1)
+
+			_, err := userRepository.UpdateByEmail(ctx, email, dto)
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestUserRepository_UpdateByEmailPublish(t *testing.T) {
+	t.Parallel()
+
+	ctx := logger.ContextWithLogger(context.Background(), zap.L())
+	queryCtx := postgres.WithQueryName(ctx, "update_user_by_email")
+	expectedSQL := `WITH published AS (
+	DELETE FROM follow_requests
+	WHERE followee_id IN (SELECT id FROM users WHERE lower(email) = $1 AND deleted_at IS NULL)
+	RETURNING followee_id, follower_id
+), approved AS (
+	INSERT INTO follows (followee_id, follower_id) SELECT followee_id, follower_id FROM published
+	ON CONFLICT (followee_id, follower_id) DO NOTHING
+) UPDATE users SET private = $2, updated_at = $3 WHERE (lower(email) = $4 AND deleted_at IS NULL) RETURNING id, username, email, password, bio, image, private, created_at` //nolint:lll
+	email := strings.ToLower(faker.Email())
+	private := false
+	now := time.Now()
+	dto := user.UpdateDTO{
+		Private:   &private,
+		UpdatedAt: now,
+	}
+
+	userRepository, mockPgxPool, mockRow := mockUserRepository(t)
+
+	mockRow.EXPECT().Scan(
+		gomock.Any(),
+		gomock.Any(),
+		gomock.Any(),
+		gomock.Any(),
+		gomock.Any(),
+		gomock.Any(),
+		gomock.Any(),
+		gomock.Any(),
+	).Return(nil).Times(1)
+	mockPgxPool.EXPECT().QueryRow(
+		queryCtx,
+		expectedSQL,
+		email,
+		private,
+		now,
+		email,
+	).Return(mockRow).Times(1)
+
+	_, err := userRepository.UpdateByEmail(ctx, email, dto)
+	require.NoError(t, err)
+}
+
+func TestUserRepository_DeleteByEmail(t *testing.T) {
+	t.Parallel()
+
+	ctx := logger.ContextWithLogger(context.Background(), zap.L())
+	queryCtx := postgres.WithQueryName(ctx, "delete_user_by_email")
+	expectedSQL := "UPDATE users SET deleted_at = $1 WHERE (lower(email) = $2 AND deleted_at IS NULL)"
+	email := strings.ToLower(faker.Email())
+	deletedAt := time.Now()
+
+	type args struct {
+		email     string
+		deletedAt time.Time
+	}
+
+	tests := []struct {
+		name    string
+		mock    func(*mockPsql.MockPgxPool)
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success delete by email",
+			mock: func(pool *mockPsql.MockPgxPool) {
+				pool.EXPECT().
+					Exec(queryCtx, expectedSQL, deletedAt, email).
+					Return(pgconn.CommandTag("UPDATE 1"), nil).
+					Times(1)
+			},
+			args: args{
+				email:     email,
+				deletedAt: deletedAt,
+			},
+		},
+		{
+			name: "not found error",
+			mock: func(pool *mockPsql.MockPgxPool) {
+				pool.EXPECT().
+					Exec(queryCtx, expectedSQL, deletedAt, email).
+					Return(pgconn.CommandTag("UPDATE 0"), nil).
+					Times(1)
+			},
+			args: args{
+				email:     email,
+				deletedAt: deletedAt,
+			},
+			wantErr: true,
+		},
+		{
+			name: "exec error",
+			mock: func(pool *mockPsql.MockPgxPool) {
+				pool.EXPECT().Exec(queryCtx, expectedSQL, deletedAt, email).Return(nil, errUserRepository).Times(1)
+			},
+			args: args{
+				email:     email,
+				deletedAt: deletedAt,
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			userRepository, mockPgxPool, _ := mockUserRepository(t)
+
+			tt.mock(mockPgxPool)
+
+			err := userRepository.DeleteByEmail(ctx, tt.args.email, tt.args.deletedAt)
+			require.True(t, (err != nil) == tt.wantErr)
+		})
+	}
+}
+
+func TestUserRepository_Restore(t *testing.T) {
+	t.Parallel()
+
+	ctx := logger.ContextWithLogger(context.Background(), zap.L())
+	queryCtx := postgres.WithQueryName(ctx, "restore_user")
+	expectedSQL := "UPDATE users SET deleted_at = $1 WHERE (id = $2 AND purged_at IS NULL)"
+	id := uuid.New()
+
+	tests := []struct {
+		name    string
+		mock    func(*mockPsql.MockPgxPool)
+		wantErr bool
+	}{
+		{
+			name: "success restore",
+			mock: func(pool *mockPsql.MockPgxPool) {
+				pool.EXPECT().
+					Exec(queryCtx, expectedSQL, nil, id.String()).
+					Return(pgconn.CommandTag("UPDATE 1"), nil).
+					Times(1)
+			},
+		},
+		{
+			name: "already purged",
+			mock: func(pool *mockPsql.MockPgxPool) {
+				pool.EXPECT().
+					Exec(queryCtx, expectedSQL, nil, id.String()).
+					Return(pgconn.CommandTag("UPDATE 0"), nil).
+					Times(1)
+			},
+			wantErr: true,
+		},
+		{
+			name: "exec error",
+			mock: func(pool *mockPsql.MockPgxPool) {
+				pool.EXPECT().Exec(queryCtx, expectedSQL, nil, id.String()).Return(nil, errUserRepository).Times(1)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			userRepository, mockPgxPool, _ := mockUserRepository(t)
+
+			tt.mock(mockPgxPool)
+
+			err := userRepository.Restore(ctx, id)
+			require.True(t, (err != nil) == tt.wantErr)
+		})
+	}
+}
+
+func TestUserRepository_Purge(t *testing.T) {
+	t.Parallel()
+
+	ctx := logger.ContextWithLogger(context.Background(), zap.L())
+	queryCtx := postgres.WithQueryName(ctx, "purge_users")
+	now := time.Now()
+	dto := user.PurgeDTO{
+		DeletedBefore:    now.Add(-time.Hour),
+		QuarantinedUntil: now.Add(time.Hour),
+		PurgedAt:         now,
+	}
+
+	type args struct {
+		mode user.DeletionMode
+	}
+
+	tests := []struct {
+		name    string
+		mock    func(*mockPsql.MockPgxPool)
+		args    args
+		want    int64
+		wantErr bool
+	}{
+		{
+			name: "anonymize",
+			mock: func(pool *mockPsql.MockPgxPool) {
+				pool.EXPECT().
+					Exec(queryCtx, gomock.Any(), dto.DeletedBefore, dto.QuarantinedUntil, dto.PurgedAt).
+					Return(pgconn.CommandTag("UPDATE 3"), nil).
+					Times(1)
+			},
+			args: args{
+				mode: user.DeletionModeAnonymize,
+			},
+			want: 3,
+		},
+		{
+			name: "cascade",
+			mock: func(pool *mockPsql.MockPgxPool) {
+				pool.EXPECT().
+					Exec(queryCtx, gomock.Any(), dto.DeletedBefore, dto.QuarantinedUntil).
+					Return(pgconn.CommandTag("DELETE 2"), nil).
+					Times(1)
+			},
+			args: args{
+				mode: user.DeletionModeCascade,
+			},
+			want: 2,
+		},
+		{
+			name: "unknown mode",
+			mock: func(pool *mockPsql.MockPgxPool) {},
+			args: args{
+				mode: user.DeletionMode("unknown"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "exec error",
+			mock: func(pool *mockPsql.MockPgxPool) {
+				pool.EXPECT().
+					Exec(queryCtx, gomock.Any(), dto.DeletedBefore, dto.QuarantinedUntil).
+					Return(nil, errUserRepository).
+					Times(1)
+			},
+			args: args{
+				mode: user.DeletionModeCascade,
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			userRepository, mockPgxPool, _ := mockUserRepository(t)
+
+			tt.mock(mockPgxPool)
+
+			purgeDTO := dto
+			purgeDTO.Mode = tt.args.mode
+
+			got, err := userRepository.Purge(ctx, purgeDTO)
+			require.True(t, (err != nil) == tt.wantErr)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestUserRepository_CheckUsernameQuarantine(t *testing.T) {
+	t.Parallel()
+
+	ctx := logger.ContextWithLogger(context.Background(), zap.L())
+	queryCtx := postgres.WithQueryName(ctx, "check_username_quarantine")
+	expectedSQL := "SELECT username FROM quarantined_usernames WHERE (username = $1 AND quarantined_until > $2 AND (user_id IS NULL OR user_id <> $3)) LIMIT 1" //nolint:lll
+	username := strings.ToLower(faker.Username())
+	userID := uuid.New()
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		mock    func(*mockPsql.MockRow, *mockPsql.MockPgxPool)
+		wantErr error
+	}{
+		{
+			name: "username is available",
+			mock: func(row *mockPsql.MockRow, pool *mockPsql.MockPgxPool) {
+				row.EXPECT().Scan(gomock.Any()).Return(pgx.ErrNoRows).Times(1)
+				pool.EXPECT().QueryRow(queryCtx, expectedSQL, username, now, userID.String()).Return(row).Times(1)
+			},
+		},
+		{
+			name: "username is quarantined",
+			mock: func(row *mockPsql.MockRow, pool *mockPsql.MockPgxPool) {
+				row.EXPECT().Scan(gomock.Any()).Return(nil).Times(1)
+				pool.EXPECT().QueryRow(queryCtx, expectedSQL, username, now, userID.String()).Return(row).Times(1)
+			},
+			wantErr: user.ErrUsernameQuarantined,
+		},
+		{
+			name: "scan error",
+			mock: func(row *mockPsql.MockRow, pool *mockPsql.MockPgxPool) {
+				row.EXPECT().Scan(gomock.Any()).Return(errUserRepository).Times(1)
+				pool.EXPECT().QueryRow(queryCtx, expectedSQL, username, now, userID.String()).Return(row).Times(1)
+			},
+			wantErr: errUserRepository,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			userRepository, mockPgxPool, mockRow := mockUserRepository(t)
+
+			tt.mock(mockRow, mockPgxPool)
+
+			err := userRepository.CheckUsernameQuarantine(ctx, username, userID, now)
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestUserRepository_CountRenames(t *testing.T) {
+	t.Parallel()
+
+	ctx := logger.ContextWithLogger(context.Background(), zap.L())
+	queryCtx := postgres.WithQueryName(ctx, "count_renames")
+	expectedSQL := "SELECT count(*) FROM username_history WHERE (user_id = $1 AND renamed_at >= $2)"
+	userID := uuid.New()
+	since := time.Now()
+
+	tests := []struct {
+		name    string
+		mock    func(*mockPsql.MockRow, *mockPsql.MockPgxPool)
+		want    int
+		wantErr bool
+	}{
+		{
+			name: "success count renames",
+			mock: func(row *mockPsql.MockRow, pool *mockPsql.MockPgxPool) {
+				row.EXPECT().Scan(gomock.Any()).DoAndReturn(func(dest ...interface{}) error {
+					*dest[0].(*int) = 2
+
+					return nil
+				}).Times(1)
+				pool.EXPECT().QueryRow(queryCtx, expectedSQL, userID.String(), since).Return(row).Times(1)
+			},
+			want: 2,
+		},
+		{
+			name: "scan error",
+			mock: func(row *mockPsql.MockRow, pool *mockPsql.MockPgxPool) {
+				row.EXPECT().Scan(gomock.Any()).Return(errUserRepository).Times(1)
+				pool.EXPECT().QueryRow(queryCtx, expectedSQL, userID.String(), since).Return(row).Times(1)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			userRepository, mockPgxPool, mockRow := mockUserRepository(t)
+
+			tt.mock(mockRow, mockPgxPool)
+
+			got, err := userRepository.CountRenames(ctx, userID, since)
+			require.True(t, (err != nil) == tt.wantErr)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestUserRepository_ReadsFromReplica(t *testing.T) {
+	t.Parallel()
+
+	ctx := logger.ContextWithLogger(context.Background(), zap.L())
+	mockCtl := gomock.NewController(t)
+	primary := mockPsql.NewMockPgxPool(mockCtl)
+	replica := mockPsql.NewMockPgxPool(mockCtl)
+	mockRow := mockPsql.NewMockRow(mockCtl)
+
+	db := &postgres.Postgres{
+		Builder: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+		Pool:    primary,
+	}
+	db.AddReplica(replica)
+
+	userRepository := psql.NewUserRepository(db)
+
+	mockRow.EXPECT().Scan(gomock.Any()).Return(nil).Times(1)
+	replica.EXPECT().QueryRow(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(mockRow).Times(1)
+
+	_, err := userRepository.CountRenames(ctx, uuid.New(), time.Now())
+	require.NoError(t, err)
+
+	mockRow.EXPECT().Scan(gomock.Any()).Return(pgx.ErrNoRows).Times(1)
+	replica.EXPECT().QueryRow(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(mockRow).Times(1)
+
+	require.NoError(t, userRepository.CheckUsernameQuarantine(ctx, "alice", uuid.New(), time.Now()))
+
+	email := faker.Email()
+
+	// The lock can not be taken on a replica.
+	mockRow.EXPECT().Scan(
+		gomock.Any(),
+		gomock.Any(),
+		gomock.Any(),
+		gomock.Any(),
+		gomock.Any(),
+		gomock.Any(),
+		gomock.Any(),
+		gomock.Any(),
+		gomock.Any(),
+		gomock.Any(),
+	).Return(nil).Times(2)
+	replica.EXPECT().QueryRow(gomock.Any(), gomock.Any(), strings.ToLower(email)).Return(mockRow).Times(1)
+	primary.EXPECT().QueryRow(gomock.Any(), gomock.Any(), strings.ToLower(email)).Return(mockRow).Times(1)
+
+	_, err = userRepository.GetByEmail(ctx, email)
+	require.NoError(t, err)
+
+	_, err = userRepository.LockByEmail(ctx, email)
+	require.NoError(t, err)
+}
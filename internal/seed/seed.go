@@ -0,0 +1,312 @@
+// Package seed generates demo data for profiling queries at a realistic scale.
+package seed
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bxcodec/faker/v3"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/maypok86/conduit/pkg/logger"
+	"github.com/maypok86/conduit/pkg/postgres"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultUsers      = 1000
+	defaultMaxFollows = 100
+	defaultSeed       = 1
+	defaultPassword   = "password"
+
+	// followeeExponent shapes the popularity of users: a few users get most of the followers.
+	followeeExponent = 1.1
+	// followsExponent shapes the activity of users: most users follow a few others.
+	followsExponent = 1.5
+	// period is the time range the seeded users and follows are created in.
+	period = 365 * 24 * time.Hour
+)
+
+var (
+	// epoch is fixed so that the same seed generates the same timestamps.
+	epoch     = time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC)
+	namespace = uuid.MustParse("6f1c1a1e-2b9e-4f55-9a59-1d5c0cf7d1a4")
+)
+
+// User is a generated user.
+type User struct {
+	ID        uuid.UUID
+	Username  string
+	Email     string
+	Bio       string
+	Image     string
+	CreatedAt time.Time
+}
+
+// Follow is a generated follow.
+type Follow struct {
+	FolloweeID uuid.UUID
+	FollowerID uuid.UUID
+	CreatedAt  time.Time
+}
+
+// Dataset is the generated demo data.
+type Dataset struct {
+	Users   []User
+	Follows []Follow
+}
+
+// Generate generates users with a power-law follow graph. The same arguments always generate the same dataset.
+//
+// Generate sets the random source of faker, so it must not run concurrently with other faker users.
+func Generate(seed int64, users, maxFollows int) Dataset {
+	faker.SetRandomSource(rand.NewSource(seed))
+	random := rand.New(rand.NewSource(seed)) //nolint:gosec
+
+	dataset := Dataset{
+		Users: make([]User, 0, users),
+	}
+
+	for i := 0; i < users; i++ {
+		id := uuid.NewSHA1(namespace, []byte(strconv.FormatInt(seed, 10)+"/"+strconv.Itoa(i)))
+		username := strings.ToLower(faker.FirstName() + "_" + faker.LastName() + strconv.Itoa(i))
+
+		dataset.Users = append(dataset.Users, User{
+			ID:        id,
+			Username:  username,
+			Email:     username + "@example.com",
+			Bio:       faker.Sentence(),
+			Image:     "https://i.pravatar.cc/300?u=" + id.String(),
+			CreatedAt: epoch.Add(time.Duration(random.Int63n(int64(period)))),
+		})
+	}
+
+	if users < 2 || maxFollows < 1 {
+		return dataset
+	}
+
+	followees := rand.NewZipf(random, followeeExponent, 1, uint64(users-1))
+	follows := rand.NewZipf(random, followsExponent, 1, uint64(maxFollows-1))
+
+	for followerIndex, follower := range dataset.Users {
+		count := int(follows.Uint64()) + 1
+		followed := make(map[int]struct{}, count)
+
+		// Popular users are picked repeatedly, so the attempts are limited to keep the generation fast.
+		for attempt := 0; len(followed) < count && attempt < 3*count; attempt++ {
+			followeeIndex := int(followees.Uint64())
+			if followeeIndex == followerIndex {
+				continue
+			}
+
+			if _, ok := followed[followeeIndex]; ok {
+				continue
+			}
+
+			followed[followeeIndex] = struct{}{}
+
+			followee := dataset.Users[followeeIndex]
+
+			createdAt := followee.CreatedAt
+			if follower.CreatedAt.After(createdAt) {
+				createdAt = follower.CreatedAt
+			}
+
+			dataset.Follows = append(dataset.Follows, Follow{
+				FolloweeID: followee.ID,
+				FollowerID: follower.ID,
+				CreatedAt:  createdAt.Add(time.Duration(random.Int63n(int64(time.Hour)))),
+			})
+		}
+	}
+
+	return dataset
+}
+
+// PasswordHasher is a password hasher.
+type PasswordHasher interface {
+	Hash(plain string) (string, error)
+}
+
+// Result is the number of inserted rows, rows that already existed are not counted.
+type Result struct {
+	Users   int64
+	Follows int64
+}
+
+// Seeder loads a generated dataset into postgres.
+type Seeder struct {
+	db             *postgres.Postgres
+	passwordHasher PasswordHasher
+	users          int
+	maxFollows     int
+	seed           int64
+	password       string
+}
+
+// Option is a functional option for configuring a Seeder.
+type Option func(*Seeder)
+
+// WithUsers sets the number of generated users.
+func WithUsers(users int) Option {
+	return func(s *Seeder) {
+		s.users = users
+	}
+}
+
+// WithMaxFollows sets the max number of users a single user follows.
+func WithMaxFollows(maxFollows int) Option {
+	return func(s *Seeder) {
+		s.maxFollows = maxFollows
+	}
+}
+
+// WithSeed sets the seed of the generated data.
+func WithSeed(seed int64) Option {
+	return func(s *Seeder) {
+		s.seed = seed
+	}
+}
+
+// WithPassword sets the password of every generated user.
+func WithPassword(password string) Option {
+	return func(s *Seeder) {
+		s.password = password
+	}
+}
+
+// New creates a new Seeder.
+func New(db *postgres.Postgres, passwordHasher PasswordHasher, opts ...Option) *Seeder {
+	s := &Seeder{
+		db:             db,
+		passwordHasher: passwordHasher,
+		users:          defaultUsers,
+		maxFollows:     defaultMaxFollows,
+		seed:           defaultSeed,
+		password:       defaultPassword,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Run generates the dataset and inserts it. Rows that already exist are skipped, so running the same seed again
+// inserts nothing.
+func (s *Seeder) Run(ctx context.Context) (Result, error) {
+	started := time.Now()
+	dataset := Generate(s.seed, s.users, s.maxFollows)
+
+	logger.FromContext(ctx).Info(
+		"demo data is generated",
+		zap.Int("users", len(dataset.Users)),
+		zap.Int("follows", len(dataset.Follows)),
+		zap.Duration("duration", time.Since(started)),
+	)
+
+	// Every user gets the same password, hashing it once keeps seeding fast.
+	passwordHash, err := s.passwordHasher.Hash(s.password)
+	if err != nil {
+		return Result{}, fmt.Errorf("can not hash password: %w", err)
+	}
+
+	tx, err := s.db.Pool.Begin(ctx)
+	if err != nil {
+		return Result{}, fmt.Errorf("can not begin transaction: %w", err)
+	}
+
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	var result Result
+
+	result.Users, err = copyUsers(ctx, tx, dataset.Users, passwordHash)
+	if err != nil {
+		return Result{}, err
+	}
+
+	result.Follows, err = copyFollows(ctx, tx, dataset.Follows)
+	if err != nil {
+		return Result{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return Result{}, fmt.Errorf("can not commit transaction: %w", err)
+	}
+
+	return result, nil
+}
+
+// copyUsers copies the users into a temporary table, CopyFrom can not skip the existing rows by itself.
+func copyUsers(ctx context.Context, tx pgx.Tx, users []User, passwordHash string) (int64, error) {
+	createSQL := "CREATE TEMPORARY TABLE seed_users (LIKE users INCLUDING DEFAULTS) ON COMMIT DROP"
+	if _, err := tx.Exec(ctx, createSQL); err != nil {
+		return 0, fmt.Errorf("can not create seed users table: %w", err)
+	}
+
+	columns := []string{"id", "email", "username", "password", "bio", "image", "created_at", "updated_at"}
+
+	_, err := tx.CopyFrom(
+		ctx,
+		pgx.Identifier{"seed_users"},
+		columns,
+		pgx.CopyFromSlice(len(users), func(i int) ([]interface{}, error) {
+			u := users[i]
+
+			return []interface{}{u.ID, u.Email, u.Username, passwordHash, u.Bio, u.Image, u.CreatedAt, u.CreatedAt}, nil
+		}),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("can not copy users: %w", err)
+	}
+
+	commandTag, err := tx.Exec(ctx, `INSERT INTO users (id, email, username, password, bio, image, created_at, updated_at)
+SELECT id, email, username, password, bio, image, created_at, updated_at FROM seed_users
+ON CONFLICT DO NOTHING`)
+	if err != nil {
+		return 0, fmt.Errorf("can not insert users: %w", err)
+	}
+
+	return commandTag.RowsAffected(), nil
+}
+
+// copyFollows copies the follows into a temporary table. Follows of users that were skipped because
+// their username or email is taken are skipped too.
+func copyFollows(ctx context.Context, tx pgx.Tx, follows []Follow) (int64, error) {
+	createSQL := "CREATE TEMPORARY TABLE seed_follows (LIKE follows INCLUDING DEFAULTS) ON COMMIT DROP"
+	if _, err := tx.Exec(ctx, createSQL); err != nil {
+		return 0, fmt.Errorf("can not create seed follows table: %w", err)
+	}
+
+	_, err := tx.CopyFrom(
+		ctx,
+		pgx.Identifier{"seed_follows"},
+		[]string{"followee_id", "follower_id", "created_at", "updated_at"},
+		pgx.CopyFromSlice(len(follows), func(i int) ([]interface{}, error) {
+			f := follows[i]
+
+			return []interface{}{f.FolloweeID, f.FollowerID, f.CreatedAt, f.CreatedAt}, nil
+		}),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("can not copy follows: %w", err)
+	}
+
+	commandTag, err := tx.Exec(ctx, `INSERT INTO follows (followee_id, follower_id, created_at, updated_at)
+SELECT followee_id, follower_id, created_at, updated_at FROM seed_follows
+WHERE EXISTS (SELECT 1 FROM users WHERE users.id = seed_follows.followee_id)
+	AND EXISTS (SELECT 1 FROM users WHERE users.id = seed_follows.follower_id)
+ON CONFLICT DO NOTHING`)
+	if err != nil {
+		return 0, fmt.Errorf("can not insert follows: %w", err)
+	}
+
+	return commandTag.RowsAffected(), nil
+}
@@ -0,0 +1,66 @@
+package seed_test
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/maypok86/conduit/internal/seed"
+	"github.com/stretchr/testify/require"
+)
+
+// Generate sets the random source of faker, so the test does not run in parallel.
+func TestGenerate(t *testing.T) { //nolint:paralleltest
+	const (
+		users      = 2000
+		maxFollows = 50
+	)
+
+	dataset := seed.Generate(1, users, maxFollows)
+	require.Len(t, dataset.Users, users)
+	require.NotEmpty(t, dataset.Follows)
+
+	require.True(t, reflect.DeepEqual(dataset, seed.Generate(1, users, maxFollows)))
+	require.False(t, reflect.DeepEqual(dataset.Users, seed.Generate(2, users, maxFollows).Users))
+
+	usernames := make(map[string]struct{}, users)
+	for _, u := range dataset.Users {
+		usernames[u.Username] = struct{}{}
+	}
+
+	require.Len(t, usernames, users)
+
+	type pair struct {
+		followee uuid.UUID
+		follower uuid.UUID
+	}
+
+	follows := make(map[pair]struct{}, len(dataset.Follows))
+	following := make(map[uuid.UUID]int, users)
+	followers := make(map[uuid.UUID]int, users)
+
+	for _, f := range dataset.Follows {
+		require.NotEqual(t, f.FolloweeID, f.FollowerID)
+
+		follows[pair{followee: f.FolloweeID, follower: f.FollowerID}] = struct{}{}
+		following[f.FollowerID]++
+		followers[f.FolloweeID]++
+	}
+
+	require.Len(t, follows, len(dataset.Follows))
+
+	for _, count := range following {
+		require.LessOrEqual(t, count, maxFollows)
+	}
+
+	counts := make([]int, 0, len(followers))
+	for _, count := range followers {
+		counts = append(counts, count)
+	}
+
+	sort.Sort(sort.Reverse(sort.IntSlice(counts)))
+
+	// A power-law graph has a few very popular users and a long tail.
+	require.Greater(t, counts[0], 10*counts[len(counts)/2])
+}
@@ -0,0 +1,81 @@
+// Package testserver serves the api by the real router over the memory storage, for the tests that talk to it
+// over http.
+package testserver
+
+import (
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/maypok86/conduit/api/openapi"
+	httphandler "github.com/maypok86/conduit/internal/controller/http/handler"
+	"github.com/maypok86/conduit/internal/controller/http/middleware"
+	"github.com/maypok86/conduit/internal/domain"
+	"github.com/maypok86/conduit/internal/repository/memory"
+	"github.com/maypok86/conduit/pkg/hash"
+	"github.com/maypok86/conduit/pkg/token"
+	"github.com/maypok86/conduit/pkg/worker"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// env is the configuration the router reads.
+var env = map[string]string{
+	"ENVIRONMENT":        "test",
+	"HTTP_HOST":          "localhost",
+	"HTTP_PORT":          "8080",
+	"STORAGE_DRIVER":     "memory",
+	"TOKEN_SECRET_KEY":   "testserver-package-token-secret-key",
+	"CORS_ALLOW_ORIGINS": "http://localhost:3000",
+}
+
+// Main sets the configuration the router reads and runs the tests, it is meant to be called from TestMain.
+func Main(m *testing.M) {
+	for key, value := range env {
+		if err := os.Setenv(key, value); err != nil {
+			panic(err)
+		}
+	}
+
+	gin.SetMode(gin.TestMode)
+
+	os.Exit(m.Run())
+}
+
+// New starts a server with empty storage, it is closed when the test ends. Mismatches with the specification
+// fail the requests, so the tests catch the contract drift too.
+func New(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	tokenMaker, err := token.NewJWTMaker(env["TOKEN_SECRET_KEY"])
+	require.NoError(t, err)
+
+	openAPI, err := middleware.NewOpenAPI(
+		openapi.Spec,
+		middleware.WithStrictValidation(true),
+		middleware.WithResponseValidation(true),
+	)
+	require.NoError(t, err)
+
+	store := memory.NewStore()
+	server := httptest.NewServer(httphandler.NewRouter(httphandler.Deps{
+		TokenMaker: tokenMaker,
+		Logger:     zap.NewNop(),
+		Services: domain.NewServices(domain.Deps{
+			Repositories:     memory.NewRepositories(store),
+			PasswordHasher:   hash.NewArgon2Hasher(),
+			Transactor:       store,
+			ExportDispatcher: worker.New(),
+		}),
+		OpenAPI: &openAPI,
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// URL returns the base url of the api served by server.
+func URL(server *httptest.Server) string {
+	return server.URL + "/api"
+}
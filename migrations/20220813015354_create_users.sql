@@ -16,5 +16,5 @@ CREATE TABLE IF NOT EXISTS users (
 
 -- +goose Down
 -- +goose StatementBegin
-DROP TABLE IF NOT EXISTS users;
+DROP TABLE IF EXISTS users;
 -- +goose StatementEnd
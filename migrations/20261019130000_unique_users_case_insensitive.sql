@@ -0,0 +1,84 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS users_dedupe_log (
+    user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    field text NOT NULL,
+    old_value text NOT NULL,
+    new_value text NOT NULL,
+    created_at timestamp DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+-- The oldest account keeps the value, the others get a suffix derived from their id. A suffixed value can be
+-- taken already, then a counter is appended until the value is free. Every rename is logged, so support can
+-- reach out to the affected users.
+DO $$
+DECLARE
+    duplicate record;
+    suffix text;
+    candidate text;
+    attempt int;
+BEGIN
+    FOR duplicate IN
+        SELECT id, username FROM (
+            SELECT id, username,
+                row_number() OVER (PARTITION BY lower(username) ORDER BY created_at, id) AS position
+            FROM users
+        ) ranked WHERE position > 1
+    LOOP
+        attempt := 0;
+        LOOP
+            suffix := substr(replace(duplicate.id::text, '-', ''), 1, 8);
+            IF attempt > 0 THEN
+                suffix := suffix || attempt::text;
+            END IF;
+            candidate := duplicate.username || suffix;
+            EXIT WHEN NOT EXISTS (SELECT 1 FROM users WHERE lower(username) = lower(candidate));
+            attempt := attempt + 1;
+        END LOOP;
+
+        INSERT INTO users_dedupe_log (user_id, field, old_value, new_value)
+        VALUES (duplicate.id, 'username', duplicate.username, candidate);
+        UPDATE users SET username = candidate WHERE id = duplicate.id;
+    END LOOP;
+
+    FOR duplicate IN
+        SELECT id, email FROM (
+            SELECT id, email,
+                row_number() OVER (PARTITION BY lower(email) ORDER BY created_at, id) AS position
+            FROM users
+        ) ranked WHERE position > 1
+    LOOP
+        attempt := 0;
+        LOOP
+            suffix := substr(replace(duplicate.id::text, '-', ''), 1, 8);
+            IF attempt > 0 THEN
+                suffix := suffix || attempt::text;
+            END IF;
+            candidate := split_part(duplicate.email, '@', 1) || '+' || suffix || '@'
+                || split_part(duplicate.email, '@', 2);
+            EXIT WHEN NOT EXISTS (SELECT 1 FROM users WHERE lower(email) = lower(candidate));
+            attempt := attempt + 1;
+        END LOOP;
+
+        INSERT INTO users_dedupe_log (user_id, field, old_value, new_value)
+        VALUES (duplicate.id, 'email', duplicate.email, candidate);
+        UPDATE users SET email = candidate WHERE id = duplicate.id;
+    END LOOP;
+END
+$$;
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_email_key;
+
+CREATE UNIQUE INDEX IF NOT EXISTS users_email_lower_key ON users (lower(email));
+CREATE UNIQUE INDEX IF NOT EXISTS users_username_lower_key ON users (lower(username));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS users_username_lower_key;
+DROP INDEX IF EXISTS users_email_lower_key;
+
+ALTER TABLE users ADD CONSTRAINT users_email_key UNIQUE (email);
+
+DROP TABLE IF EXISTS users_dedupe_log;
+-- +goose StatementEnd
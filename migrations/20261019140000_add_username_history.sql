@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS username_history (
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    username text NOT NULL,
+    renamed_at timestamp DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS username_history_username_idx ON username_history (lower(username), renamed_at DESC);
+CREATE INDEX IF NOT EXISTS username_history_user_id_renamed_at_idx ON username_history (user_id, renamed_at DESC);
+
+-- The owner of a released username can claim it back during the cooldown, everybody else can not.
+ALTER TABLE quarantined_usernames ADD COLUMN IF NOT EXISTS user_id uuid REFERENCES users (id) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE quarantined_usernames DROP COLUMN IF EXISTS user_id;
+DROP TABLE IF EXISTS username_history;
+-- +goose StatementEnd
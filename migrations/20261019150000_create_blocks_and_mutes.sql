@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS blocks (
+    blocker_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    blocked_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    created_at timestamp DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    CHECK (blocker_id != blocked_id),
+    PRIMARY KEY (blocker_id, blocked_id)
+);
+
+CREATE INDEX IF NOT EXISTS blocks_blocked_id_idx ON blocks (blocked_id);
+
+CREATE TABLE IF NOT EXISTS mutes (
+    muter_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    muted_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    created_at timestamp DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    CHECK (muter_id != muted_id),
+    PRIMARY KEY (muter_id, muted_id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS mutes;
+DROP TABLE IF EXISTS blocks;
+-- +goose StatementEnd
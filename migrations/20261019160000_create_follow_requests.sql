@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users ADD COLUMN IF NOT EXISTS private boolean DEFAULT false NOT NULL;
+
+CREATE TABLE IF NOT EXISTS follow_requests (
+    followee_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    follower_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    created_at timestamp DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    CHECK (followee_id != follower_id),
+    PRIMARY KEY (followee_id, follower_id)
+);
+
+CREATE INDEX IF NOT EXISTS follow_requests_follower_id_idx ON follow_requests (follower_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS follow_requests;
+ALTER TABLE users DROP COLUMN IF EXISTS private;
+-- +goose StatementEnd
@@ -0,0 +1,9 @@
+// Package migrations embeds the goose sql migrations into the binary.
+package migrations
+
+import "embed"
+
+// FS contains the sql migrations.
+//
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,28 @@
+package migrations_test
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/maypok86/conduit/migrations"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFS(t *testing.T) {
+	t.Parallel()
+
+	files, err := fs.Glob(migrations.FS, "*.sql")
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	for _, file := range files {
+		content, err := fs.ReadFile(migrations.FS, file)
+		require.NoError(t, err)
+
+		migration := string(content)
+		require.Contains(t, migration, "-- +goose Up", file)
+		require.Contains(t, migration, "-- +goose Down", file)
+		require.NotContains(t, strings.ToUpper(migration), "DROP TABLE IF NOT EXISTS", file)
+	}
+}
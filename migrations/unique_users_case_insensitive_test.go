@@ -0,0 +1,61 @@
+package migrations_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/maypok86/conduit/internal/repository/psql/integration"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	accountDeletionVersion            int64 = 20261019120000
+	uniqueUsersCaseInsensitiveVersion int64 = 20261019130000
+)
+
+func TestUniqueUsersCaseInsensitive(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	schema := integration.NewSchema(t)
+	schema.MigrateTo(t, accountDeletionVersion)
+
+	conn, err := pgx.Connect(ctx, schema.URL)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, conn.Close(ctx))
+	})
+
+	// The duplicates of Jake would be renamed to the values the last two users already have.
+	_, err = conn.Exec(ctx, `INSERT INTO users (id, username, email, password, created_at) VALUES
+		('00000000-0000-0000-0000-000000000001', 'Jake', 'Jake@conduit.io', 'hash', '2022-01-01'),
+		('aaaaaaaa-0000-0000-0000-000000000002', 'jake', 'jake@conduit.io', 'hash', '2022-01-02'),
+		('00000000-0000-0000-0000-000000000003', 'jakeaaaaaaaa', 'jake+aaaaaaaa@conduit.io', 'hash', '2022-01-03'),
+		('00000000-0000-0000-0000-000000000004', 'celeb', 'celeb@conduit.io', 'hash', '2022-01-04')`)
+	require.NoError(t, err)
+
+	schema.MigrateTo(t, uniqueUsersCaseInsensitiveVersion)
+
+	var username, email string
+	require.NoError(t, conn.QueryRow(
+		ctx,
+		"SELECT username, email FROM users WHERE id = 'aaaaaaaa-0000-0000-0000-000000000002'",
+	).Scan(&username, &email))
+	require.Equal(t, "jakeaaaaaaaa1", username)
+	require.Equal(t, "jake+aaaaaaaa1@conduit.io", email)
+
+	require.NoError(t, conn.QueryRow(
+		ctx,
+		"SELECT username, email FROM users WHERE id = '00000000-0000-0000-0000-000000000001'",
+	).Scan(&username, &email))
+	require.Equal(t, "Jake", username)
+	require.Equal(t, "Jake@conduit.io", email)
+
+	var logged int
+	require.NoError(t, conn.QueryRow(ctx, "SELECT count(*) FROM users_dedupe_log").Scan(&logged))
+	require.Equal(t, 2, logged)
+
+	_, err = conn.Exec(ctx, `INSERT INTO users (username, email, password) VALUES ('CELEB', 'other@conduit.io', 'hash')`)
+	require.Error(t, err)
+}
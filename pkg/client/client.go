@@ -0,0 +1,206 @@
+// Package client provides a typed client of the conduit api.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+type auth int
+
+const (
+	// noAuth requests never send the token.
+	noAuth auth = iota
+	// optionalAuth requests send the token if the client has one.
+	optionalAuth
+	// requiredAuth requests send the token and log in again once if it is rejected.
+	requiredAuth
+)
+
+// Client is a client of the conduit api. It keeps the token of the last login and sends it with the requests
+// that need it. The client is safe for concurrent use.
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	userAgent  string
+
+	mu          sync.RWMutex
+	token       string
+	credentials *credentials
+}
+
+type credentials struct {
+	email    string
+	password string
+}
+
+// New returns a new instance of Client for the api at baseURL, for example http://localhost:8080/api.
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("can not parse base url: %w", err)
+	}
+
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("can not use base url %q: scheme and host are required", baseURL)
+	}
+
+	c := &Client{
+		baseURL:    u,
+		httpClient: http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c, nil
+}
+
+// Token returns the token the client authenticates with.
+func (c *Client) Token() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.token
+}
+
+// SetToken sets the token the client authenticates with.
+func (c *Client) SetToken(token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.token = token
+}
+
+type request struct {
+	method string
+	path   string
+	query  url.Values
+	body   interface{}
+	auth   auth
+}
+
+type response struct {
+	statusCode int
+	header     http.Header
+	body       []byte
+}
+
+// do sends the request and decodes the json response into out if out is not nil. An expired or revoked
+// token of a request that needs it is renewed with the credentials once.
+func (c *Client) do(ctx context.Context, req request, out interface{}) (response, error) {
+	resp, err := c.send(ctx, req)
+	if err != nil {
+		return response{}, err
+	}
+
+	if resp.statusCode == http.StatusUnauthorized && req.auth == requiredAuth && c.hasCredentials() {
+		if err := c.relogin(ctx); err != nil {
+			return response{}, err
+		}
+
+		resp, err = c.send(ctx, req)
+		if err != nil {
+			return response{}, err
+		}
+	}
+
+	if resp.statusCode >= http.StatusBadRequest {
+		return response{}, newError(resp.statusCode, resp.body)
+	}
+
+	if out != nil {
+		if err := json.Unmarshal(resp.body, out); err != nil {
+			return response{}, fmt.Errorf("can not decode %s %s response: %w", req.method, req.path, err)
+		}
+	}
+
+	return resp, nil
+}
+
+func (c *Client) send(ctx context.Context, req request) (response, error) {
+	u := *c.baseURL
+	u.Path += req.path
+	u.RawQuery = req.query.Encode()
+
+	var body io.Reader
+	if req.body != nil {
+		data, err := json.Marshal(req.body)
+		if err != nil {
+			return response{}, fmt.Errorf("can not encode %s %s request: %w", req.method, req.path, err)
+		}
+
+		body = bytes.NewReader(data)
+	}
+
+	httpRequest, err := http.NewRequestWithContext(ctx, req.method, u.String(), body)
+	if err != nil {
+		return response{}, fmt.Errorf("can not create %s %s request: %w", req.method, req.path, err)
+	}
+
+	httpRequest.Header.Set("Accept", "application/json")
+	if body != nil {
+		httpRequest.Header.Set("Content-Type", "application/json")
+	}
+
+	if c.userAgent != "" {
+		httpRequest.Header.Set("User-Agent", c.userAgent)
+	}
+
+	if token := c.Token(); token != "" && req.auth != noAuth {
+		httpRequest.Header.Set("Authorization", "Token "+token)
+	}
+
+	httpResponse, err := c.httpClient.Do(httpRequest)
+	if err != nil {
+		return response{}, fmt.Errorf("can not send %s %s request: %w", req.method, req.path, err)
+	}
+	defer httpResponse.Body.Close()
+
+	data, err := io.ReadAll(httpResponse.Body)
+	if err != nil {
+		return response{}, fmt.Errorf("can not read %s %s response: %w", req.method, req.path, err)
+	}
+
+	return response{
+		statusCode: httpResponse.StatusCode,
+		header:     httpResponse.Header,
+		body:       data,
+	}, nil
+}
+
+func (c *Client) hasCredentials() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.credentials != nil
+}
+
+func (c *Client) relogin(ctx context.Context) error {
+	c.mu.RLock()
+	creds := *c.credentials
+	c.mu.RUnlock()
+
+	if _, err := c.Login(ctx, creds.email, creds.password); err != nil {
+		return fmt.Errorf("can not renew token: %w", err)
+	}
+
+	return nil
+}
+
+// rememberEmail keeps the credentials in sync with the email of the current user.
+func (c *Client) rememberEmail(email string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.credentials != nil {
+		c.credentials.email = email
+	}
+}
@@ -0,0 +1,280 @@
+package client_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/maypok86/conduit/internal/testserver"
+	"github.com/maypok86/conduit/pkg/client"
+	"github.com/stretchr/testify/require"
+)
+
+const password = "password"
+
+func TestMain(m *testing.M) {
+	testserver.Main(m)
+}
+
+// newServer returns the url of the api served by the real router over the memory storage.
+func newServer(t *testing.T) string {
+	t.Helper()
+
+	return testserver.URL(testserver.New(t))
+}
+
+func newClient(t *testing.T, baseURL string, opts ...client.Option) *client.Client {
+	t.Helper()
+
+	c, err := client.New(baseURL, opts...)
+	require.NoError(t, err)
+
+	return c
+}
+
+func register(t *testing.T, c *client.Client, username string) client.User {
+	t.Helper()
+
+	user, err := c.Register(context.Background(), client.NewUser{
+		Email:    username + "@mail.com",
+		Username: username,
+		Password: password,
+	})
+	require.NoError(t, err)
+
+	return user
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		baseURL string
+		wantErr bool
+	}{
+		{
+			name:    "valid",
+			baseURL: "http://localhost:8080/api/",
+		},
+		{
+			name:    "without scheme",
+			baseURL: "localhost:8080/api",
+			wantErr: true,
+		},
+		{
+			name:    "invalid",
+			baseURL: "http://local host:8080",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := client.New(tt.baseURL)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestClient_Users(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	c := newClient(t, newServer(t), client.WithUserAgent("conduit-client-test"))
+
+	_, err := c.CurrentUser(ctx)
+	require.ErrorIs(t, err, client.ErrUnauthorized)
+
+	registered := register(t, c, "jake")
+	require.Equal(t, "jake", registered.Username)
+	require.NotEmpty(t, registered.Token)
+	require.Equal(t, registered.Token, c.Token())
+
+	_, err = c.Register(ctx, client.NewUser{Email: "jake@mail.com", Username: "jake", Password: password})
+	require.ErrorIs(t, err, client.ErrUnprocessable)
+
+	var apiErr *client.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.NotEmpty(t, apiErr.Body())
+
+	_, err = c.Login(ctx, "jake@mail.com", "wrong password")
+	require.Error(t, err)
+
+	loggedIn, err := c.Login(ctx, "jake@mail.com", password)
+	require.NoError(t, err)
+	require.Equal(t, loggedIn.Token, c.Token())
+
+	bio := "I work at statefarm"
+	updated, err := c.UpdateUser(ctx, client.UpdateUser{Bio: &bio})
+	require.NoError(t, err)
+	require.Equal(t, bio, updated.Bio)
+
+	current, err := c.CurrentUser(ctx)
+	require.NoError(t, err)
+	require.Equal(t, bio, current.Bio)
+
+	export, err := c.ExportUser(ctx)
+	require.NoError(t, err)
+	require.Contains(t, []client.ExportStatus{client.ExportPending, client.ExportReady}, export.Status)
+
+	deletion, err := c.DeleteUser(ctx)
+	require.NoError(t, err)
+	require.True(t, deletion.PurgeAt.After(deletion.DeletedAt))
+}
+
+func TestClient_TokenRefresh(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	baseURL := newServer(t)
+	register(t, newClient(t, baseURL), "jake")
+
+	c := newClient(t, baseURL, client.WithToken("revoked"), client.WithCredentials("jake@mail.com", password))
+
+	user, err := c.CurrentUser(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "jake", user.Username)
+	require.NotEqual(t, "revoked", c.Token())
+
+	withoutCredentials := newClient(t, baseURL, client.WithToken("revoked"))
+
+	_, err = withoutCredentials.CurrentUser(ctx)
+	require.ErrorIs(t, err, client.ErrUnauthorized)
+}
+
+func TestClient_Profiles(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	baseURL := newServer(t)
+
+	jake := newClient(t, baseURL)
+	register(t, jake, "jake")
+
+	celeb := newClient(t, baseURL)
+	register(t, celeb, "celeb")
+
+	_, err := jake.Profile(ctx, "unknown")
+	require.ErrorIs(t, err, client.ErrUnprocessable)
+
+	profile, err := jake.Follow(ctx, "celeb")
+	require.NoError(t, err)
+	require.True(t, profile.Following)
+
+	profile, err = newClient(t, baseURL).Profile(ctx, "celeb")
+	require.NoError(t, err)
+	require.False(t, profile.Following)
+	require.Equal(t, 1, profile.FollowersCount)
+
+	profile, err = jake.Mute(ctx, "celeb")
+	require.NoError(t, err)
+	require.True(t, profile.Muting)
+
+	profile, err = jake.Unmute(ctx, "celeb")
+	require.NoError(t, err)
+	require.False(t, profile.Muting)
+
+	profile, err = jake.Unfollow(ctx, "celeb")
+	require.NoError(t, err)
+	require.False(t, profile.Following)
+
+	profile, err = jake.Block(ctx, "celeb")
+	require.NoError(t, err)
+	require.True(t, profile.Blocking)
+
+	profile, err = jake.Unblock(ctx, "celeb")
+	require.NoError(t, err)
+	require.False(t, profile.Blocking)
+}
+
+func TestClient_FollowRequests(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	baseURL := newServer(t)
+
+	celeb := newClient(t, baseURL)
+	register(t, celeb, "celeb")
+
+	private := true
+	_, err := celeb.UpdateUser(ctx, client.UpdateUser{Private: &private})
+	require.NoError(t, err)
+
+	for _, username := range []string{"jake", "anna"} {
+		follower := newClient(t, baseURL)
+		register(t, follower, username)
+
+		profile, err := follower.Follow(ctx, "celeb")
+		require.NoError(t, err)
+		require.True(t, profile.Requested)
+	}
+
+	page, err := celeb.ListFollowRequests(ctx, client.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, page.Profiles, 2)
+
+	_, err = celeb.ApproveFollowRequest(ctx, "jake")
+	require.NoError(t, err)
+
+	_, err = celeb.RejectFollowRequest(ctx, "anna")
+	require.NoError(t, err)
+
+	it := celeb.FollowRequests(1)
+	require.False(t, it.Next(ctx))
+	require.NoError(t, it.Err())
+
+	page, err = celeb.ListFollowers(ctx, "celeb", client.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, page.Profiles, 1)
+	require.Equal(t, "jake", page.Profiles[0].Username)
+}
+
+func TestIterator(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	baseURL := newServer(t)
+
+	celeb := newClient(t, baseURL)
+	register(t, celeb, "celeb")
+
+	const followers = 5
+
+	want := make(map[string]bool, followers)
+	for i := 0; i < followers; i++ {
+		username := "follower" + strconv.Itoa(i)
+		want[username] = true
+
+		follower := newClient(t, baseURL)
+		register(t, follower, username)
+
+		_, err := follower.Follow(ctx, "celeb")
+		require.NoError(t, err)
+	}
+
+	got := make(map[string]bool, followers)
+	it := celeb.Followers("celeb", 2)
+	for it.Next(ctx) {
+		got[it.Value().Username] = true
+	}
+	require.NoError(t, it.Err())
+	require.Equal(t, want, got)
+
+	it = celeb.Following("celeb", 2)
+	require.False(t, it.Next(ctx))
+	require.NoError(t, it.Err())
+
+	it = celeb.Followers("unknown", 2)
+	require.False(t, it.Next(ctx))
+	require.ErrorIs(t, it.Err(), client.ErrUnprocessable)
+}
@@ -0,0 +1,92 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+var (
+	// ErrBadRequest is matched by the errors of the requests the api considers malformed.
+	ErrBadRequest = errors.New("bad request")
+	// ErrUnauthorized is matched by the errors of the requests without a valid token.
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrNotFound is matched by the errors of the requests to missing resources.
+	ErrNotFound = errors.New("not found")
+	// ErrUnprocessable is matched by the errors of the requests the api can not fulfil, for example
+	// a registration with a taken username.
+	ErrUnprocessable = errors.New("unprocessable entity")
+	// ErrTooManyRequests is matched by the errors of the rate limited requests.
+	ErrTooManyRequests = errors.New("too many requests")
+	// ErrServer is matched by the errors of the requests the api failed to handle.
+	ErrServer = errors.New("server error")
+)
+
+// Error is an error response of the api. The api describes the error in {"errors":{"body":["..."]}},
+// the errors are keyed by the field they refer to, body holds the general ones.
+type Error struct {
+	StatusCode int
+	Errors     map[string][]string
+}
+
+func newError(statusCode int, body []byte) *Error {
+	var response struct {
+		Errors map[string][]string `json:"errors"`
+	}
+
+	// Some errors, for example 401 of the auth middleware, have no body.
+	_ = json.Unmarshal(body, &response)
+
+	return &Error{
+		StatusCode: statusCode,
+		Errors:     response.Errors,
+	}
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	if len(e.Errors) == 0 {
+		return fmt.Sprintf("conduit: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+
+	fields := make([]string, 0, len(e.Errors))
+	for field := range e.Errors {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	messages := make([]string, 0, len(fields))
+	for _, field := range fields {
+		messages = append(messages, field+": "+strings.Join(e.Errors[field], ", "))
+	}
+
+	return fmt.Sprintf("conduit: %d %s", e.StatusCode, strings.Join(messages, "; "))
+}
+
+// Is makes errors.Is match the error with the sentinel of its status code.
+func (e *Error) Is(target error) bool {
+	switch target { //nolint:errorlint
+	case ErrBadRequest:
+		return e.StatusCode == http.StatusBadRequest
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrUnprocessable:
+		return e.StatusCode == http.StatusUnprocessableEntity
+	case ErrTooManyRequests:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrServer:
+		return e.StatusCode >= http.StatusInternalServerError
+	default:
+		return false
+	}
+}
+
+// Body returns the general messages of the error.
+func (e *Error) Body() []string {
+	return e.Errors["body"]
+}
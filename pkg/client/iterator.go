@@ -0,0 +1,108 @@
+package client
+
+import "context"
+
+// Page is a page of a list.
+type Page[T any] struct {
+	Items      []T
+	NextCursor string
+}
+
+// Iterator iterates over the items of a paginated list and fetches the next page when the current one is over.
+//
+//	it := c.Followers("jake", 50)
+//	for it.Next(ctx) {
+//		fmt.Println(it.Value().Username)
+//	}
+//	if err := it.Err(); err != nil {
+//		return err
+//	}
+type Iterator[T any] struct {
+	fetch func(ctx context.Context, opts ListOptions) (Page[T], error)
+	limit int
+
+	items  []T
+	index  int
+	cursor string
+	last   bool
+	err    error
+}
+
+// NewIterator returns a new instance of Iterator over the pages returned by fetch.
+func NewIterator[T any](limit int, fetch func(ctx context.Context, opts ListOptions) (Page[T], error)) *Iterator[T] {
+	return &Iterator[T]{
+		fetch: fetch,
+		limit: limit,
+		index: -1,
+	}
+}
+
+// Next advances the iterator to the next item, it returns false when the list is over or the fetch failed.
+func (it *Iterator[T]) Next(ctx context.Context) bool {
+	if it.err != nil {
+		return false
+	}
+
+	it.index++
+	for it.index >= len(it.items) {
+		if it.last {
+			return false
+		}
+
+		page, err := it.fetch(ctx, ListOptions{Cursor: it.cursor, Limit: it.limit})
+		if err != nil {
+			it.err = err
+			return false
+		}
+
+		it.items = page.Items
+		it.index = 0
+		it.cursor = page.NextCursor
+		it.last = page.NextCursor == ""
+	}
+
+	return true
+}
+
+// Value returns the current item.
+func (it *Iterator[T]) Value() T {
+	return it.items[it.index]
+}
+
+// Err returns the error the iteration stopped with.
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// Followers returns an iterator over the followers of the user with username, limit is the size of a page.
+func (c *Client) Followers(username string, limit int) *Iterator[Profile] {
+	return NewIterator(limit, func(ctx context.Context, opts ListOptions) (Page[Profile], error) {
+		return profilesPage(c.ListFollowers(ctx, username, opts))
+	})
+}
+
+// Following returns an iterator over the users the user with username follows, limit is the size of a page.
+func (c *Client) Following(username string, limit int) *Iterator[Profile] {
+	return NewIterator(limit, func(ctx context.Context, opts ListOptions) (Page[Profile], error) {
+		return profilesPage(c.ListFollowing(ctx, username, opts))
+	})
+}
+
+// FollowRequests returns an iterator over the pending follow requests of the authenticated user,
+// limit is the size of a page.
+func (c *Client) FollowRequests(limit int) *Iterator[Profile] {
+	return NewIterator(limit, func(ctx context.Context, opts ListOptions) (Page[Profile], error) {
+		return profilesPage(c.ListFollowRequests(ctx, opts))
+	})
+}
+
+func profilesPage(page ProfilesPage, err error) (Page[Profile], error) {
+	if err != nil {
+		return Page[Profile]{}, err
+	}
+
+	return Page[Profile]{
+		Items:      page.Profiles,
+		NextCursor: page.NextCursor,
+	}, nil
+}
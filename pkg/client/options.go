@@ -0,0 +1,38 @@
+package client
+
+import "net/http"
+
+// Option is a functional option for configuring a Client.
+type Option func(*Client)
+
+// WithHTTPClient sets the http client the requests are sent with.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithToken sets the token the client authenticates with.
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+// WithCredentials makes the client log in with the credentials when the api rejects its token,
+// so the client outlives the expiration of the tokens.
+func WithCredentials(email, password string) Option {
+	return func(c *Client) {
+		c.credentials = &credentials{
+			email:    email,
+			password: password,
+		}
+	}
+}
+
+// WithUserAgent sets the User-Agent header of the requests.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
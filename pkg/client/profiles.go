@@ -0,0 +1,139 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Profile is a public profile of a user. The relation fields are set for the authenticated client only.
+type Profile struct {
+	Username       string `json:"username"`
+	Bio            string `json:"bio"`
+	Image          string `json:"image"`
+	Private        bool   `json:"private"`
+	Following      bool   `json:"following"`
+	Requested      bool   `json:"requested"`
+	Blocking       bool   `json:"blocking"`
+	Muting         bool   `json:"muting"`
+	FollowersCount int    `json:"followersCount"`
+	FollowingCount int    `json:"followingCount"`
+	RenamedFrom    string `json:"renamedFrom,omitempty"`
+}
+
+// ListOptions are the pagination options of the lists, zero values use the defaults of the api.
+type ListOptions struct {
+	Cursor string
+	Limit  int
+}
+
+func (o ListOptions) query() url.Values {
+	query := url.Values{}
+	if o.Cursor != "" {
+		query.Set("cursor", o.Cursor)
+	}
+
+	if o.Limit != 0 {
+		query.Set("limit", strconv.Itoa(o.Limit))
+	}
+
+	return query
+}
+
+// ProfilesPage is a page of profiles, NextCursor is empty on the last page.
+type ProfilesPage struct {
+	Profiles   []Profile `json:"profiles"`
+	NextCursor string    `json:"nextCursor"`
+}
+
+type profileResponse struct {
+	Profile Profile `json:"profile"`
+}
+
+// Profile returns the profile of the user with username.
+func (c *Client) Profile(ctx context.Context, username string) (Profile, error) {
+	return c.profile(ctx, http.MethodGet, "/profiles/"+url.PathEscape(username), optionalAuth)
+}
+
+// Follow follows the user with username, the following of a private profile is requested.
+func (c *Client) Follow(ctx context.Context, username string) (Profile, error) {
+	return c.profile(ctx, http.MethodPost, "/profiles/"+url.PathEscape(username)+"/follow", requiredAuth)
+}
+
+// Unfollow unfollows the user with username or cancels the follow request.
+func (c *Client) Unfollow(ctx context.Context, username string) (Profile, error) {
+	return c.profile(ctx, http.MethodDelete, "/profiles/"+url.PathEscape(username)+"/follow", requiredAuth)
+}
+
+// Block blocks the user with username.
+func (c *Client) Block(ctx context.Context, username string) (Profile, error) {
+	return c.profile(ctx, http.MethodPost, "/profiles/"+url.PathEscape(username)+"/block", requiredAuth)
+}
+
+// Unblock unblocks the user with username.
+func (c *Client) Unblock(ctx context.Context, username string) (Profile, error) {
+	return c.profile(ctx, http.MethodDelete, "/profiles/"+url.PathEscape(username)+"/block", requiredAuth)
+}
+
+// Mute mutes the user with username.
+func (c *Client) Mute(ctx context.Context, username string) (Profile, error) {
+	return c.profile(ctx, http.MethodPost, "/profiles/"+url.PathEscape(username)+"/mute", requiredAuth)
+}
+
+// Unmute unmutes the user with username.
+func (c *Client) Unmute(ctx context.Context, username string) (Profile, error) {
+	return c.profile(ctx, http.MethodDelete, "/profiles/"+url.PathEscape(username)+"/mute", requiredAuth)
+}
+
+// ApproveFollowRequest approves the follow request of the user with username.
+func (c *Client) ApproveFollowRequest(ctx context.Context, username string) (Profile, error) {
+	return c.profile(ctx, http.MethodPost, "/user/follow-requests/"+url.PathEscape(username)+"/approve", requiredAuth)
+}
+
+// RejectFollowRequest rejects the follow request of the user with username.
+func (c *Client) RejectFollowRequest(ctx context.Context, username string) (Profile, error) {
+	return c.profile(ctx, http.MethodPost, "/user/follow-requests/"+url.PathEscape(username)+"/reject", requiredAuth)
+}
+
+// ListFollowers returns a page of the followers of the user with username.
+func (c *Client) ListFollowers(ctx context.Context, username string, opts ListOptions) (ProfilesPage, error) {
+	return c.profiles(ctx, "/profiles/"+url.PathEscape(username)+"/followers", opts, optionalAuth)
+}
+
+// ListFollowing returns a page of the users the user with username follows.
+func (c *Client) ListFollowing(ctx context.Context, username string, opts ListOptions) (ProfilesPage, error) {
+	return c.profiles(ctx, "/profiles/"+url.PathEscape(username)+"/following", opts, optionalAuth)
+}
+
+// ListFollowRequests returns a page of the pending follow requests of the authenticated user.
+func (c *Client) ListFollowRequests(ctx context.Context, opts ListOptions) (ProfilesPage, error) {
+	return c.profiles(ctx, "/user/follow-requests", opts, requiredAuth)
+}
+
+func (c *Client) profile(ctx context.Context, method, path string, auth auth) (Profile, error) {
+	var response profileResponse
+	if _, err := c.do(ctx, request{
+		method: method,
+		path:   path,
+		auth:   auth,
+	}, &response); err != nil {
+		return Profile{}, err
+	}
+
+	return response.Profile, nil
+}
+
+func (c *Client) profiles(ctx context.Context, path string, opts ListOptions, auth auth) (ProfilesPage, error) {
+	var page ProfilesPage
+	if _, err := c.do(ctx, request{
+		method: http.MethodGet,
+		path:   path,
+		query:  opts.query(),
+		auth:   auth,
+	}, &page); err != nil {
+		return ProfilesPage{}, err
+	}
+
+	return page, nil
+}
@@ -0,0 +1,190 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"time"
+)
+
+// User is the authenticated user.
+type User struct {
+	Email    string `json:"email"`
+	Username string `json:"username"`
+	Bio      string `json:"bio"`
+	Image    string `json:"image"`
+	Private  bool   `json:"private"`
+	Token    string `json:"token"`
+}
+
+// NewUser is a registration of a user.
+type NewUser struct {
+	Email    string `json:"email"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// UpdateUser is an update of the current user, nil fields are left as is.
+type UpdateUser struct {
+	Email    *string `json:"email,omitempty"`
+	Username *string `json:"username,omitempty"`
+	Bio      *string `json:"bio,omitempty"`
+	Image    *string `json:"image,omitempty"`
+	Private  *bool   `json:"private,omitempty"`
+}
+
+// Deletion describes a scheduled account deletion.
+type Deletion struct {
+	DeletedAt time.Time `json:"deletedAt"`
+	PurgeAt   time.Time `json:"purgeAt"`
+}
+
+// ExportStatus is the status of a user data export.
+type ExportStatus string
+
+const (
+	// ExportPending is the status of an export that is being generated.
+	ExportPending ExportStatus = "pending"
+	// ExportReady is the status of an export with the archive.
+	ExportReady ExportStatus = "ready"
+	// ExportFailed is the status of an export that failed to be generated.
+	ExportFailed ExportStatus = "failed"
+)
+
+// Export is a user data export. Archive and Filename are set only for the ready exports.
+type Export struct {
+	Status      ExportStatus `json:"status"`
+	RequestedAt time.Time    `json:"requestedAt"`
+	Archive     []byte       `json:"-"`
+	Filename    string       `json:"-"`
+}
+
+type userResponse struct {
+	User User `json:"user"`
+}
+
+// Register registers a user and authenticates the client as the user.
+func (c *Client) Register(ctx context.Context, newUser NewUser) (User, error) {
+	var response userResponse
+	if _, err := c.do(ctx, request{
+		method: http.MethodPost,
+		path:   "/users/",
+		body:   map[string]NewUser{"user": newUser},
+		auth:   noAuth,
+	}, &response); err != nil {
+		return User{}, err
+	}
+
+	c.SetToken(response.User.Token)
+
+	return response.User, nil
+}
+
+// Login logs the user in and authenticates the client as the user.
+func (c *Client) Login(ctx context.Context, email, password string) (User, error) {
+	var response userResponse
+	if _, err := c.do(ctx, request{
+		method: http.MethodPost,
+		path:   "/users/login",
+		body: map[string]interface{}{
+			"user": map[string]string{
+				"email":    email,
+				"password": password,
+			},
+		},
+		auth: noAuth,
+	}, &response); err != nil {
+		return User{}, err
+	}
+
+	c.SetToken(response.User.Token)
+
+	return response.User, nil
+}
+
+// CurrentUser returns the authenticated user.
+func (c *Client) CurrentUser(ctx context.Context) (User, error) {
+	var response userResponse
+	if _, err := c.do(ctx, request{
+		method: http.MethodGet,
+		path:   "/user/",
+		auth:   requiredAuth,
+	}, &response); err != nil {
+		return User{}, err
+	}
+
+	return response.User, nil
+}
+
+// UpdateUser updates the authenticated user.
+func (c *Client) UpdateUser(ctx context.Context, update UpdateUser) (User, error) {
+	var response userResponse
+	if _, err := c.do(ctx, request{
+		method: http.MethodPut,
+		path:   "/user/",
+		body:   map[string]UpdateUser{"user": update},
+		auth:   requiredAuth,
+	}, &response); err != nil {
+		return User{}, err
+	}
+
+	// The token is bound to the old email, so the next renewal has to log in with the new one.
+	if update.Email != nil {
+		c.rememberEmail(response.User.Email)
+	}
+
+	return response.User, nil
+}
+
+// DeleteUser schedules the deletion of the authenticated user.
+func (c *Client) DeleteUser(ctx context.Context) (Deletion, error) {
+	var response struct {
+		Deletion Deletion `json:"deletion"`
+	}
+	if _, err := c.do(ctx, request{
+		method: http.MethodDelete,
+		path:   "/user/",
+		auth:   requiredAuth,
+	}, &response); err != nil {
+		return Deletion{}, err
+	}
+
+	return response.Deletion, nil
+}
+
+// ExportUser exports the data of the authenticated user. Large accounts are exported in background,
+// poll until the status is ExportReady.
+func (c *Client) ExportUser(ctx context.Context) (Export, error) {
+	resp, err := c.do(ctx, request{
+		method: http.MethodGet,
+		path:   "/user/export",
+		auth:   requiredAuth,
+	}, nil)
+	if err != nil {
+		return Export{}, err
+	}
+
+	if resp.statusCode == http.StatusOK {
+		export := Export{
+			Status:  ExportReady,
+			Archive: resp.body,
+		}
+
+		if _, params, err := mime.ParseMediaType(resp.header.Get("Content-Disposition")); err == nil {
+			export.Filename = params["filename"]
+		}
+
+		return export, nil
+	}
+
+	var response struct {
+		Export Export `json:"export"`
+	}
+	if err := json.Unmarshal(resp.body, &response); err != nil {
+		return Export{}, fmt.Errorf("can not decode export response: %w", err)
+	}
+
+	return response.Export, nil
+}
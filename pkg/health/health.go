@@ -0,0 +1,171 @@
+// Package health provides the liveness and readiness checks of the application.
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/maypok86/conduit/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const defaultTimeout = time.Second
+
+const (
+	// StatusOK is the status of a passed check.
+	StatusOK = "ok"
+	// StatusFail is the status of a failed check.
+	StatusFail = "fail"
+)
+
+// drainingCheck is the check that fails once the shutdown begins.
+const drainingCheck = "shutdown"
+
+// Checker checks a dependency of the application.
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// CheckerFunc is a function that implements the Checker interface.
+type CheckerFunc func(ctx context.Context) error
+
+// Check calls f.
+func (f CheckerFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+// Report is the result of the readiness checks, Checks holds the status of every check. The errors are
+// only logged, the report is public and must not disclose the internals of the dependencies.
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// IsOK check that all checks passed.
+func (r Report) IsOK() bool {
+	return r.Status == StatusOK
+}
+
+// Option is a functional option for configuring a Registry.
+type Option func(*Registry)
+
+// WithTimeout sets the timeout of a single check.
+func WithTimeout(timeout time.Duration) Option {
+	return func(r *Registry) {
+		r.timeout = timeout
+	}
+}
+
+// Registry holds the checkers the readiness of the application depends on.
+type Registry struct {
+	timeout  time.Duration
+	mu       sync.RWMutex
+	checkers map[string]Checker
+	// draining is 1 once the shutdown begins.
+	draining int32
+}
+
+// NewRegistry returns a new instance of Registry.
+func NewRegistry(opts ...Option) *Registry {
+	r := &Registry{
+		timeout:  defaultTimeout,
+		checkers: make(map[string]Checker),
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// Register adds the checker under name, a checker with the same name is replaced.
+func (r *Registry) Register(name string, checker Checker) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.checkers[name] = checker
+}
+
+// Drain makes the readiness fail from now on, so the load balancers stop sending traffic before
+// the server stops.
+func (r *Registry) Drain() {
+	atomic.StoreInt32(&r.draining, 1)
+}
+
+// Ready runs the checkers concurrently, each within the timeout. The errors of the failed checks
+// are logged with the logger of ctx.
+func (r *Registry) Ready(ctx context.Context) Report {
+	if atomic.LoadInt32(&r.draining) == 1 {
+		return Report{
+			Status: StatusFail,
+			Checks: map[string]string{drainingCheck: StatusFail},
+		}
+	}
+
+	r.mu.RLock()
+	checkers := make(map[string]Checker, len(r.checkers))
+	for name, checker := range r.checkers {
+		checkers[name] = checker
+	}
+	r.mu.RUnlock()
+
+	report := Report{
+		Status: StatusOK,
+		Checks: make(map[string]string, len(checkers)),
+	}
+
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+
+	for name, checker := range checkers {
+		name, checker := name, checker
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			status := StatusOK
+			if err := r.check(ctx, checker); err != nil {
+				status = StatusFail
+
+				logger.FromContext(ctx).Warn("readiness check failed", zap.String("check", name), zap.Error(err))
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			report.Checks[name] = status
+			if status != StatusOK {
+				report.Status = StatusFail
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return report
+}
+
+func (r *Registry) check(ctx context.Context, checker Checker) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- checker.Check(ctx)
+	}()
+
+	// A checker that ignores the context does not hold the readiness up.
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		return ctx.Err() //nolint:wrapcheck
+	}
+}
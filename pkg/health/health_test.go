@@ -0,0 +1,119 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/maypok86/conduit/pkg/health"
+	"github.com/maypok86/conduit/pkg/logger"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestRegistry_Ready(t *testing.T) {
+	t.Parallel()
+
+	errDown := errors.New("postgres is down")
+	ok := health.CheckerFunc(func(context.Context) error {
+		return nil
+	})
+	failing := health.CheckerFunc(func(context.Context) error {
+		return errDown
+	})
+	hanging := health.CheckerFunc(func(context.Context) error {
+		time.Sleep(time.Second)
+
+		return nil
+	})
+
+	tests := []struct {
+		name     string
+		checkers map[string]health.Checker
+		drain    bool
+		want     health.Report
+	}{
+		{
+			name: "no checkers",
+			want: health.Report{Status: health.StatusOK, Checks: map[string]string{}},
+		},
+		{
+			name:     "passed",
+			checkers: map[string]health.Checker{"postgres": ok, "cache": ok},
+			want: health.Report{
+				Status: health.StatusOK,
+				Checks: map[string]string{"postgres": "ok", "cache": "ok"},
+			},
+		},
+		{
+			name:     "failed",
+			checkers: map[string]health.Checker{"postgres": failing, "cache": ok},
+			want: health.Report{
+				Status: health.StatusFail,
+				Checks: map[string]string{"postgres": "fail", "cache": "ok"},
+			},
+		},
+		{
+			name:     "timed out",
+			checkers: map[string]health.Checker{"postgres": hanging},
+			want: health.Report{
+				Status: health.StatusFail,
+				Checks: map[string]string{"postgres": "fail"},
+			},
+		},
+		{
+			name:     "draining",
+			checkers: map[string]health.Checker{"postgres": ok},
+			drain:    true,
+			want: health.Report{
+				Status: health.StatusFail,
+				Checks: map[string]string{"shutdown": "fail"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			registry := health.NewRegistry(health.WithTimeout(50 * time.Millisecond))
+			for name, checker := range tt.checkers {
+				registry.Register(name, checker)
+			}
+
+			if tt.drain {
+				registry.Drain()
+			}
+
+			report := registry.Ready(context.Background())
+			require.Equal(t, tt.want, report)
+			require.Equal(t, tt.want.Status == health.StatusOK, report.IsOK())
+		})
+	}
+}
+
+func TestRegistry_ReadyLogsErrors(t *testing.T) {
+	t.Parallel()
+
+	errDown := errors.New("dial tcp 10.0.0.5:5432: connect: connection refused")
+	core, logs := observer.New(zapcore.WarnLevel)
+	ctx := logger.ContextWithLogger(context.Background(), zap.New(core))
+
+	registry := health.NewRegistry()
+	registry.Register("postgres", health.CheckerFunc(func(context.Context) error {
+		return errDown
+	}))
+
+	report := registry.Ready(ctx)
+	require.Equal(t, map[string]string{"postgres": "fail"}, report.Checks)
+
+	entries := logs.FilterMessage("readiness check failed").All()
+	require.Len(t, entries, 1)
+	require.Equal(t, "postgres", entries[0].ContextMap()["check"])
+	require.Equal(t, errDown.Error(), entries[0].ContextMap()["error"])
+}
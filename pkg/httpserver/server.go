@@ -3,6 +3,7 @@ package httpserver
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
@@ -56,20 +57,17 @@ func New(httpHandler http.Handler, opts ...Option) Server {
 	return server
 }
 
-// Start starts the http server.
+// Start starts the http server. It returns nil once the server is stopped.
 func (s Server) Start() error {
-	if err := s.httpServer.ListenAndServe(); err != nil {
+	if err := s.httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		return fmt.Errorf("failed to start http server: %w", err)
 	}
 
 	return nil
 }
 
-// Stop stops the http server.
-func (s Server) Stop(ctx context.Context, shutdownTimeout time.Duration) error {
-	ctx, cancel := context.WithTimeout(ctx, shutdownTimeout)
-	defer cancel()
-
+// Stop stops accepting the connections and waits for the in-flight requests until ctx is done.
+func (s Server) Stop(ctx context.Context) error {
 	if err := s.httpServer.Shutdown(ctx); err != nil {
 		return fmt.Errorf("failed to shutdown http server: %w", err)
 	}
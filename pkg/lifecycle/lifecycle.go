@@ -0,0 +1,93 @@
+// Package lifecycle provides the ordered shutdown of the application.
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const defaultTimeout = 15 * time.Second
+
+// StopFunc stops a component, it should return when ctx is done.
+type StopFunc func(ctx context.Context) error
+
+type hook struct {
+	name string
+	stop StopFunc
+}
+
+// Option is a functional option for configuring a Manager.
+type Option func(*Manager)
+
+// WithTimeout sets the time the whole shutdown may take.
+func WithTimeout(timeout time.Duration) Option {
+	return func(m *Manager) {
+		m.timeout = timeout
+	}
+}
+
+// WithLogger sets the logger of the shutdown steps.
+func WithLogger(logger *zap.Logger) Option {
+	return func(m *Manager) {
+		m.logger = logger
+	}
+}
+
+// Manager stops the components of the application in the order they are added.
+type Manager struct {
+	timeout time.Duration
+	logger  *zap.Logger
+	hooks   []hook
+}
+
+// New returns a new instance of Manager.
+func New(opts ...Option) *Manager {
+	m := &Manager{
+		timeout: defaultTimeout,
+		logger:  zap.NewNop(),
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m
+}
+
+// Add adds the component, it is stopped after the components added before it.
+func (m *Manager) Add(name string, stop StopFunc) {
+	m.hooks = append(m.hooks, hook{
+		name: name,
+		stop: stop,
+	})
+}
+
+// Shutdown stops the components one by one within the timeout. A failed component does not keep the next
+// ones running, the first error is returned.
+func (m *Manager) Shutdown(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, m.timeout)
+	defer cancel()
+
+	var firstErr error
+
+	for _, h := range m.hooks {
+		startedAt := time.Now()
+
+		if err := h.stop(ctx); err != nil {
+			m.logger.Error("failed to stop", zap.String("component", h.name), zap.Error(err))
+
+			if firstErr == nil {
+				firstErr = fmt.Errorf("can not stop %s: %w", h.name, err)
+			}
+
+			continue
+		}
+
+		m.logger.Info("stopped", zap.String("component", h.name), zap.Duration("duration", time.Since(startedAt)))
+	}
+
+	return firstErr
+}
@@ -0,0 +1,85 @@
+package lifecycle_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/maypok86/conduit/pkg/lifecycle"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager_Shutdown(t *testing.T) {
+	t.Parallel()
+
+	errStop := errors.New("stop failed")
+
+	tests := []struct {
+		name      string
+		failing   map[string]bool
+		wantOrder []string
+		wantErr   error
+	}{
+		{
+			name:      "ordered",
+			wantOrder: []string{"http", "workers", "postgres"},
+		},
+		{
+			name:      "failed component",
+			failing:   map[string]bool{"workers": true},
+			wantOrder: []string{"http", "workers", "postgres"},
+			wantErr:   errStop,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var order []string
+
+			m := lifecycle.New()
+			for _, name := range []string{"http", "workers", "postgres"} {
+				name := name
+
+				m.Add(name, func(context.Context) error {
+					order = append(order, name)
+					if tt.failing[name] {
+						return errStop
+					}
+
+					return nil
+				})
+			}
+
+			err := m.Shutdown(context.Background())
+			require.ErrorIs(t, err, tt.wantErr)
+			require.Equal(t, tt.wantOrder, order)
+		})
+	}
+}
+
+func TestManager_ShutdownTimeout(t *testing.T) {
+	t.Parallel()
+
+	m := lifecycle.New(lifecycle.WithTimeout(50 * time.Millisecond))
+	m.Add("http", func(ctx context.Context) error {
+		<-ctx.Done()
+
+		return ctx.Err()
+	})
+
+	stopped := false
+	m.Add("postgres", func(context.Context) error {
+		stopped = true
+
+		return nil
+	})
+
+	err := m.Shutdown(context.Background())
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.True(t, stopped)
+}
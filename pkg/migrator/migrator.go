@@ -0,0 +1,247 @@
+// Package migrator applies goose migrations from an embedded filesystem.
+package migrator
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+	"time"
+
+	_ "github.com/jackc/pgx/v4/stdlib" // registers the pgx database/sql driver.
+	"github.com/pressly/goose/v3"
+	"go.uber.org/zap"
+)
+
+const (
+	dialect = "postgres"
+	dir     = "."
+	// defaultTableName is the table of the applied migrations.
+	defaultTableName = "goose_db_version"
+	// defaultLockID is the key of the advisory lock that serializes migrations of concurrent instances.
+	defaultLockID int64 = 7239046125385123845
+)
+
+// MigrationStatus is the state of a single migration.
+type MigrationStatus struct {
+	Version   int64
+	Source    string
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// Migrator applies the migrations of fsys to a postgres database.
+type Migrator struct {
+	db            *sql.DB
+	lockID        int64
+	logger        *zap.Logger
+	tableName     string
+	freshSessions bool
+}
+
+// Option is a functional option for configuring a Migrator.
+type Option func(*Migrator)
+
+// WithLockID sets the key of the advisory lock that is held while migrating.
+func WithLockID(lockID int64) Option {
+	return func(m *Migrator) {
+		m.lockID = lockID
+	}
+}
+
+// WithLogger sets the logger for the applied migrations.
+func WithLogger(logger *zap.Logger) Option {
+	return func(m *Migrator) {
+		m.logger = logger
+	}
+}
+
+// WithTableName sets the table of the applied migrations, it may be qualified with a schema.
+func WithTableName(tableName string) Option {
+	return func(m *Migrator) {
+		m.tableName = tableName
+	}
+}
+
+// WithFreshSessions closes the connections after every use, so the session settings a migration changes,
+// e.g. its search_path, do not apply to the next migrations.
+func WithFreshSessions() Option {
+	return func(m *Migrator) {
+		m.freshSessions = true
+	}
+}
+
+// New creates a new Migrator. fsys must contain the goose sql migrations in its root.
+func New(dsn string, fsys fs.FS, opts ...Option) (*Migrator, error) {
+	m := &Migrator{
+		lockID:    defaultLockID,
+		logger:    zap.NewNop(),
+		tableName: defaultTableName,
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	// goose is configured globally, a process is expected to migrate a single database.
+	if err := goose.SetDialect(dialect); err != nil {
+		return nil, fmt.Errorf("can not set goose dialect: %w", err)
+	}
+
+	goose.SetBaseFS(fsys)
+	goose.SetTableName(m.tableName)
+	goose.SetLogger(gooseLogger{logger: m.logger.Sugar()})
+
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("can not open database: %w", err)
+	}
+
+	if m.freshSessions {
+		db.SetMaxIdleConns(0)
+	}
+
+	m.db = db
+
+	return m, nil
+}
+
+// Up applies all pending migrations.
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.withLock(ctx, func() error {
+		return goose.Up(m.db, dir) //nolint:wrapcheck
+	})
+}
+
+// UpTo applies the pending migrations up to and including version.
+func (m *Migrator) UpTo(ctx context.Context, version int64) error {
+	return m.withLock(ctx, func() error {
+		return goose.UpTo(m.db, dir, version) //nolint:wrapcheck
+	})
+}
+
+// Down rolls back the latest applied migration.
+func (m *Migrator) Down(ctx context.Context) error {
+	return m.withLock(ctx, func() error {
+		return goose.Down(m.db, dir) //nolint:wrapcheck
+	})
+}
+
+// Redo rolls back the latest applied migration and applies it again.
+func (m *Migrator) Redo(ctx context.Context) error {
+	return m.withLock(ctx, func() error {
+		return goose.Redo(m.db, dir) //nolint:wrapcheck
+	})
+}
+
+// Status returns the state of every known migration ordered by version.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	var statuses []MigrationStatus
+
+	err := m.withLock(ctx, func() error {
+		migrations, err := goose.CollectMigrations(dir, 0, goose.MaxVersion)
+		if err != nil {
+			return fmt.Errorf("can not collect migrations: %w", err)
+		}
+
+		if _, err := goose.EnsureDBVersion(m.db); err != nil {
+			return fmt.Errorf("can not ensure version table: %w", err)
+		}
+
+		statuses = make([]MigrationStatus, 0, len(migrations))
+		for _, migration := range migrations {
+			status, err := m.migrationStatus(ctx, migration)
+			if err != nil {
+				return err
+			}
+
+			statuses = append(statuses, status)
+		}
+
+		return nil
+	})
+
+	return statuses, err
+}
+
+func (m *Migrator) migrationStatus(ctx context.Context, migration *goose.Migration) (MigrationStatus, error) {
+	status := MigrationStatus{
+		Version: migration.Version,
+		Source:  migration.Source,
+	}
+
+	query := fmt.Sprintf(
+		"SELECT tstamp, is_applied FROM %s WHERE version_id = $1 ORDER BY tstamp DESC LIMIT 1",
+		goose.TableName(),
+	)
+
+	err := m.db.QueryRowContext(ctx, query, migration.Version).Scan(&status.AppliedAt, &status.Applied)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return MigrationStatus{}, fmt.Errorf("can not get status of migration %d: %w", migration.Version, err)
+	}
+
+	if !status.Applied {
+		status.AppliedAt = time.Time{}
+	}
+
+	return status, nil
+}
+
+// withLock runs fn while holding the advisory lock, so instances that start at once migrate one by one.
+func (m *Migrator) withLock(ctx context.Context, fn func() error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("can not get connection: %w", err)
+	}
+	defer conn.Close()
+
+	m.logger.Debug("acquiring migration lock", zap.Int64("lock_id", m.lockID))
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", m.lockID); err != nil {
+		return fmt.Errorf("can not acquire migration lock: %w", err)
+	}
+
+	defer func() {
+		// The lock is released with the session anyway if the unlock fails.
+		if _, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", m.lockID); err != nil {
+			m.logger.Warn("can not release migration lock", zap.Error(err))
+		}
+	}()
+
+	return fn()
+}
+
+// Close closes the database connection.
+func (m *Migrator) Close() error {
+	if err := m.db.Close(); err != nil {
+		return fmt.Errorf("can not close database: %w", err)
+	}
+
+	return nil
+}
+
+type gooseLogger struct {
+	logger *zap.SugaredLogger
+}
+
+func (l gooseLogger) Fatal(v ...interface{}) {
+	l.logger.Fatal(v...)
+}
+
+func (l gooseLogger) Fatalf(format string, v ...interface{}) {
+	l.logger.Fatalf(strings.TrimSpace(format), v...)
+}
+
+func (l gooseLogger) Print(v ...interface{}) {
+	l.logger.Info(v...)
+}
+
+func (l gooseLogger) Println(v ...interface{}) {
+	l.logger.Info(v...)
+}
+
+func (l gooseLogger) Printf(format string, v ...interface{}) {
+	l.logger.Infof(strings.TrimSpace(format), v...)
+}
@@ -3,10 +3,13 @@ package postgres
 import (
 	"fmt"
 	"time"
+
+	"go.uber.org/zap"
 )
 
 // ConnectionConfig is a configuration for postgres connection.
 type ConnectionConfig struct {
+	url      string
 	host     string
 	port     string
 	dbname   string
@@ -27,7 +30,19 @@ func NewConnectionConfig(host, port, dbname, username, password, sslmode string)
 	}
 }
 
-func (cc ConnectionConfig) getDSN() string {
+// NewURLConnectionConfig creates a new ConnectionConfig from a connection URL such as DATABASE_URL.
+func NewURLConnectionConfig(url string) ConnectionConfig {
+	return ConnectionConfig{
+		url: url,
+	}
+}
+
+// DSN returns the connection string of the ConnectionConfig.
+func (cc ConnectionConfig) DSN() string {
+	if cc.url != "" {
+		return cc.url
+	}
+
 	return fmt.Sprintf(
 		"postgres://%s:%s@%s:%s/%s?sslmode=%s",
 		cc.username,
@@ -49,6 +64,56 @@ func WithMaxPoolSize(maxPoolSize int) Option {
 	}
 }
 
+// WithMinPoolSize sets the number of connections the pool keeps open even when they are idle.
+func WithMinPoolSize(minPoolSize int) Option {
+	return func(c *Postgres) {
+		c.minPoolSize = minPoolSize
+	}
+}
+
+// WithMaxConnLifetime sets the duration after which a connection is closed and replaced.
+func WithMaxConnLifetime(maxConnLifetime time.Duration) Option {
+	return func(c *Postgres) {
+		c.maxConnLifetime = maxConnLifetime
+	}
+}
+
+// WithMaxConnIdleTime sets the duration after which an idle connection is closed.
+func WithMaxConnIdleTime(maxConnIdleTime time.Duration) Option {
+	return func(c *Postgres) {
+		c.maxConnIdleTime = maxConnIdleTime
+	}
+}
+
+// WithHealthCheckPeriod sets how often idle connections are checked.
+func WithHealthCheckPeriod(healthCheckPeriod time.Duration) Option {
+	return func(c *Postgres) {
+		c.healthCheckPeriod = healthCheckPeriod
+	}
+}
+
+// WithStatementTimeout sets statement_timeout of every connection. Zero keeps the server default.
+func WithStatementTimeout(statementTimeout time.Duration) Option {
+	return func(c *Postgres) {
+		c.statementTimeout = statementTimeout
+	}
+}
+
+// WithIdleInTransactionSessionTimeout sets idle_in_transaction_session_timeout of every connection.
+// Zero keeps the server default.
+func WithIdleInTransactionSessionTimeout(idleInTransactionSessionTimeout time.Duration) Option {
+	return func(c *Postgres) {
+		c.idleInTransactionSessionTimeout = idleInTransactionSessionTimeout
+	}
+}
+
+// WithApplicationName sets application_name of every connection, it is shown in pg_stat_activity.
+func WithApplicationName(applicationName string) Option {
+	return func(c *Postgres) {
+		c.applicationName = applicationName
+	}
+}
+
 // WithConnAttempts sets the max attempts for connecting to the Postgres.
 func WithConnAttempts(connAttempts int) Option {
 	return func(c *Postgres) {
@@ -56,9 +121,61 @@ func WithConnAttempts(connAttempts int) Option {
 	}
 }
 
-// WithConnTimeout sets the timeout for connecting to the Postgres.
+// WithConnTimeout sets the timeout of a single attempt to connect to the Postgres.
 func WithConnTimeout(connTimeout time.Duration) Option {
 	return func(c *Postgres) {
 		c.connTimeout = connTimeout
 	}
 }
+
+// WithConnBackoff sets the delay before the second attempt to connect to the Postgres.
+// The delay doubles with every next attempt up to maxBackoff.
+func WithConnBackoff(backoff, maxBackoff time.Duration) Option {
+	return func(c *Postgres) {
+		c.connBackoff = backoff
+		c.maxConnBackoff = maxBackoff
+	}
+}
+
+// WithSlowQueryThreshold sets the duration after which a query is logged as slow. Zero disables the slow query log.
+func WithSlowQueryThreshold(slowQueryThreshold time.Duration) Option {
+	return func(c *Postgres) {
+		c.slowQueryThreshold = slowQueryThreshold
+	}
+}
+
+// WithQueryHooks sets the hooks that observe every query, for example to record metrics or traces.
+func WithQueryHooks(hooks ...QueryHook) Option {
+	return func(c *Postgres) {
+		c.queryHooks = append(c.queryHooks, hooks...)
+	}
+}
+
+// WithReplicas sets the replicas that serve ReadQuerier.
+func WithReplicas(replicas ...ConnectionConfig) Option {
+	return func(c *Postgres) {
+		c.replicaConfigs = replicas
+	}
+}
+
+// WithReplicaCheckPeriod sets how often the health of the replicas is checked.
+func WithReplicaCheckPeriod(replicaCheckPeriod time.Duration) Option {
+	return func(c *Postgres) {
+		c.replicaCheckPeriod = replicaCheckPeriod
+	}
+}
+
+// WithReadYourWritesWindow sets how long the reads of a session go to the primary after its last write.
+// Zero limits read-your-writes to the request that wrote.
+func WithReadYourWritesWindow(readYourWritesWindow time.Duration) Option {
+	return func(c *Postgres) {
+		c.readYourWritesWindow = readYourWritesWindow
+	}
+}
+
+// WithLogger sets the logger for connection attempts and replica health changes.
+func WithLogger(logger *zap.Logger) Option {
+	return func(c *Postgres) {
+		c.logger = logger
+	}
+}
@@ -0,0 +1,261 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/maypok86/conduit/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const defaultSlowQueryThreshold = 200 * time.Millisecond
+
+type queryNameKey struct{}
+
+// WithQueryName names the queries made with ctx. The name identifies the query in slow query logs and hooks.
+func WithQueryName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, queryNameKey{}, name)
+}
+
+func queryName(ctx context.Context, sql string) string {
+	if name, ok := ctx.Value(queryNameKey{}).(string); ok {
+		return name
+	}
+
+	// Unnamed queries are told apart by their statement type at least.
+	if fields := strings.Fields(sql); len(fields) > 0 {
+		return strings.ToLower(fields[0])
+	}
+
+	return "unknown"
+}
+
+// QueryEvent describes a query executed through an InstrumentedPool.
+type QueryEvent struct {
+	Name         string
+	SQL          string
+	StartedAt    time.Time
+	Duration     time.Duration
+	RowsAffected int64
+	// Err is the error of the query. pgx.ErrNoRows is not reported because it means the query found nothing.
+	Err error
+}
+
+// QueryHook observes queries. BeforeQuery may return a derived context, for example with a tracing span,
+// the context is passed to the query and to AfterQuery.
+type QueryHook interface {
+	BeforeQuery(ctx context.Context, event *QueryEvent) context.Context
+	AfterQuery(ctx context.Context, event *QueryEvent)
+}
+
+// InstrumentedPool is a PgxPool that measures every query, warns about slow queries and calls hooks.
+// Queries in transactions started by the pool are instrumented too.
+type InstrumentedPool struct {
+	pool               PgxPool
+	slowQueryThreshold time.Duration
+	hooks              []QueryHook
+}
+
+// NewInstrumentedPool wraps pool. Queries longer than slowQueryThreshold are logged at Warn,
+// zero disables the slow query log.
+func NewInstrumentedPool(pool PgxPool, slowQueryThreshold time.Duration, hooks ...QueryHook) *InstrumentedPool {
+	return &InstrumentedPool{
+		pool:               pool,
+		slowQueryThreshold: slowQueryThreshold,
+		hooks:              hooks,
+	}
+}
+
+// Close closes the wrapped pool.
+func (ip *InstrumentedPool) Close() {
+	ip.pool.Close()
+}
+
+// Ping pings the wrapped pool.
+func (ip *InstrumentedPool) Ping(ctx context.Context) error {
+	return ip.pool.Ping(ctx) //nolint:wrapcheck
+}
+
+// Exec executes sql and records the number of affected rows.
+func (ip *InstrumentedPool) Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error) {
+	return ip.exec(ctx, ip.pool, sql, arguments...)
+}
+
+// Query executes sql, the query is recorded when the rows are closed.
+func (ip *InstrumentedPool) Query(ctx context.Context, sql string, optionsAndArgs ...interface{}) (pgx.Rows, error) {
+	return ip.query(ctx, ip.pool, sql, optionsAndArgs...)
+}
+
+// QueryRow executes sql, the query is recorded when the row is scanned.
+func (ip *InstrumentedPool) QueryRow(ctx context.Context, sql string, optionsAndArgs ...interface{}) pgx.Row {
+	return ip.queryRow(ctx, ip.pool, sql, optionsAndArgs...)
+}
+
+// Begin starts a transaction whose queries are instrumented.
+func (ip *InstrumentedPool) Begin(ctx context.Context) (pgx.Tx, error) {
+	tx, err := ip.pool.Begin(ctx)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return instrumentedTx{Tx: tx, pool: ip}, nil
+}
+
+// BeginTx starts a transaction with txOptions whose queries are instrumented.
+func (ip *InstrumentedPool) BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error) {
+	tx, err := ip.pool.BeginTx(ctx, txOptions)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return instrumentedTx{Tx: tx, pool: ip}, nil
+}
+
+func (ip *InstrumentedPool) exec(
+	ctx context.Context,
+	querier Querier,
+	sql string,
+	arguments ...interface{},
+) (pgconn.CommandTag, error) {
+	ctx, event := ip.start(ctx, sql)
+
+	commandTag, err := querier.Exec(ctx, sql, arguments...)
+	ip.finish(ctx, event, commandTag.RowsAffected(), err)
+
+	return commandTag, err //nolint:wrapcheck
+}
+
+func (ip *InstrumentedPool) query(
+	ctx context.Context,
+	querier Querier,
+	sql string,
+	optionsAndArgs ...interface{},
+) (pgx.Rows, error) {
+	ctx, event := ip.start(ctx, sql)
+
+	rows, err := querier.Query(ctx, sql, optionsAndArgs...)
+	if err != nil {
+		ip.finish(ctx, event, 0, err)
+
+		return nil, err //nolint:wrapcheck
+	}
+
+	return &instrumentedRows{Rows: rows, ctx: ctx, event: event, pool: ip}, nil
+}
+
+func (ip *InstrumentedPool) queryRow(
+	ctx context.Context,
+	querier Querier,
+	sql string,
+	optionsAndArgs ...interface{},
+) pgx.Row {
+	ctx, event := ip.start(ctx, sql)
+
+	return instrumentedRow{Row: querier.QueryRow(ctx, sql, optionsAndArgs...), ctx: ctx, event: event, pool: ip}
+}
+
+func (ip *InstrumentedPool) start(ctx context.Context, sql string) (context.Context, *QueryEvent) {
+	event := &QueryEvent{
+		Name:      queryName(ctx, sql),
+		SQL:       sql,
+		StartedAt: time.Now(),
+	}
+
+	for _, hook := range ip.hooks {
+		ctx = hook.BeforeQuery(ctx, event)
+	}
+
+	return ctx, event
+}
+
+func (ip *InstrumentedPool) finish(ctx context.Context, event *QueryEvent, rowsAffected int64, err error) {
+	event.Duration = time.Since(event.StartedAt)
+	event.RowsAffected = rowsAffected
+
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		event.Err = err
+	}
+
+	if ip.slowQueryThreshold > 0 && event.Duration >= ip.slowQueryThreshold {
+		logger.FromContext(ctx).Warn(
+			"slow query",
+			zap.String("query", event.Name),
+			zap.Duration("duration", event.Duration),
+			zap.Int64("rows_affected", event.RowsAffected),
+			zap.String("sql", event.SQL),
+		)
+	}
+
+	for i := len(ip.hooks) - 1; i >= 0; i-- {
+		ip.hooks[i].AfterQuery(ctx, event)
+	}
+}
+
+type instrumentedRows struct {
+	pgx.Rows
+	ctx      context.Context
+	event    *QueryEvent
+	pool     *InstrumentedPool
+	finished bool
+}
+
+func (r *instrumentedRows) Close() {
+	r.Rows.Close()
+
+	if r.finished {
+		return
+	}
+
+	r.finished = true
+	r.pool.finish(r.ctx, r.event, r.Rows.CommandTag().RowsAffected(), r.Rows.Err())
+}
+
+type instrumentedRow struct {
+	pgx.Row
+	ctx   context.Context
+	event *QueryEvent
+	pool  *InstrumentedPool
+}
+
+func (r instrumentedRow) Scan(dest ...interface{}) error {
+	err := r.Row.Scan(dest...)
+
+	var rowsAffected int64
+	if err == nil {
+		rowsAffected = 1
+	}
+
+	r.pool.finish(r.ctx, r.event, rowsAffected, err)
+
+	return err //nolint:wrapcheck
+}
+
+type instrumentedTx struct {
+	pgx.Tx
+	pool *InstrumentedPool
+}
+
+func (tx instrumentedTx) Begin(ctx context.Context) (pgx.Tx, error) {
+	savepoint, err := tx.Tx.Begin(ctx)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return instrumentedTx{Tx: savepoint, pool: tx.pool}, nil
+}
+
+func (tx instrumentedTx) Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error) {
+	return tx.pool.exec(ctx, tx.Tx, sql, arguments...)
+}
+
+func (tx instrumentedTx) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	return tx.pool.query(ctx, tx.Tx, sql, args...)
+}
+
+func (tx instrumentedTx) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	return tx.pool.queryRow(ctx, tx.Tx, sql, args...)
+}
@@ -0,0 +1,154 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/maypok86/conduit/pkg/logger"
+	"github.com/maypok86/conduit/pkg/postgres"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+type hookKey struct{}
+
+type recordingHook struct {
+	before []string
+	after  []postgres.QueryEvent
+}
+
+func (h *recordingHook) BeforeQuery(ctx context.Context, event *postgres.QueryEvent) context.Context {
+	h.before = append(h.before, event.Name)
+
+	return context.WithValue(ctx, hookKey{}, event.Name)
+}
+
+func (h *recordingHook) AfterQuery(ctx context.Context, event *postgres.QueryEvent) {
+	if ctx.Value(hookKey{}) != event.Name {
+		panic("context of BeforeQuery is not passed to AfterQuery")
+	}
+
+	h.after = append(h.after, *event)
+}
+
+type fakeRow struct {
+	err error
+}
+
+func (r fakeRow) Scan(...interface{}) error {
+	return r.err
+}
+
+type fakeRows struct {
+	pgx.Rows
+	closed bool
+}
+
+func (r *fakeRows) Close() {
+	r.closed = true
+}
+
+func (r *fakeRows) Err() error {
+	return nil
+}
+
+func (r *fakeRows) CommandTag() pgconn.CommandTag {
+	return pgconn.CommandTag("SELECT 3")
+}
+
+type queryPool struct {
+	postgres.PgxPool
+	delay  time.Duration
+	rowErr error
+	rows   *fakeRows
+}
+
+func (p *queryPool) Exec(context.Context, string, ...interface{}) (pgconn.CommandTag, error) {
+	time.Sleep(p.delay)
+
+	return pgconn.CommandTag("UPDATE 2"), nil
+}
+
+func (p *queryPool) Query(context.Context, string, ...interface{}) (pgx.Rows, error) {
+	return p.rows, nil
+}
+
+func (p *queryPool) QueryRow(context.Context, string, ...interface{}) pgx.Row {
+	return fakeRow{err: p.rowErr}
+}
+
+func (p *queryPool) BeginTx(context.Context, pgx.TxOptions) (pgx.Tx, error) {
+	return &queryTx{pool: p}, nil
+}
+
+type queryTx struct {
+	pgx.Tx
+	pool *queryPool
+}
+
+func (tx *queryTx) Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error) {
+	return tx.pool.Exec(ctx, sql, arguments...)
+}
+
+func TestInstrumentedPool(t *testing.T) {
+	t.Parallel()
+
+	hook := &recordingHook{}
+	pool := &queryPool{rowErr: pgx.ErrNoRows, rows: &fakeRows{}}
+	instrumented := postgres.NewInstrumentedPool(pool, 0, hook)
+	ctx := context.Background()
+
+	_, err := instrumented.Exec(postgres.WithQueryName(ctx, "update_user"), "UPDATE users SET bio = $1", "bio")
+	require.NoError(t, err)
+
+	err = instrumented.QueryRow(ctx, "SELECT id FROM users").Scan()
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+
+	rows, err := instrumented.Query(postgres.WithQueryName(ctx, "list_users"), "SELECT id FROM users")
+	require.NoError(t, err)
+	require.Len(t, hook.after, 2)
+	rows.Close()
+	rows.Close()
+	require.True(t, pool.rows.closed)
+
+	tx, err := instrumented.BeginTx(ctx, pgx.TxOptions{})
+	require.NoError(t, err)
+	_, err = tx.Exec(postgres.WithQueryName(ctx, "delete_user"), "DELETE FROM users")
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"update_user", "select", "list_users", "delete_user"}, hook.before)
+	require.Len(t, hook.after, 4)
+
+	for i, wantRows := range []int64{2, 0, 3, 2} {
+		require.Equal(t, hook.before[i], hook.after[i].Name)
+		require.Equal(t, wantRows, hook.after[i].RowsAffected)
+		require.NoError(t, hook.after[i].Err)
+	}
+}
+
+func TestInstrumentedPool_SlowQuery(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zapcore.WarnLevel)
+	ctx := logger.ContextWithLogger(context.Background(), zap.New(core))
+
+	pool := &queryPool{}
+	instrumented := postgres.NewInstrumentedPool(pool, 10*time.Millisecond)
+
+	_, err := instrumented.Exec(postgres.WithQueryName(ctx, "fast"), "UPDATE users SET bio = NULL")
+	require.NoError(t, err)
+	require.Zero(t, logs.Len())
+
+	pool.delay = 20 * time.Millisecond
+	_, err = instrumented.Exec(postgres.WithQueryName(ctx, "slow"), "UPDATE users SET bio = NULL")
+	require.NoError(t, err)
+
+	entries := logs.FilterMessage("slow query").All()
+	require.Len(t, entries, 1)
+	require.Equal(t, "slow", entries[0].ContextMap()["query"])
+}
@@ -5,17 +5,26 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
+	"strconv"
+	"sync"
 	"time"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/jackc/pgx/v4/pgxpool"
+	"go.uber.org/zap"
 )
 
 const (
-	defaultMaxPoolSize  int           = 1
-	defaultConnAttempts int           = 10
-	defaultConnTimeout  time.Duration = time.Second
+	defaultMaxPoolSize       int           = 10
+	defaultMaxConnLifetime   time.Duration = time.Hour
+	defaultMaxConnIdleTime   time.Duration = 30 * time.Minute
+	defaultHealthCheckPeriod time.Duration = time.Minute
+	defaultConnAttempts      int           = 10
+	defaultConnTimeout       time.Duration = 5 * time.Second
+	defaultConnBackoff       time.Duration = 100 * time.Millisecond
+	defaultMaxConnBackoff    time.Duration = 5 * time.Second
+	defaultReplicaCheck      time.Duration = 5 * time.Second
+	defaultReadYourWrites    time.Duration = 5 * time.Second
 )
 
 // ErrUnableToConnect is returned when unable to connect to the postgres.
@@ -23,19 +32,50 @@ var ErrUnableToConnect = errors.New("all attempts are exceeded. Unable to connec
 
 // Postgres is a postgres connection.
 type Postgres struct {
-	maxPoolSize  int
-	connAttempts int
-	connTimeout  time.Duration
-	Builder      sq.StatementBuilderType
-	Pool         PgxPool
+	maxPoolSize                     int
+	minPoolSize                     int
+	maxConnLifetime                 time.Duration
+	maxConnIdleTime                 time.Duration
+	healthCheckPeriod               time.Duration
+	statementTimeout                time.Duration
+	idleInTransactionSessionTimeout time.Duration
+	applicationName                 string
+	connAttempts                    int
+	connTimeout                     time.Duration
+	connBackoff                     time.Duration
+	maxConnBackoff                  time.Duration
+	logger                          *zap.Logger
+	slowQueryThreshold              time.Duration
+	queryHooks                      []QueryHook
+	replicaConfigs                  []ConnectionConfig
+	replicaCheckPeriod              time.Duration
+	readYourWritesWindow            time.Duration
+	replicas                        []*replica
+	nextReplica                     uint32
+	stopReplicaMonitor              context.CancelFunc
+	writesMu                        sync.Mutex
+	lastWrites                      map[string]time.Time
+	lastSweep                       time.Time
+	primary                         *pgxpool.Pool
+	Builder                         sq.StatementBuilderType
+	Pool                            PgxPool
 }
 
 // New creates a new postgres connection.
 func New(ctx context.Context, connectionConfig ConnectionConfig, opts ...Option) (*Postgres, error) {
 	instance := &Postgres{
-		maxPoolSize:  defaultMaxPoolSize,
-		connAttempts: defaultConnAttempts,
-		connTimeout:  defaultConnTimeout,
+		maxPoolSize:          defaultMaxPoolSize,
+		maxConnLifetime:      defaultMaxConnLifetime,
+		maxConnIdleTime:      defaultMaxConnIdleTime,
+		healthCheckPeriod:    defaultHealthCheckPeriod,
+		connAttempts:         defaultConnAttempts,
+		connTimeout:          defaultConnTimeout,
+		connBackoff:          defaultConnBackoff,
+		maxConnBackoff:       defaultMaxConnBackoff,
+		replicaCheckPeriod:   defaultReplicaCheck,
+		readYourWritesWindow: defaultReadYourWrites,
+		slowQueryThreshold:   defaultSlowQueryThreshold,
+		logger:               zap.NewNop(),
 	}
 
 	for _, opt := range opts {
@@ -44,36 +84,127 @@ func New(ctx context.Context, connectionConfig ConnectionConfig, opts ...Option)
 
 	instance.Builder = sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 
-	dsn := connectionConfig.getDSN()
+	poolCfg, err := instance.parseConfig(connectionConfig.DSN())
+	if err != nil {
+		return nil, err
+	}
+
+	pool, err := instance.connect(ctx, poolCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	instance.primary = pool
+	instance.Pool = instance.instrument(pool)
+
+	if err := instance.connectReplicas(ctx); err != nil {
+		instance.Close()
+
+		return nil, err
+	}
+
+	return instance, nil
+}
 
+func (p *Postgres) parseConfig(dsn string) (*pgxpool.Config, error) {
 	poolCfg, err := pgxpool.ParseConfig(dsn)
 	if err != nil {
 		return nil, fmt.Errorf("unable to parse config: %w", err)
 	}
 
-	poolCfg.MaxConns = int32(instance.maxPoolSize)
+	poolCfg.MaxConns = int32(p.maxPoolSize)
+	poolCfg.MinConns = int32(p.minPoolSize)
+	poolCfg.MaxConnLifetime = p.maxConnLifetime
+	poolCfg.MaxConnIdleTime = p.maxConnIdleTime
+	poolCfg.HealthCheckPeriod = p.healthCheckPeriod
+
+	runtimeParams := poolCfg.ConnConfig.RuntimeParams
+	if p.statementTimeout > 0 {
+		runtimeParams["statement_timeout"] = strconv.FormatInt(p.statementTimeout.Milliseconds(), 10)
+	}
+
+	if p.idleInTransactionSessionTimeout > 0 {
+		runtimeParams["idle_in_transaction_session_timeout"] = strconv.FormatInt(
+			p.idleInTransactionSessionTimeout.Milliseconds(),
+			10,
+		)
+	}
+
+	if p.applicationName != "" {
+		runtimeParams["application_name"] = p.applicationName
+	}
+
+	return poolCfg, nil
+}
+
+// connect tries to connect connAttempts times, the delay between attempts grows exponentially.
+func (p *Postgres) connect(ctx context.Context, poolCfg *pgxpool.Config) (*pgxpool.Pool, error) {
+	backoff := p.connBackoff
+
+	for attempt := 1; ; attempt++ {
+		attemptCtx, cancel := context.WithTimeout(ctx, p.connTimeout)
+		pool, err := pgxpool.ConnectConfig(attemptCtx, poolCfg)
+
+		cancel()
 
-	for instance.connAttempts > 0 {
-		instance.Pool, err = pgxpool.ConnectConfig(ctx, poolCfg)
 		if err == nil {
-			break
+			return pool, nil
+		}
+
+		if attempt >= p.connAttempts {
+			p.logger.Error("Postgres is unavailable", zap.Error(err), zap.Int("attempts", attempt))
+
+			return nil, ErrUnableToConnect
 		}
 
-		log.Printf("Postgres is trying to connect, attempts left: %d", instance.connAttempts)
-		time.Sleep(instance.connTimeout)
+		p.logger.Warn(
+			"Postgres is unavailable, retrying",
+			zap.Error(err),
+			zap.Int("attempts_left", p.connAttempts-attempt),
+			zap.Duration("backoff", backoff),
+		)
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("can not connect to postgres: %w", ctx.Err())
+		case <-time.After(backoff):
+		}
 
-		instance.connAttempts--
+		backoff *= 2
+		if backoff > p.maxConnBackoff {
+			backoff = p.maxConnBackoff
+		}
 	}
+}
 
-	if err != nil {
-		return nil, ErrUnableToConnect
+func (p *Postgres) instrument(pool PgxPool) PgxPool {
+	return NewInstrumentedPool(pool, p.slowQueryThreshold, p.queryHooks...)
+}
+
+// Ping checks the connection to the primary.
+func (p *Postgres) Ping(ctx context.Context) error {
+	if err := p.Pool.Ping(ctx); err != nil {
+		return fmt.Errorf("can not ping postgres: %w", err)
 	}
 
-	return instance, nil
+	return nil
+}
+
+// Stat returns the statistics of the primary pool.
+func (p *Postgres) Stat() *pgxpool.Stat {
+	return p.primary.Stat()
 }
 
-// Close closes the postgres connection.
+// Close closes the primary and replica connections.
 func (p *Postgres) Close() {
+	if p.stopReplicaMonitor != nil {
+		p.stopReplicaMonitor()
+	}
+
+	for _, r := range p.replicas {
+		r.pool.Close()
+	}
+
 	if p.Pool != nil {
 		p.Pool.Close()
 	}
@@ -0,0 +1,44 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/maypok86/conduit/pkg/postgres"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew_InvalidURL(t *testing.T) {
+	t.Parallel()
+
+	_, err := postgres.New(context.Background(), postgres.NewURLConnectionConfig("postgres://localhost:port"))
+	require.Error(t, err)
+}
+
+func TestNew_UnableToConnect(t *testing.T) {
+	t.Parallel()
+
+	_, err := postgres.New(
+		context.Background(),
+		postgres.NewURLConnectionConfig("postgres://conduit@127.0.0.1:1/conduit?sslmode=disable"),
+		postgres.WithConnAttempts(3),
+		postgres.WithConnTimeout(time.Second),
+		postgres.WithConnBackoff(time.Millisecond, 2*time.Millisecond),
+	)
+	require.ErrorIs(t, err, postgres.ErrUnableToConnect)
+}
+
+func TestNew_ContextCanceled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := postgres.New(
+		ctx,
+		postgres.NewURLConnectionConfig("postgres://conduit@127.0.0.1:1/conduit?sslmode=disable"),
+		postgres.WithConnBackoff(time.Hour, time.Hour),
+	)
+	require.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,249 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"go.uber.org/zap"
+)
+
+type replica struct {
+	pool PgxPool
+	// healthy is 1 when the last health check succeeded.
+	healthy int32
+}
+
+func (r *replica) isHealthy() bool {
+	return atomic.LoadInt32(&r.healthy) == 1
+}
+
+type sessionKey struct{}
+
+type session struct {
+	key string
+	// wrote is 1 after the first write of the request.
+	wrote int32
+}
+
+// WithSession marks ctx as a request of the client identified by key. After a write in ctx the rest of the
+// request reads from the primary, and so do the requests with the same key within the read-your-writes window.
+// An empty key limits read-your-writes to the request itself.
+func WithSession(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, sessionKey{}, &session{key: key})
+}
+
+func sessionFromContext(ctx context.Context) *session {
+	s, _ := ctx.Value(sessionKey{}).(*session)
+
+	return s
+}
+
+// sessionQuerier queries the primary and remembers the writes made in the session.
+type sessionQuerier struct {
+	db      *Postgres
+	session *session
+}
+
+func (q sessionQuerier) Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error) {
+	q.track(sql)
+
+	return q.db.Pool.Exec(ctx, sql, arguments...)
+}
+
+func (q sessionQuerier) Query(ctx context.Context, sql string, optionsAndArgs ...interface{}) (pgx.Rows, error) {
+	q.track(sql)
+
+	return q.db.Pool.Query(ctx, sql, optionsAndArgs...)
+}
+
+func (q sessionQuerier) QueryRow(ctx context.Context, sql string, optionsAndArgs ...interface{}) pgx.Row {
+	q.track(sql)
+
+	return q.db.Pool.QueryRow(ctx, sql, optionsAndArgs...)
+}
+
+// track treats every statement except a plain SELECT as a write.
+func (q sessionQuerier) track(sql string) {
+	const selectPrefix = "SELECT"
+
+	sql = strings.TrimSpace(sql)
+	if len(sql) >= len(selectPrefix) && strings.EqualFold(sql[:len(selectPrefix)], selectPrefix) {
+		return
+	}
+
+	q.db.recordWrite(q.session)
+}
+
+func (p *Postgres) recordWrite(s *session) {
+	if s == nil {
+		return
+	}
+
+	atomic.StoreInt32(&s.wrote, 1)
+
+	if s.key == "" || p.readYourWritesWindow <= 0 {
+		return
+	}
+
+	now := time.Now()
+
+	p.writesMu.Lock()
+	defer p.writesMu.Unlock()
+
+	if p.lastWrites == nil {
+		p.lastWrites = make(map[string]time.Time)
+	}
+
+	p.lastWrites[s.key] = now
+
+	if now.Sub(p.lastSweep) < p.readYourWritesWindow {
+		return
+	}
+
+	for key, wroteAt := range p.lastWrites {
+		if now.Sub(wroteAt) >= p.readYourWritesWindow {
+			delete(p.lastWrites, key)
+		}
+	}
+
+	p.lastSweep = now
+}
+
+func (p *Postgres) wroteRecently(s *session) bool {
+	if s == nil {
+		return false
+	}
+
+	if atomic.LoadInt32(&s.wrote) == 1 {
+		return true
+	}
+
+	if s.key == "" || p.readYourWritesWindow <= 0 {
+		return false
+	}
+
+	p.writesMu.Lock()
+	defer p.writesMu.Unlock()
+
+	wroteAt, ok := p.lastWrites[s.key]
+
+	return ok && time.Since(wroteAt) < p.readYourWritesWindow
+}
+
+// AddReplica adds a replica pool that serves ReadQuerier. The replica is considered healthy until CheckReplicas
+// reports otherwise.
+func (p *Postgres) AddReplica(pool PgxPool) {
+	p.replicas = append(p.replicas, &replica{pool: pool, healthy: 1})
+}
+
+// ReadQuerier returns a querier for reads that tolerate replication lag.
+//
+// The transaction from ctx is returned if there is one. The primary pool is returned after a write in the
+// session of ctx and when no replica is healthy, otherwise healthy replicas are used in turn.
+func (p *Postgres) ReadQuerier(ctx context.Context) Querier {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx
+	}
+
+	if len(p.replicas) == 0 || p.wroteRecently(sessionFromContext(ctx)) {
+		return p.Querier(ctx)
+	}
+
+	start := atomic.AddUint32(&p.nextReplica, 1)
+	for i := range p.replicas {
+		r := p.replicas[(int(start)+i)%len(p.replicas)]
+		if r.isHealthy() {
+			return r.pool
+		}
+	}
+
+	return p.Querier(ctx)
+}
+
+// CheckReplicas pings every replica and updates its health. Unhealthy replicas receive no reads until
+// a later check succeeds.
+func (p *Postgres) CheckReplicas(ctx context.Context) {
+	logger := p.logger
+	if logger == nil {
+		logger = zap.L()
+	}
+
+	for i, r := range p.replicas {
+		pingCtx, cancel := ctx, context.CancelFunc(func() {})
+		if p.connTimeout > 0 {
+			pingCtx, cancel = context.WithTimeout(ctx, p.connTimeout)
+		}
+
+		err := r.pool.Ping(pingCtx)
+
+		cancel()
+
+		var healthy int32
+		if err == nil {
+			healthy = 1
+		}
+
+		if atomic.SwapInt32(&r.healthy, healthy) == healthy {
+			continue
+		}
+
+		if err != nil {
+			logger.Warn("Postgres replica is unhealthy, reads fall back to the primary",
+				zap.Int("replica", i), zap.Error(err))
+		} else {
+			logger.Info("Postgres replica is healthy again", zap.Int("replica", i))
+		}
+	}
+}
+
+func (p *Postgres) connectReplicas(ctx context.Context) error {
+	for _, replicaConfig := range p.replicaConfigs {
+		poolCfg, err := p.parseConfig(replicaConfig.DSN())
+		if err != nil {
+			return fmt.Errorf("can not parse replica config: %w", err)
+		}
+
+		// An unavailable replica must not prevent the start, the health checks exclude it from reads.
+		poolCfg.LazyConnect = true
+
+		pool, err := pgxpool.ConnectConfig(ctx, poolCfg)
+		if err != nil {
+			return fmt.Errorf("can not create replica pool: %w", err)
+		}
+
+		p.AddReplica(p.instrument(pool))
+	}
+
+	if len(p.replicas) == 0 {
+		return nil
+	}
+
+	p.CheckReplicas(ctx)
+
+	monitorCtx, cancel := context.WithCancel(context.Background())
+	p.stopReplicaMonitor = cancel
+
+	go p.monitorReplicas(monitorCtx)
+
+	return nil
+}
+
+func (p *Postgres) monitorReplicas(ctx context.Context) {
+	ticker := time.NewTicker(p.replicaCheckPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.CheckReplicas(ctx)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package postgres_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/maypok86/conduit/pkg/postgres"
+	"github.com/stretchr/testify/require"
+)
+
+func newReplicated(replicas int) (*postgres.Postgres, *fakePool, []*fakePool) {
+	primary := &fakePool{}
+	db := &postgres.Postgres{Pool: primary}
+	postgres.WithReadYourWritesWindow(time.Minute)(db)
+
+	replicaPools := make([]*fakePool, 0, replicas)
+	for i := 0; i < replicas; i++ {
+		replica := &fakePool{}
+		replicaPools = append(replicaPools, replica)
+		db.AddReplica(replica)
+	}
+
+	return db, primary, replicaPools
+}
+
+func TestPostgres_ReadQuerier(t *testing.T) {
+	t.Parallel()
+
+	db, primary, replicas := newReplicated(2)
+	ctx := context.Background()
+
+	first := db.ReadQuerier(ctx)
+	second := db.ReadQuerier(ctx)
+	require.NotSame(t, first, second)
+	require.Contains(t, []postgres.Querier{replicas[0], replicas[1]}, first)
+	require.Contains(t, []postgres.Querier{replicas[0], replicas[1]}, second)
+
+	err := db.WithinTransaction(ctx, func(ctx context.Context) error {
+		require.Same(t, primary.txs[0], db.ReadQuerier(ctx))
+
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestPostgres_ReadQuerierWithoutReplicas(t *testing.T) {
+	t.Parallel()
+
+	db, primary, _ := newReplicated(0)
+
+	require.Same(t, primary, db.ReadQuerier(context.Background()))
+}
+
+func TestPostgres_ReadYourWrites(t *testing.T) {
+	t.Parallel()
+
+	db, _, replicas := newReplicated(1)
+
+	ctx := postgres.WithSession(context.Background(), "token")
+	require.Same(t, replicas[0], db.ReadQuerier(ctx))
+
+	_, err := db.Querier(ctx).Exec(ctx, "SELECT 1")
+	require.NoError(t, err)
+	require.Same(t, replicas[0], db.ReadQuerier(ctx))
+
+	_, err = db.Querier(ctx).Exec(ctx, "UPDATE users SET bio = $1", "bio")
+	require.NoError(t, err)
+	require.NotSame(t, replicas[0], db.ReadQuerier(ctx))
+
+	// Another request of the same client is within the read-your-writes window.
+	sameClient := postgres.WithSession(context.Background(), "token")
+	require.NotSame(t, replicas[0], db.ReadQuerier(sameClient))
+
+	otherClient := postgres.WithSession(context.Background(), "other")
+	require.Same(t, replicas[0], db.ReadQuerier(otherClient))
+}
+
+func TestPostgres_ReadYourWritesTransaction(t *testing.T) {
+	t.Parallel()
+
+	db, _, replicas := newReplicated(1)
+
+	ctx := postgres.WithSession(context.Background(), "")
+	require.NoError(t, db.WithinTransaction(ctx, func(ctx context.Context) error {
+		return nil
+	}))
+	require.NotSame(t, replicas[0], db.ReadQuerier(ctx))
+
+	// Without a key read-your-writes is limited to the request.
+	require.Same(t, replicas[0], db.ReadQuerier(postgres.WithSession(context.Background(), "")))
+}
+
+func TestPostgres_CheckReplicas(t *testing.T) {
+	t.Parallel()
+
+	db, primary, replicas := newReplicated(2)
+	ctx := context.Background()
+
+	replicas[0].pingErr = errors.New("replica is down")
+	db.CheckReplicas(ctx)
+
+	for i := 0; i < 4; i++ {
+		require.Same(t, replicas[1], db.ReadQuerier(ctx))
+	}
+
+	replicas[1].pingErr = errors.New("replica is down")
+	db.CheckReplicas(ctx)
+	require.Same(t, primary, db.ReadQuerier(ctx))
+
+	replicas[0].pingErr = nil
+	db.CheckReplicas(ctx)
+	require.Same(t, replicas[0], db.ReadQuerier(ctx))
+}
@@ -0,0 +1,54 @@
+package postgres
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/maypok86/conduit/pkg/postgres"
+
+// TracingHook is a QueryHook that records every query as an OpenTelemetry span.
+type TracingHook struct {
+	tracer trace.Tracer
+}
+
+// NewTracingHook returns a new instance of TracingHook that creates the spans with provider.
+func NewTracingHook(provider trace.TracerProvider) TracingHook {
+	return TracingHook{
+		tracer: provider.Tracer(tracerName),
+	}
+}
+
+// BeforeQuery starts the span of the query.
+func (h TracingHook) BeforeQuery(ctx context.Context, event *QueryEvent) context.Context {
+	ctx, _ = h.tracer.Start(
+		ctx,
+		event.Name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithTimestamp(event.StartedAt),
+		trace.WithAttributes(
+			semconv.DBSystemPostgreSQL,
+			semconv.DBOperationKey.String(event.Name),
+			semconv.DBStatementKey.String(event.SQL),
+		),
+	)
+
+	return ctx
+}
+
+// AfterQuery ends the span of the query.
+func (h TracingHook) AfterQuery(ctx context.Context, event *QueryEvent) {
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(attribute.Int64("db.rows_affected", event.RowsAffected))
+
+	if event.Err != nil {
+		span.RecordError(event.Err)
+		span.SetStatus(codes.Error, event.Err.Error())
+	}
+
+	span.End(trace.WithTimestamp(event.StartedAt.Add(event.Duration)))
+}
@@ -0,0 +1,71 @@
+package postgres_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/maypok86/conduit/pkg/postgres"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracingHook(t *testing.T) {
+	t.Parallel()
+
+	errQuery := errors.New("query failed")
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus codes.Code
+	}{
+		{
+			name:       "success",
+			wantStatus: codes.Unset,
+		},
+		{
+			name:       "error",
+			err:        errQuery,
+			wantStatus: codes.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := tracetest.NewSpanRecorder()
+			hook := postgres.NewTracingHook(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+			event := &postgres.QueryEvent{
+				Name:      "user.get_by_email",
+				SQL:       "SELECT * FROM users WHERE email = $1",
+				StartedAt: time.Now(),
+			}
+
+			ctx := hook.BeforeQuery(context.Background(), event)
+
+			event.Duration = 10 * time.Millisecond
+			event.RowsAffected = 1
+			event.Err = tt.err
+			hook.AfterQuery(ctx, event)
+
+			spans := recorder.Ended()
+			require.Len(t, spans, 1)
+
+			span := spans[0]
+			require.Equal(t, event.Name, span.Name())
+			require.Equal(t, event.Duration, span.EndTime().Sub(span.StartTime()))
+			require.Equal(t, tt.wantStatus, span.Status().Code)
+			require.Contains(t, span.Attributes(), attribute.String("db.statement", event.SQL))
+			require.Contains(t, span.Attributes(), attribute.String("db.system", "postgresql"))
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v4"
+)
+
+const (
+	defaultTxMaxRetries   = 3
+	defaultTxRetryBackoff = 10 * time.Millisecond
+)
+
+type txKey struct{}
+
+// Querier executes queries in a transaction or directly in the pool.
+type Querier interface {
+	Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, optionsAndArgs ...interface{}) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, optionsAndArgs ...interface{}) pgx.Row
+}
+
+// Querier returns the transaction started by WithinTransaction or the primary pool if there is no transaction
+// in ctx. Writes made through it in a session make the following reads of the session use the primary.
+func (p *Postgres) Querier(ctx context.Context) Querier {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx
+	}
+
+	if s := sessionFromContext(ctx); s != nil {
+		return sessionQuerier{db: p, session: s}
+	}
+
+	return p.Pool
+}
+
+type txConfig struct {
+	options      pgx.TxOptions
+	maxRetries   int
+	retryBackoff time.Duration
+}
+
+// TxOption is a functional option for configuring a transaction.
+type TxOption func(*txConfig)
+
+// WithIsolationLevel sets the isolation level of the transaction.
+func WithIsolationLevel(level pgx.TxIsoLevel) TxOption {
+	return func(c *txConfig) {
+		c.options.IsoLevel = level
+	}
+}
+
+// WithAccessMode sets the access mode of the transaction.
+func WithAccessMode(mode pgx.TxAccessMode) TxOption {
+	return func(c *txConfig) {
+		c.options.AccessMode = mode
+	}
+}
+
+// WithMaxRetries sets how many times the transaction is retried after a serialization failure or a deadlock.
+func WithMaxRetries(maxRetries int) TxOption {
+	return func(c *txConfig) {
+		c.maxRetries = maxRetries
+	}
+}
+
+// WithRetryBackoff sets the delay before the first retry, the delay doubles with every next retry.
+func WithRetryBackoff(backoff time.Duration) TxOption {
+	return func(c *txConfig) {
+		c.retryBackoff = backoff
+	}
+}
+
+// WithinTransaction runs fn in a transaction with the default options.
+func (p *Postgres) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return p.WithinTransactionOptions(ctx, fn)
+}
+
+// WithinTransactionOptions runs fn in a transaction. The transaction is committed if fn returns nil and
+// rolled back otherwise. The whole transaction is retried on serialization failures and deadlocks,
+// so fn must be safe to run more than once.
+//
+// A call nested in fn runs in a savepoint of the outer transaction: its error rolls back only the work done
+// since the savepoint. Options of nested calls are ignored because they can not change a running transaction.
+func (p *Postgres) WithinTransactionOptions(
+	ctx context.Context,
+	fn func(ctx context.Context) error,
+	opts ...TxOption,
+) error {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return runInSavepoint(ctx, tx, fn)
+	}
+
+	cfg := txConfig{
+		maxRetries:   defaultTxMaxRetries,
+		retryBackoff: defaultTxRetryBackoff,
+	}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	backoff := cfg.retryBackoff
+
+	for attempt := 0; ; attempt++ {
+		err := p.runInTransaction(ctx, cfg.options, fn)
+		if err == nil || attempt >= cfg.maxRetries || !isRetryable(err) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("can not retry transaction: %w", ctx.Err())
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+	}
+}
+
+func (p *Postgres) runInTransaction(
+	ctx context.Context,
+	options pgx.TxOptions,
+	fn func(ctx context.Context) error,
+) error {
+	tx, err := p.Pool.BeginTx(ctx, options)
+	if err != nil {
+		return fmt.Errorf("can not begin transaction: %w", err)
+	}
+
+	p.recordWrite(sessionFromContext(ctx))
+
+	defer func() {
+		// Rollback is a no-op after a successful commit.
+		_ = tx.Rollback(ctx)
+	}()
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("can not commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func runInSavepoint(ctx context.Context, tx pgx.Tx, fn func(ctx context.Context) error) error {
+	// Begin of a pgx.Tx creates a savepoint, its Commit releases the savepoint and Rollback rolls back to it.
+	savepoint, err := tx.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("can not create savepoint: %w", err)
+	}
+
+	defer func() {
+		_ = savepoint.Rollback(ctx)
+	}()
+
+	if err := fn(context.WithValue(ctx, txKey{}, savepoint)); err != nil {
+		return err
+	}
+
+	if err := savepoint.Commit(ctx); err != nil {
+		return fmt.Errorf("can not release savepoint: %w", err)
+	}
+
+	return nil
+}
+
+func isRetryable(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+
+	return pgErr.Code == pgerrcode.SerializationFailure || pgErr.Code == pgerrcode.DeadlockDetected
+}
@@ -0,0 +1,195 @@
+package postgres_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v4"
+	"github.com/maypok86/conduit/pkg/postgres"
+	"github.com/stretchr/testify/require"
+)
+
+var errTx = errors.New("tx error")
+
+type fakeTx struct {
+	pgx.Tx
+	parent     *fakeTx
+	commitErr  error
+	committed  bool
+	rolledBack bool
+	savepoints []*fakeTx
+}
+
+func (tx *fakeTx) Begin(context.Context) (pgx.Tx, error) {
+	savepoint := &fakeTx{parent: tx}
+	tx.savepoints = append(tx.savepoints, savepoint)
+
+	return savepoint, nil
+}
+
+func (tx *fakeTx) Commit(context.Context) error {
+	if tx.commitErr != nil {
+		return tx.commitErr
+	}
+
+	tx.committed = true
+
+	return nil
+}
+
+func (tx *fakeTx) Rollback(context.Context) error {
+	if !tx.committed {
+		tx.rolledBack = true
+	}
+
+	return nil
+}
+
+type fakePool struct {
+	postgres.PgxPool
+	commitErrs []error
+	options    []pgx.TxOptions
+	txs        []*fakeTx
+	pingErr    error
+}
+
+func (p *fakePool) Exec(context.Context, string, ...interface{}) (pgconn.CommandTag, error) {
+	return nil, nil
+}
+
+func (p *fakePool) Ping(context.Context) error {
+	return p.pingErr
+}
+
+func (p *fakePool) BeginTx(_ context.Context, options pgx.TxOptions) (pgx.Tx, error) {
+	tx := &fakeTx{}
+	if len(p.commitErrs) > len(p.txs) {
+		tx.commitErr = p.commitErrs[len(p.txs)]
+	}
+
+	p.options = append(p.options, options)
+	p.txs = append(p.txs, tx)
+
+	return tx, nil
+}
+
+func TestPostgres_WithinTransaction(t *testing.T) {
+	t.Parallel()
+
+	pool := &fakePool{}
+	db := &postgres.Postgres{Pool: pool}
+
+	err := db.WithinTransaction(context.Background(), func(ctx context.Context) error {
+		require.Equal(t, pool.txs[0], db.Querier(ctx))
+
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, pool.txs, 1)
+	require.True(t, pool.txs[0].committed)
+	require.Equal(t, pool, db.Querier(context.Background()))
+}
+
+func TestPostgres_WithinTransactionRollback(t *testing.T) {
+	t.Parallel()
+
+	pool := &fakePool{}
+	db := &postgres.Postgres{Pool: pool}
+
+	err := db.WithinTransaction(context.Background(), func(ctx context.Context) error {
+		return errTx
+	})
+	require.ErrorIs(t, err, errTx)
+	require.Len(t, pool.txs, 1)
+	require.True(t, pool.txs[0].rolledBack)
+}
+
+func TestPostgres_WithinTransactionSavepoint(t *testing.T) {
+	t.Parallel()
+
+	pool := &fakePool{}
+	db := &postgres.Postgres{Pool: pool}
+
+	err := db.WithinTransaction(context.Background(), func(ctx context.Context) error {
+		require.NoError(t, db.WithinTransaction(ctx, func(ctx context.Context) error {
+			return nil
+		}))
+
+		require.ErrorIs(t, db.WithinTransaction(ctx, func(ctx context.Context) error {
+			require.Equal(t, pool.txs[0].savepoints[1], db.Querier(ctx))
+
+			return errTx
+		}), errTx)
+
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, pool.txs, 1)
+	require.Len(t, pool.txs[0].savepoints, 2)
+	require.True(t, pool.txs[0].savepoints[0].committed)
+	require.True(t, pool.txs[0].savepoints[1].rolledBack)
+	require.True(t, pool.txs[0].committed)
+}
+
+func TestPostgres_WithinTransactionOptions(t *testing.T) {
+	t.Parallel()
+
+	serializationFailure := &pgconn.PgError{Code: pgerrcode.SerializationFailure}
+	deadlock := &pgconn.PgError{Code: pgerrcode.DeadlockDetected}
+
+	tests := []struct {
+		name       string
+		commitErrs []error
+		opts       []postgres.TxOption
+		wantTxs    int
+		wantErr    error
+	}{
+		{
+			name:       "retry serialization failure and deadlock",
+			commitErrs: []error{serializationFailure, deadlock},
+			wantTxs:    3,
+		},
+		{
+			name:       "retries are exceeded",
+			commitErrs: []error{serializationFailure, serializationFailure},
+			opts:       []postgres.TxOption{postgres.WithMaxRetries(1)},
+			wantTxs:    2,
+			wantErr:    serializationFailure,
+		},
+		{
+			name:       "other errors are not retried",
+			commitErrs: []error{errTx},
+			wantTxs:    1,
+			wantErr:    errTx,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			pool := &fakePool{commitErrs: tt.commitErrs}
+			db := &postgres.Postgres{Pool: pool}
+
+			opts := append([]postgres.TxOption{
+				postgres.WithIsolationLevel(pgx.Serializable),
+				postgres.WithRetryBackoff(0),
+			}, tt.opts...)
+
+			err := db.WithinTransactionOptions(context.Background(), func(ctx context.Context) error {
+				return nil
+			}, opts...)
+			require.ErrorIs(t, err, tt.wantErr)
+			require.Len(t, pool.txs, tt.wantTxs)
+
+			for _, options := range pool.options {
+				require.Equal(t, pgx.Serializable, options.IsoLevel)
+			}
+		})
+	}
+}
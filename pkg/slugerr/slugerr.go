@@ -13,6 +13,10 @@ var (
 	ErrorTypeAuthorization = ErrorType{"authorization"}
 	// ErrorTypeIncorrectInput defines the incorrect input type of error.
 	ErrorTypeIncorrectInput = ErrorType{"incorrect-input"}
+	// ErrorTypeConflict defines the conflict with existing data type of error.
+	ErrorTypeConflict = ErrorType{"conflict"}
+	// ErrorTypeTooManyRequests defines the rate limit exceeded type of error.
+	ErrorTypeTooManyRequests = ErrorType{"too-many-requests"}
 )
 
 // SlugError defines error for slug.
@@ -63,3 +67,21 @@ func NewIncorrectInputError(err string, slug string) SlugError {
 		errorType: ErrorTypeIncorrectInput,
 	}
 }
+
+// NewConflictError creates a new conflict error.
+func NewConflictError(err string, slug string) SlugError {
+	return SlugError{
+		err:       err,
+		slug:      slug,
+		errorType: ErrorTypeConflict,
+	}
+}
+
+// NewTooManyRequestsError creates a new too many requests error.
+func NewTooManyRequestsError(err string, slug string) SlugError {
+	return SlugError{
+		err:       err,
+		slug:      slug,
+		errorType: ErrorTypeTooManyRequests,
+	}
+}
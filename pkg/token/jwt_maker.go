@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
 )
 
 const minSecretKeySize = 32
@@ -27,9 +28,9 @@ func NewJWTMaker(secretKey string) (JWTMaker, error) {
 	return JWTMaker{secretKey}, nil
 }
 
-// CreateToken creates a new JWT web token for a specific username and duration.
-func (maker JWTMaker) CreateToken(email string, duration time.Duration) (string, error) {
-	payload, err := NewPayload(email, duration)
+// CreateToken creates a new JWT web token for a specific user and duration.
+func (maker JWTMaker) CreateToken(userID uuid.UUID, email string, duration time.Duration) (string, error) {
+	payload, err := NewPayload(userID, email, duration)
 	if err != nil {
 		return "", fmt.Errorf("failed to create payload: %w", err)
 	}
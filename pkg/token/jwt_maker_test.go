@@ -6,6 +6,7 @@ import (
 
 	"github.com/bxcodec/faker/v3"
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
 	"github.com/maypok86/conduit/pkg/token"
 	"github.com/stretchr/testify/require"
 )
@@ -57,7 +58,7 @@ func TestExpiredJWTToken(t *testing.T) {
 func TestInvalidJWTTokenAlgNone(t *testing.T) {
 	t.Parallel()
 
-	payload, err := token.NewPayload(faker.Email(), time.Minute)
+	payload, err := token.NewPayload(uuid.New(), faker.Email(), time.Minute)
 	require.NoError(t, err)
 
 	jwtToken := jwt.NewWithClaims(jwt.SigningMethodNone, payload)
@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/maypok86/conduit/pkg/token"
 	"github.com/stretchr/testify/require"
 )
@@ -11,7 +12,7 @@ import (
 func fakeToken(t *testing.T, maker token.JWTMaker, email string, duration time.Duration) string {
 	t.Helper()
 
-	token, err := maker.CreateToken(email, duration)
+	token, err := maker.CreateToken(uuid.New(), email, duration)
 	require.NoError(t, err)
 	require.NotEmpty(t, token)
 
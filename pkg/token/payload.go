@@ -19,14 +19,16 @@ var (
 
 // Payload contains the payload data of the token.
 type Payload struct {
-	ID        uuid.UUID `json:"id"`
+	ID uuid.UUID `json:"id"`
+	// UserID is the id of the user, it is zero in the tokens issued before it was added.
+	UserID    uuid.UUID `json:"user_id"`
 	Email     string    `json:"email"`
 	IssuedAt  time.Time `json:"issued_at"`
 	ExpiredAt time.Time `json:"expired_at"`
 }
 
-// NewPayload creates a new token payload with a specific username and duration.
-func NewPayload(email string, duration time.Duration) (*Payload, error) {
+// NewPayload creates a new token payload for a specific user and duration.
+func NewPayload(userID uuid.UUID, email string, duration time.Duration) (*Payload, error) {
 	tokenID, err := uuid.NewRandom()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create token id: %w", err)
@@ -34,6 +36,7 @@ func NewPayload(email string, duration time.Duration) (*Payload, error) {
 
 	payload := &Payload{
 		ID:        tokenID,
+		UserID:    userID,
 		Email:     email,
 		IssuedAt:  time.Now(),
 		ExpiredAt: time.Now().Add(duration),
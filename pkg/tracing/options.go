@@ -0,0 +1,55 @@
+package tracing
+
+import "io"
+
+// Option is a functional option for configuring a Provider.
+type Option func(*Provider)
+
+// WithExporter sets the exporter of the spans, none, stdout or otlp.
+func WithExporter(exporter string) Option {
+	return func(p *Provider) {
+		p.exporter = exporter
+	}
+}
+
+// WithServiceName sets the name of the service in the spans.
+func WithServiceName(serviceName string) Option {
+	return func(p *Provider) {
+		p.serviceName = serviceName
+	}
+}
+
+// WithServiceVersion sets the version of the service in the spans.
+func WithServiceVersion(serviceVersion string) Option {
+	return func(p *Provider) {
+		p.serviceVersion = serviceVersion
+	}
+}
+
+// WithOTLPEndpoint sets the host and port of the OTLP collector.
+func WithOTLPEndpoint(endpoint string) Option {
+	return func(p *Provider) {
+		p.otlpEndpoint = endpoint
+	}
+}
+
+// WithOTLPInsecure makes the OTLP exporter use http instead of https.
+func WithOTLPInsecure(insecure bool) Option {
+	return func(p *Provider) {
+		p.otlpInsecure = insecure
+	}
+}
+
+// WithSampleRatio sets the ratio of the sampled traces, the sampling decision of the caller is respected.
+func WithSampleRatio(sampleRatio float64) Option {
+	return func(p *Provider) {
+		p.sampleRatio = sampleRatio
+	}
+}
+
+// WithWriter sets the writer of the stdout exporter.
+func WithWriter(writer io.Writer) Option {
+	return func(p *Provider) {
+		p.writer = writer
+	}
+}
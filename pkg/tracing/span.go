@@ -0,0 +1,21 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Start starts a span with tracer. When the span is not recorded, for example when the tracing is off,
+// ctx is returned as is: the span would only repeat the span context ctx already has.
+func Start(ctx context.Context, tracer trace.Tracer, name string, opts ...trace.SpanStartOption) (
+	context.Context,
+	trace.Span,
+) {
+	spanCtx, span := tracer.Start(ctx, name, opts...)
+	if !span.IsRecording() {
+		return ctx, span
+	}
+
+	return spanCtx, span
+}
@@ -0,0 +1,136 @@
+// Package tracing configures the OpenTelemetry tracing.
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+)
+
+const (
+	// ExporterNone exports no spans, the trace context of the incoming requests is still propagated.
+	ExporterNone = "none"
+	// ExporterStdout writes the spans as json.
+	ExporterStdout = "stdout"
+	// ExporterOTLP sends the spans to an OTLP collector over http.
+	ExporterOTLP = "otlp"
+)
+
+const (
+	defaultExporter     = ExporterNone
+	defaultServiceName  = "conduit"
+	defaultOTLPEndpoint = "localhost:4318"
+	defaultSampleRatio  = 1
+)
+
+// ErrUnknownExporter is returned when the exporter is not none, stdout or otlp.
+var ErrUnknownExporter = errors.New("unknown exporter")
+
+// Provider is the tracer provider of the application.
+type Provider struct {
+	exporter       string
+	serviceName    string
+	serviceVersion string
+	otlpEndpoint   string
+	otlpInsecure   bool
+	sampleRatio    float64
+	writer         io.Writer
+	provider       *sdktrace.TracerProvider
+}
+
+// New creates the tracer provider with the configured exporter and sets it as the global one together with
+// the W3C trace context propagator.
+func New(ctx context.Context, opts ...Option) (*Provider, error) {
+	p := &Provider{
+		exporter:     defaultExporter,
+		serviceName:  defaultServiceName,
+		otlpEndpoint: defaultOTLPEndpoint,
+		sampleRatio:  defaultSampleRatio,
+		writer:       os.Stdout,
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	if p.exporter == ExporterNone {
+		return p, nil
+	}
+
+	exporter, err := p.newExporter(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceNameKey.String(p.serviceName),
+		semconv.ServiceVersionKey.String(p.serviceVersion),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("can not create tracing resource: %w", err)
+	}
+
+	p.provider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(p.sampleRatio))),
+	)
+	otel.SetTracerProvider(p.provider)
+
+	return p, nil
+}
+
+func (p *Provider) newExporter(ctx context.Context) (sdktrace.SpanExporter, error) {
+	switch p.exporter {
+	case ExporterStdout:
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(p.writer))
+		if err != nil {
+			return nil, fmt.Errorf("can not create stdout exporter: %w", err)
+		}
+
+		return exporter, nil
+	case ExporterOTLP:
+		opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(p.otlpEndpoint)}
+		if p.otlpInsecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+
+		exporter, err := otlptracehttp.New(ctx, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("can not create otlp exporter: %w", err)
+		}
+
+		return exporter, nil
+	default:
+		return nil, fmt.Errorf("can not create exporter %q: %w", p.exporter, ErrUnknownExporter)
+	}
+}
+
+// Shutdown exports the buffered spans and stops the provider.
+func (p *Provider) Shutdown(ctx context.Context) error {
+	if p.provider == nil {
+		return nil
+	}
+
+	if err := p.provider.Shutdown(ctx); err != nil {
+		return fmt.Errorf("can not shutdown tracer provider: %w", err)
+	}
+
+	return nil
+}
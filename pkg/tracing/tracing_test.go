@@ -0,0 +1,63 @@
+package tracing_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/maypok86/conduit/pkg/tracing"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// The tests set the global tracer provider, so they do not run in parallel.
+//
+//nolint:paralleltest
+func TestNew(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := tracing.New(ctx, tracing.WithExporter("zipkin"))
+	require.ErrorIs(t, err, tracing.ErrUnknownExporter)
+
+	var buf bytes.Buffer
+	provider, err := tracing.New(
+		ctx,
+		tracing.WithExporter(tracing.ExporterStdout),
+		tracing.WithServiceVersion("v1.0.0"),
+		tracing.WithWriter(&buf),
+	)
+	require.NoError(t, err)
+
+	spanCtx, span := tracing.Start(ctx, otel.Tracer("test"), "test.span")
+	require.True(t, span.IsRecording())
+	require.True(t, trace.SpanContextFromContext(spanCtx).IsValid())
+	span.End()
+
+	require.NoError(t, provider.Shutdown(ctx))
+	require.Contains(t, buf.String(), `"Name":"test.span"`)
+	require.Contains(t, buf.String(), `"v1.0.0"`)
+}
+
+//nolint:paralleltest
+func TestNew_None(t *testing.T) {
+	ctx := context.Background()
+
+	provider, err := tracing.New(ctx)
+	require.NoError(t, err)
+	require.NoError(t, provider.Shutdown(ctx))
+
+	// The trace context of the caller is propagated even when the spans are not exported.
+	carrier := propagation.HeaderCarrier{}
+	carrier.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	remoteCtx := otel.GetTextMapPropagator().Extract(ctx, carrier)
+	require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", trace.SpanContextFromContext(remoteCtx).TraceID().String())
+
+	spanCtx, span := tracing.Start(remoteCtx, trace.NewNoopTracerProvider().Tracer("test"), "test.span")
+	defer span.End()
+
+	require.False(t, span.IsRecording())
+	require.Equal(t, remoteCtx, spanCtx)
+}
@@ -0,0 +1,18 @@
+package worker
+
+// Option is a functional option for configuring a Pool.
+type Option func(*Pool)
+
+// WithSize sets the number of goroutines that run tasks.
+func WithSize(size int) Option {
+	return func(p *Pool) {
+		p.size = size
+	}
+}
+
+// WithQueueSize sets the number of tasks that can wait for a free goroutine.
+func WithQueueSize(queueSize int) Option {
+	return func(p *Pool) {
+		p.queueSize = queueSize
+	}
+}
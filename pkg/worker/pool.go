@@ -0,0 +1,122 @@
+// Package worker provides a pool of goroutines for running background tasks.
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+const (
+	defaultSize      = 1
+	defaultQueueSize = 100
+)
+
+var (
+	// ErrStopped is returned when a task is dispatched to a stopped pool.
+	ErrStopped = errors.New("worker pool is stopped")
+	// ErrQueueFull is returned when there is no room for a new task in the queue.
+	ErrQueueFull = errors.New("worker pool queue is full")
+)
+
+// Task is a background task. The context is canceled when the pool is stopped before the task is finished.
+type Task func(ctx context.Context)
+
+// Pool is a fixed size pool of goroutines.
+type Pool struct {
+	size      int
+	queueSize int
+	tasks     chan Task
+	mutex     sync.RWMutex
+	stopped   bool
+	wg        sync.WaitGroup
+	cancel    context.CancelFunc
+}
+
+// New creates a new Pool.
+func New(opts ...Option) *Pool {
+	pool := &Pool{
+		size:      defaultSize,
+		queueSize: defaultQueueSize,
+	}
+
+	for _, opt := range opts {
+		opt(pool)
+	}
+
+	pool.tasks = make(chan Task, pool.queueSize)
+
+	return pool
+}
+
+// Start starts the pool goroutines.
+func (p *Pool) Start(ctx context.Context) {
+	ctx, p.cancel = context.WithCancel(ctx)
+
+	p.wg.Add(p.size)
+
+	for i := 0; i < p.size; i++ {
+		go func() {
+			defer p.wg.Done()
+
+			for task := range p.tasks {
+				task(ctx)
+			}
+		}()
+	}
+}
+
+// Dispatch puts the task into the queue without blocking.
+func (p *Pool) Dispatch(task func(ctx context.Context)) error {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	if p.stopped {
+		return ErrStopped
+	}
+
+	select {
+	case p.tasks <- task:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Stop stops accepting new tasks and waits for the queued ones. If ctx is done first,
+// the running tasks are canceled.
+func (p *Pool) Stop(ctx context.Context) error {
+	p.mutex.Lock()
+	if p.stopped {
+		p.mutex.Unlock()
+
+		return nil
+	}
+
+	p.stopped = true
+	close(p.tasks)
+	p.mutex.Unlock()
+
+	done := make(chan struct{})
+
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		if p.cancel != nil {
+			p.cancel()
+		}
+
+		return nil
+	case <-ctx.Done():
+		if p.cancel != nil {
+			p.cancel()
+		}
+
+		return fmt.Errorf("failed to drain worker pool: %w", ctx.Err())
+	}
+}
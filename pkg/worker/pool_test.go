@@ -0,0 +1,65 @@
+package worker_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/maypok86/conduit/pkg/worker"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPool_Stop(t *testing.T) {
+	t.Parallel()
+
+	const tasks = 10
+
+	pool := worker.New(worker.WithSize(2), worker.WithQueueSize(tasks))
+	pool.Start(context.Background())
+
+	var done int64
+
+	for i := 0; i < tasks; i++ {
+		require.NoError(t, pool.Dispatch(func(ctx context.Context) {
+			time.Sleep(time.Millisecond)
+			atomic.AddInt64(&done, 1)
+		}))
+	}
+
+	require.NoError(t, pool.Stop(context.Background()))
+	require.Equal(t, int64(tasks), atomic.LoadInt64(&done))
+	require.ErrorIs(t, pool.Dispatch(func(ctx context.Context) {}), worker.ErrStopped)
+}
+
+func TestPool_QueueFull(t *testing.T) {
+	t.Parallel()
+
+	pool := worker.New(worker.WithQueueSize(1))
+
+	require.NoError(t, pool.Dispatch(func(ctx context.Context) {}))
+	require.ErrorIs(t, pool.Dispatch(func(ctx context.Context) {}), worker.ErrQueueFull)
+
+	pool.Start(context.Background())
+	require.NoError(t, pool.Stop(context.Background()))
+}
+
+func TestPool_StopTimeout(t *testing.T) {
+	t.Parallel()
+
+	pool := worker.New()
+	pool.Start(context.Background())
+
+	canceled := make(chan struct{})
+
+	require.NoError(t, pool.Dispatch(func(ctx context.Context) {
+		<-ctx.Done()
+		close(canceled)
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	require.ErrorIs(t, pool.Stop(ctx), context.DeadlineExceeded)
+	<-canceled
+}
@@ -4,4 +4,4 @@ set -e
 
 readonly app="$1"
 
-env $(cat .env | grep -Ev '^#' | xargs) "$app"
\ No newline at end of file
+env $(cat .env | grep -Ev '^#' | xargs) "$app" "${@:2}"
\ No newline at end of file
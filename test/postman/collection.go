@@ -0,0 +1,143 @@
+// Package postman runs the requests of a Postman collection and evaluates their test scripts.
+package postman
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Collection is a Postman collection in the v2.1 format.
+type Collection struct {
+	Info  Info   `json:"info"`
+	Items []Item `json:"item"`
+}
+
+// Info describes the collection.
+type Info struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// Item is a folder with nested items or a request with its scripts.
+type Item struct {
+	Name    string   `json:"name"`
+	Items   []Item   `json:"item"`
+	Request *Request `json:"request"`
+	Events  []Event  `json:"event"`
+}
+
+// IsFolder reports whether the item groups other items.
+func (i Item) IsFolder() bool {
+	return i.Request == nil
+}
+
+// Script returns the source of the scripts listening to the event.
+func (i Item) Script(listen string) string {
+	sources := make([]string, 0, len(i.Events))
+	for _, event := range i.Events {
+		if event.Listen == listen {
+			sources = append(sources, event.Script.Exec.String())
+		}
+	}
+
+	return strings.Join(sources, "\n")
+}
+
+// Request is the http request of an item. The url, the headers and the body may contain {{variables}}.
+type Request struct {
+	Method  string   `json:"method"`
+	Headers []Header `json:"header"`
+	Body    *Body    `json:"body"`
+	URL     URL      `json:"url"`
+}
+
+// Header is a request header.
+type Header struct {
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	Disabled bool   `json:"disabled"`
+}
+
+// Body is a request body, only the raw mode is supported.
+type Body struct {
+	Mode string `json:"mode"`
+	Raw  string `json:"raw"`
+}
+
+// URL is a request url, the collection stores it as a string or as an object with the raw url.
+type URL struct {
+	Raw string `json:"raw"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (u *URL) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &u.Raw); err == nil {
+		return nil
+	}
+
+	type url URL
+
+	var object url
+	if err := json.Unmarshal(data, &object); err != nil {
+		return fmt.Errorf("can not unmarshal url: %w", err)
+	}
+
+	*u = URL(object)
+
+	return nil
+}
+
+// Event is a script run around a request, "prerequest" or "test".
+type Event struct {
+	Listen string `json:"listen"`
+	Script Script `json:"script"`
+}
+
+// Script is the source of an event.
+type Script struct {
+	Exec Lines `json:"exec"`
+}
+
+// Lines is a script source, the collection stores it as a string or as an array of lines.
+type Lines []string
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (l *Lines) UnmarshalJSON(data []byte) error {
+	var line string
+	if err := json.Unmarshal(data, &line); err == nil {
+		*l = Lines{line}
+
+		return nil
+	}
+
+	var lines []string
+	if err := json.Unmarshal(data, &lines); err != nil {
+		return fmt.Errorf("can not unmarshal script: %w", err)
+	}
+
+	*l = lines
+
+	return nil
+}
+
+// String returns the source.
+func (l Lines) String() string {
+	return strings.Join(l, "\n")
+}
+
+// Load reads the collection from path.
+func Load(path string) (Collection, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Collection{}, fmt.Errorf("can not read collection: %w", err)
+	}
+
+	var collection Collection
+	if err := json.Unmarshal(data, &collection); err != nil {
+		return Collection{}, fmt.Errorf("can not unmarshal collection: %w", err)
+	}
+
+	return collection, nil
+}
@@ -0,0 +1,87 @@
+package postman_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/maypok86/conduit/internal/testserver"
+	"github.com/maypok86/conduit/test/postman"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const collectionPath = "../../api/Conduit.postman_collection.json"
+
+// unsupportedFolders are the folders of the RealWorld collection conduit does not conform to yet.
+var unsupportedFolders = map[string]string{
+	"Articles":                     "articles are not implemented",
+	"Articles, Favorite, Comments": "articles are not implemented",
+	"Profiles":                     "usernames are alphanumeric, the folder registers celeb_{{USERNAME}}",
+	"Tags":                         "tags are not implemented",
+}
+
+func TestMain(m *testing.M) {
+	testserver.Main(m)
+}
+
+// TestCollection replays the collection folder by folder, the requests of a folder depend on the variables
+// saved by the previous ones, so they run sequentially.
+//
+//nolint:paralleltest // the requests share the server state.
+func TestCollection(t *testing.T) {
+	collection, err := postman.Load(collectionPath)
+	require.NoError(t, err)
+
+	server := testserver.New(t)
+	suffix := strconv.FormatInt(time.Now().UnixNano(), 10)
+	runner := postman.NewRunner(server.Client(), map[string]string{
+		"APIURL":   testserver.URL(server),
+		"USERNAME": "u" + suffix,
+		"EMAIL":    "u" + suffix + "@mail.com",
+		"PASSWORD": "password",
+	})
+
+	for _, folder := range collection.Items {
+		folder := folder
+
+		t.Run(folder.Name, func(t *testing.T) {
+			if reason, ok := unsupportedFolders[folder.Name]; ok {
+				t.Skip(reason)
+			}
+
+			runItems(t, runner, folder.Items)
+		})
+	}
+}
+
+func runItems(t *testing.T, runner *postman.Runner, items []postman.Item) {
+	t.Helper()
+
+	passed, total := 0, 0
+
+	for _, item := range items {
+		item := item
+
+		t.Run(item.Name, func(t *testing.T) {
+			if item.IsFolder() {
+				runItems(t, runner, item.Items)
+
+				return
+			}
+
+			result, err := runner.Run(context.Background(), item)
+			require.NoError(t, err)
+
+			for _, assertion := range result.Assertions {
+				assert.Truef(t, assertion.Passed, "%s (status %d)", assertion.Name, result.StatusCode)
+			}
+
+			passed += len(result.Assertions) - len(result.Failed())
+			total += len(result.Assertions)
+		})
+	}
+
+	t.Logf("%d of %d assertions passed", passed, total)
+}
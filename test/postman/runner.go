@@ -0,0 +1,217 @@
+package postman
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/dop251/goja"
+)
+
+// testEvent is the event of the scripts run after the response is received.
+const testEvent = "test"
+
+// prelude adds the parts of the pm api that are built on the legacy sandbox globals.
+const prelude = `pm.response = {
+	code: responseCode.code,
+	text: function () { return responseBody; },
+	json: function () { return JSON.parse(responseBody); }
+};`
+
+var variablePattern = regexp.MustCompile(`{{\s*([\w.-]+)\s*}}`)
+
+// Assertion is a named check of a test script.
+type Assertion struct {
+	Name   string
+	Passed bool
+}
+
+// Result is the outcome of a request.
+type Result struct {
+	Name       string
+	StatusCode int
+	Assertions []Assertion
+}
+
+// Failed returns the assertions that did not pass.
+func (r Result) Failed() []Assertion {
+	failed := make([]Assertion, 0)
+	for _, assertion := range r.Assertions {
+		if !assertion.Passed {
+			failed = append(failed, assertion)
+		}
+	}
+
+	return failed
+}
+
+// Runner sends the requests of a collection and evaluates their test scripts. Variables the scripts save
+// with pm.globals are available to the following requests, so the items must be run in the collection order.
+type Runner struct {
+	client    *http.Client
+	variables map[string]string
+	globals   map[string]string
+}
+
+// NewRunner returns a new instance of Runner. The variables play the role of the Postman environment.
+func NewRunner(client *http.Client, variables map[string]string) *Runner {
+	environment := make(map[string]string, len(variables))
+	for name, value := range variables {
+		environment[name] = value
+	}
+
+	return &Runner{
+		client:    client,
+		variables: environment,
+		globals:   make(map[string]string),
+	}
+}
+
+// Run sends the request of the item and evaluates its test scripts.
+func (r *Runner) Run(ctx context.Context, item Item) (Result, error) {
+	if item.IsFolder() {
+		return Result{}, fmt.Errorf("can not run folder %s: only requests can be run", item.Name)
+	}
+
+	request, err := r.newRequest(ctx, *item.Request)
+	if err != nil {
+		return Result{}, fmt.Errorf("can not create request %s: %w", item.Name, err)
+	}
+
+	response, err := r.client.Do(request)
+	if err != nil {
+		return Result{}, fmt.Errorf("can not send request %s: %w", item.Name, err)
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return Result{}, fmt.Errorf("can not read response of %s: %w", item.Name, err)
+	}
+
+	assertions, err := r.evaluate(item.Script(testEvent), response.StatusCode, string(body))
+	if err != nil {
+		return Result{}, fmt.Errorf("can not evaluate test script of %s: %w", item.Name, err)
+	}
+
+	return Result{
+		Name:       item.Name,
+		StatusCode: response.StatusCode,
+		Assertions: assertions,
+	}, nil
+}
+
+func (r *Runner) newRequest(ctx context.Context, request Request) (*http.Request, error) {
+	var body io.Reader
+	if request.Body != nil && request.Body.Raw != "" {
+		body = strings.NewReader(r.resolve(request.Body.Raw))
+	}
+
+	httpRequest, err := http.NewRequestWithContext(ctx, request.Method, r.resolve(request.URL.Raw), body)
+	if err != nil {
+		return nil, fmt.Errorf("can not create http request: %w", err)
+	}
+
+	for _, header := range request.Headers {
+		if !header.Disabled {
+			httpRequest.Header.Add(header.Key, r.resolve(header.Value))
+		}
+	}
+
+	return httpRequest, nil
+}
+
+// resolve replaces the {{variables}} of s, the environment takes precedence over the globals like in Postman.
+// Unknown variables are left as is.
+func (r *Runner) resolve(s string) string {
+	return variablePattern.ReplaceAllStringFunc(s, func(match string) string {
+		name := variablePattern.FindStringSubmatch(match)[1]
+		if value, ok := r.variables[name]; ok {
+			return value
+		}
+
+		if value, ok := r.globals[name]; ok {
+			return value
+		}
+
+		return match
+	})
+}
+
+// evaluate runs the script in a sandbox with the legacy globals (responseBody, responseCode, tests,
+// environment, globals) and a subset of the pm api (pm.test, pm.response, pm.globals, pm.environment).
+func (r *Runner) evaluate(script string, statusCode int, body string) ([]Assertion, error) {
+	assertions := make([]Assertion, 0)
+	if strings.TrimSpace(script) == "" {
+		return assertions, nil
+	}
+
+	vm := goja.New()
+	tests := vm.NewObject()
+
+	sandbox := map[string]interface{}{
+		"responseBody": body,
+		"responseCode": map[string]interface{}{
+			"code": statusCode,
+			"name": http.StatusText(statusCode),
+		},
+		"tests":       tests,
+		"environment": r.variables,
+		"globals":     r.globals,
+		"pm": map[string]interface{}{
+			"globals":     newScope(r.globals),
+			"environment": newScope(r.variables),
+			"test": func(name string, fn goja.Callable) {
+				_, err := fn(goja.Undefined())
+				assertions = append(assertions, Assertion{Name: name, Passed: err == nil})
+			},
+		},
+	}
+	for name, value := range sandbox {
+		if err := vm.Set(name, value); err != nil {
+			return nil, fmt.Errorf("can not set %s: %w", name, err)
+		}
+	}
+
+	if _, err := vm.RunString(prelude); err != nil {
+		return nil, fmt.Errorf("can not run prelude: %w", err)
+	}
+
+	if _, err := vm.RunString(script); err != nil {
+		return nil, fmt.Errorf("can not run script: %w", err)
+	}
+
+	for _, name := range tests.Keys() {
+		assertions = append(assertions, Assertion{Name: name, Passed: tests.Get(name).ToBoolean()})
+	}
+
+	return assertions, nil
+}
+
+// newScope exposes variables like pm.globals, setting undefined or null removes the variable.
+func newScope(variables map[string]string) map[string]interface{} {
+	return map[string]interface{}{
+		"get": func(name string) interface{} {
+			if value, ok := variables[name]; ok {
+				return value
+			}
+
+			return goja.Undefined()
+		},
+		"set": func(name string, value goja.Value) {
+			if goja.IsUndefined(value) || goja.IsNull(value) {
+				delete(variables, name)
+
+				return
+			}
+
+			variables[name] = value.String()
+		},
+		"unset": func(name string) {
+			delete(variables, name)
+		},
+	}
+}